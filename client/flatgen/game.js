@@ -1,10 +1,23 @@
 // automatically generated by the FlatBuffers compiler, do not modify
 /* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
 export { BunicaEvent } from './game/bunica-event.js';
+export { Compression } from './game/compression.js';
+export { Entity } from './game/entity.js';
+export { EntityDespawned } from './game/entity-despawned.js';
+export { EntityKind } from './game/entity-kind.js';
+export { EntitySpawned } from './game/entity-spawned.js';
+export { EntityUpdated } from './game/entity-updated.js';
 export { EventKind } from './game/event-kind.js';
 export { EventList } from './game/event-list.js';
+export { IdleAction } from './game/idle-action.js';
+export { IdleWarning } from './game/idle-warning.js';
 export { KindHolder } from './game/kind-holder.js';
+export { MapRegion } from './game/map-region.js';
+export { Ping } from './game/ping.js';
 export { Player } from './game/player.js';
+export { PlayerDamaged } from './game/player-damaged.js';
+export { PlayerDied } from './game/player-died.js';
+export { PlayerFire } from './game/player-fire.js';
 export { PlayerHello } from './game/player-hello.js';
 export { PlayerHelloConfirm } from './game/player-hello-confirm.js';
 export { PlayerJoined } from './game/player-joined.js';
@@ -12,4 +25,11 @@ export { PlayerJoinedList } from './game/player-joined-list.js';
 export { PlayerMoved } from './game/player-moved.js';
 export { PlayerMovedList } from './game/player-moved-list.js';
 export { PlayerQuit } from './game/player-quit.js';
+export { PlayerRespawned } from './game/player-respawned.js';
+export { Pong } from './game/pong.js';
+export { PositionEncoding } from './game/position-encoding.js';
 export { RawEvent } from './game/raw-event.js';
+export { Rect } from './game/rect.js';
+export { TimeSync } from './game/time-sync.js';
+export { TimeSyncReply } from './game/time-sync-reply.js';
+export { WorldMap } from './game/world-map.js';
@@ -0,0 +1,7 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+export var Compression;
+(function (Compression) {
+    Compression[Compression["None"] = 0] = "None";
+    Compression[Compression["Deflate"] = 1] = "Deflate";
+})(Compression || (Compression = {}));
@@ -0,0 +1,68 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+export class EntityDespawned {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsEntityDespawned(bb, obj) {
+        return (obj || new EntityDespawned()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsEntityDespawned(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new EntityDespawned()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    ids(index) {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readInt32(this.bb.__vector(this.bb_pos + offset) + index * 4) : 0;
+    }
+    idsLength() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    idsArray() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? new Int32Array(this.bb.bytes().buffer, this.bb.bytes().byteOffset + this.bb.__vector(this.bb_pos + offset), this.bb.__vector_len(this.bb_pos + offset)) : null;
+    }
+    static startEntityDespawned(builder) {
+        builder.startObject(2);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addIds(builder, idsOffset) {
+        builder.addFieldOffset(1, idsOffset, 0);
+    }
+    /**
+    * @deprecated This Uint8Array overload will be removed in the future.
+    */
+    static createIdsVector(builder, data) {
+        builder.startVector(4, data.length, 4);
+        for (let i = data.length - 1; i >= 0; i--) {
+            builder.addInt32(data[i]);
+        }
+        return builder.endVector();
+    }
+    static startIdsVector(builder, numElems) {
+        builder.startVector(4, numElems, 4);
+    }
+    static endEntityDespawned(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createEntityDespawned(builder, kind, idsOffset) {
+        EntityDespawned.startEntityDespawned(builder);
+        EntityDespawned.addKind(builder, kind);
+        EntityDespawned.addIds(builder, idsOffset);
+        return EntityDespawned.endEntityDespawned(builder);
+    }
+}
@@ -0,0 +1,9 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+export var EntityKind;
+(function (EntityKind) {
+    EntityKind[EntityKind["Prop"] = 0] = "Prop";
+    EntityKind[EntityKind["Projectile"] = 1] = "Projectile";
+    EntityKind[EntityKind["Pickup"] = 2] = "Pickup";
+    EntityKind[EntityKind["Npc"] = 3] = "Npc";
+})(EntityKind || (EntityKind = {}));
@@ -0,0 +1,55 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { Entity } from '../../flatgen/game/entity.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+export class EntityUpdated {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsEntityUpdated(bb, obj) {
+        return (obj || new EntityUpdated()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsEntityUpdated(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new EntityUpdated()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    entities(index, obj) {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? (obj || new Entity()).__init(this.bb.__vector(this.bb_pos + offset) + index * 32, this.bb) : null;
+    }
+    entitiesLength() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    static startEntityUpdated(builder) {
+        builder.startObject(2);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addEntities(builder, entitiesOffset) {
+        builder.addFieldOffset(1, entitiesOffset, 0);
+    }
+    static startEntitiesVector(builder, numElems) {
+        builder.startVector(32, numElems, 4);
+    }
+    static endEntityUpdated(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createEntityUpdated(builder, kind, entitiesOffset) {
+        EntityUpdated.startEntityUpdated(builder);
+        EntityUpdated.addKind(builder, kind);
+        EntityUpdated.addEntities(builder, entitiesOffset);
+        return EntityUpdated.endEntityUpdated(builder);
+    }
+}
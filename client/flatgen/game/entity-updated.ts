@@ -0,0 +1,71 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { Entity } from '../../flatgen/game/entity.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+
+
+export class EntityUpdated {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):EntityUpdated {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsEntityUpdated(bb:flatbuffers.ByteBuffer, obj?:EntityUpdated):EntityUpdated {
+  return (obj || new EntityUpdated()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsEntityUpdated(bb:flatbuffers.ByteBuffer, obj?:EntityUpdated):EntityUpdated {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new EntityUpdated()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+entities(index: number, obj?:Entity):Entity|null {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? (obj || new Entity()).__init(this.bb!.__vector(this.bb_pos + offset) + index * 32, this.bb!) : null;
+}
+
+entitiesLength():number {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
+}
+
+static startEntityUpdated(builder:flatbuffers.Builder) {
+  builder.startObject(2);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addEntities(builder:flatbuffers.Builder, entitiesOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(1, entitiesOffset, 0);
+}
+
+static startEntitiesVector(builder:flatbuffers.Builder, numElems:number) {
+  builder.startVector(32, numElems, 4);
+}
+
+static endEntityUpdated(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+static createEntityUpdated(builder:flatbuffers.Builder, kind:EventKind, entitiesOffset:flatbuffers.Offset):flatbuffers.Offset {
+  EntityUpdated.startEntityUpdated(builder);
+  EntityUpdated.addKind(builder, kind);
+  EntityUpdated.addEntities(builder, entitiesOffset);
+  return EntityUpdated.endEntityUpdated(builder);
+}
+}
@@ -0,0 +1,50 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+export class Entity {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    id() {
+        return this.bb.readInt32(this.bb_pos);
+    }
+    kind() {
+        return this.bb.readUint8(this.bb_pos + 4);
+    }
+    owner() {
+        return this.bb.readInt32(this.bb_pos + 8);
+    }
+    x() {
+        return this.bb.readInt32(this.bb_pos + 12);
+    }
+    y() {
+        return this.bb.readInt32(this.bb_pos + 16);
+    }
+    vx() {
+        return this.bb.readFloat32(this.bb_pos + 20);
+    }
+    vy() {
+        return this.bb.readFloat32(this.bb_pos + 24);
+    }
+    size() {
+        return this.bb.readFloat32(this.bb_pos + 28);
+    }
+    static sizeOf() {
+        return 32;
+    }
+    static createEntity(builder, id, kind, owner, x, y, vx, vy, size) {
+        builder.prep(4, 32);
+        builder.writeFloat32(size);
+        builder.writeFloat32(vy);
+        builder.writeFloat32(vx);
+        builder.writeInt32(y);
+        builder.writeInt32(x);
+        builder.writeInt32(owner);
+        builder.pad(3);
+        builder.writeInt8(kind);
+        builder.writeInt32(id);
+        return builder.offset();
+    }
+}
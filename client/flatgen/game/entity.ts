@@ -0,0 +1,69 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EntityKind } from '../../flatgen/game/entity-kind.js';
+
+
+export class Entity {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):Entity {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+id():number {
+  return this.bb!.readInt32(this.bb_pos);
+}
+
+kind():EntityKind {
+  return this.bb!.readUint8(this.bb_pos + 4);
+}
+
+owner():number {
+  return this.bb!.readInt32(this.bb_pos + 8);
+}
+
+x():number {
+  return this.bb!.readInt32(this.bb_pos + 12);
+}
+
+y():number {
+  return this.bb!.readInt32(this.bb_pos + 16);
+}
+
+vx():number {
+  return this.bb!.readFloat32(this.bb_pos + 20);
+}
+
+vy():number {
+  return this.bb!.readFloat32(this.bb_pos + 24);
+}
+
+size():number {
+  return this.bb!.readFloat32(this.bb_pos + 28);
+}
+
+static sizeOf():number {
+  return 32;
+}
+
+static createEntity(builder:flatbuffers.Builder, id: number, kind: EntityKind, owner: number, x: number, y: number, vx: number, vy: number, size: number):flatbuffers.Offset {
+  builder.prep(4, 32);
+  builder.writeFloat32(size);
+  builder.writeFloat32(vy);
+  builder.writeFloat32(vx);
+  builder.writeInt32(y);
+  builder.writeInt32(x);
+  builder.writeInt32(owner);
+  builder.pad(3);
+  builder.writeInt8(kind);
+  builder.writeInt32(id);
+  return builder.offset();
+}
+
+}
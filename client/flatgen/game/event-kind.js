@@ -10,4 +10,17 @@ export var EventKind;
     EventKind[EventKind["PlayerHelloConfirm"] = 5] = "PlayerHelloConfirm";
     EventKind[EventKind["PlayerMovedList"] = 6] = "PlayerMovedList";
     EventKind[EventKind["PlayerMoved"] = 7] = "PlayerMoved";
+    EventKind[EventKind["WorldMap"] = 8] = "WorldMap";
+    EventKind[EventKind["TimeSync"] = 9] = "TimeSync";
+    EventKind[EventKind["TimeSyncReply"] = 10] = "TimeSyncReply";
+    EventKind[EventKind["Ping"] = 11] = "Ping";
+    EventKind[EventKind["Pong"] = 12] = "Pong";
+    EventKind[EventKind["IdleWarning"] = 13] = "IdleWarning";
+    EventKind[EventKind["EntitySpawned"] = 14] = "EntitySpawned";
+    EventKind[EventKind["EntityUpdated"] = 15] = "EntityUpdated";
+    EventKind[EventKind["EntityDespawned"] = 16] = "EntityDespawned";
+    EventKind[EventKind["PlayerFire"] = 17] = "PlayerFire";
+    EventKind[EventKind["PlayerDamaged"] = 18] = "PlayerDamaged";
+    EventKind[EventKind["PlayerDied"] = 19] = "PlayerDied";
+    EventKind[EventKind["PlayerRespawned"] = 20] = "PlayerRespawned";
 })(EventKind || (EventKind = {}));
@@ -10,5 +10,18 @@ export enum EventKind {
   PlayerJoinedList = 4,
   PlayerHelloConfirm = 5,
   PlayerMovedList = 6,
-  PlayerMoved = 7
+  PlayerMoved = 7,
+  WorldMap = 8,
+  TimeSync = 9,
+  TimeSyncReply = 10,
+  Ping = 11,
+  Pong = 12,
+  IdleWarning = 13,
+  EntitySpawned = 14,
+  EntityUpdated = 15,
+  EntityDespawned = 16,
+  PlayerFire = 17,
+  PlayerDamaged = 18,
+  PlayerDied = 19,
+  PlayerRespawned = 20
 }
@@ -25,8 +25,28 @@ export class EventList {
         const offset = this.bb.__offset(this.bb_pos, 4);
         return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
     }
+    tick() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readUint32(this.bb_pos + offset) : 0;
+    }
+    serverTime() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readInt64(this.bb_pos + offset) : BigInt('0');
+    }
+    compressed(index) {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? this.bb.readUint8(this.bb.__vector(this.bb_pos + offset) + index) : 0;
+    }
+    compressedLength() {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    compressedArray() {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? new Uint8Array(this.bb.bytes().buffer, this.bb.bytes().byteOffset + this.bb.__vector(this.bb_pos + offset), this.bb.__vector_len(this.bb_pos + offset)) : null;
+    }
     static startEventList(builder) {
-        builder.startObject(1);
+        builder.startObject(4);
     }
     static addEvents(builder, eventsOffset) {
         builder.addFieldOffset(0, eventsOffset, 0);
@@ -41,6 +61,25 @@ export class EventList {
     static startEventsVector(builder, numElems) {
         builder.startVector(4, numElems, 4);
     }
+    static addTick(builder, tick) {
+        builder.addFieldInt32(1, tick, 0);
+    }
+    static addServerTime(builder, serverTime) {
+        builder.addFieldInt64(2, serverTime, BigInt('0'));
+    }
+    static addCompressed(builder, compressedOffset) {
+        builder.addFieldOffset(3, compressedOffset, 0);
+    }
+    static createCompressedVector(builder, data) {
+        builder.startVector(1, data.length, 1);
+        for (let i = data.length - 1; i >= 0; i--) {
+            builder.addInt8(data[i]);
+        }
+        return builder.endVector();
+    }
+    static startCompressedVector(builder, numElems) {
+        builder.startVector(1, numElems, 1);
+    }
     static endEventList(builder) {
         const offset = builder.endObject();
         return offset;
@@ -51,9 +90,12 @@ export class EventList {
     static finishSizePrefixedEventListBuffer(builder, offset) {
         builder.finish(offset, undefined, true);
     }
-    static createEventList(builder, eventsOffset) {
+    static createEventList(builder, eventsOffset, tick, serverTime, compressedOffset) {
         EventList.startEventList(builder);
         EventList.addEvents(builder, eventsOffset);
+        EventList.addTick(builder, tick);
+        EventList.addServerTime(builder, serverTime);
+        EventList.addCompressed(builder, compressedOffset);
         return EventList.endEventList(builder);
     }
 }
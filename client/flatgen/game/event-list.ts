@@ -35,8 +35,33 @@ eventsLength():number {
   return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
 }
 
+tick():number {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.readUint32(this.bb_pos + offset) : 0;
+}
+
+serverTime():bigint {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readInt64(this.bb_pos + offset) : BigInt('0');
+}
+
+compressed(index: number):number|null {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? this.bb!.readUint8(this.bb!.__vector(this.bb_pos + offset) + index) : 0;
+}
+
+compressedLength():number {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
+}
+
+compressedArray():Uint8Array|null {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? new Uint8Array(this.bb!.bytes().buffer, this.bb!.bytes().byteOffset + this.bb!.__vector(this.bb_pos + offset), this.bb!.__vector_len(this.bb_pos + offset)) : null;
+}
+
 static startEventList(builder:flatbuffers.Builder) {
-  builder.startObject(1);
+  builder.startObject(4);
 }
 
 static addEvents(builder:flatbuffers.Builder, eventsOffset:flatbuffers.Offset) {
@@ -55,6 +80,30 @@ static startEventsVector(builder:flatbuffers.Builder, numElems:number) {
   builder.startVector(4, numElems, 4);
 }
 
+static addTick(builder:flatbuffers.Builder, tick:number) {
+  builder.addFieldInt32(1, tick, 0);
+}
+
+static addServerTime(builder:flatbuffers.Builder, serverTime:bigint) {
+  builder.addFieldInt64(2, serverTime, BigInt('0'));
+}
+
+static addCompressed(builder:flatbuffers.Builder, compressedOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(3, compressedOffset, 0);
+}
+
+static createCompressedVector(builder:flatbuffers.Builder, data:number[]|Uint8Array):flatbuffers.Offset {
+  builder.startVector(1, data.length, 1);
+  for (let i = data.length - 1; i >= 0; i--) {
+    builder.addInt8(data[i]!);
+  }
+  return builder.endVector();
+}
+
+static startCompressedVector(builder:flatbuffers.Builder, numElems:number) {
+  builder.startVector(1, numElems, 1);
+}
+
 static endEventList(builder:flatbuffers.Builder):flatbuffers.Offset {
   const offset = builder.endObject();
   return offset;
@@ -68,9 +117,12 @@ static finishSizePrefixedEventListBuffer(builder:flatbuffers.Builder, offset:fla
   builder.finish(offset, undefined, true);
 }
 
-static createEventList(builder:flatbuffers.Builder, eventsOffset:flatbuffers.Offset):flatbuffers.Offset {
+static createEventList(builder:flatbuffers.Builder, eventsOffset:flatbuffers.Offset, tick:number, serverTime:bigint, compressedOffset:flatbuffers.Offset):flatbuffers.Offset {
   EventList.startEventList(builder);
   EventList.addEvents(builder, eventsOffset);
+  EventList.addTick(builder, tick);
+  EventList.addServerTime(builder, serverTime);
+  EventList.addCompressed(builder, compressedOffset);
   return EventList.endEventList(builder);
 }
 }
@@ -0,0 +1,7 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+export var IdleAction;
+(function (IdleAction) {
+    IdleAction[IdleAction["Spectate"] = 0] = "Spectate";
+    IdleAction[IdleAction["Kick"] = 1] = "Kick";
+})(IdleAction || (IdleAction = {}));
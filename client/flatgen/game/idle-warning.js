@@ -0,0 +1,56 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+import { IdleAction } from '../../flatgen/game/idle-action.js';
+export class IdleWarning {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsIdleWarning(bb, obj) {
+        return (obj || new IdleWarning()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsIdleWarning(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new IdleWarning()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    action() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : IdleAction.Spectate;
+    }
+    secondsLeft() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readFloat32(this.bb_pos + offset) : 0.0;
+    }
+    static startIdleWarning(builder) {
+        builder.startObject(3);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addAction(builder, action) {
+        builder.addFieldInt8(1, action, IdleAction.Spectate);
+    }
+    static addSecondsLeft(builder, secondsLeft) {
+        builder.addFieldFloat32(2, secondsLeft, 0.0);
+    }
+    static endIdleWarning(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createIdleWarning(builder, kind, action, secondsLeft) {
+        IdleWarning.startIdleWarning(builder);
+        IdleWarning.addKind(builder, kind);
+        IdleWarning.addAction(builder, action);
+        IdleWarning.addSecondsLeft(builder, secondsLeft);
+        return IdleWarning.endIdleWarning(builder);
+    }
+}
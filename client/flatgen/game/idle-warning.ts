@@ -0,0 +1,72 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EventKind } from '../../flatgen/game/event-kind.js';
+import { IdleAction } from '../../flatgen/game/idle-action.js';
+
+
+export class IdleWarning {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):IdleWarning {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsIdleWarning(bb:flatbuffers.ByteBuffer, obj?:IdleWarning):IdleWarning {
+  return (obj || new IdleWarning()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsIdleWarning(bb:flatbuffers.ByteBuffer, obj?:IdleWarning):IdleWarning {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new IdleWarning()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+action():IdleAction {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : IdleAction.Spectate;
+}
+
+secondsLeft():number {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readFloat32(this.bb_pos + offset) : 0.0;
+}
+
+static startIdleWarning(builder:flatbuffers.Builder) {
+  builder.startObject(3);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addAction(builder:flatbuffers.Builder, action:IdleAction) {
+  builder.addFieldInt8(1, action, IdleAction.Spectate);
+}
+
+static addSecondsLeft(builder:flatbuffers.Builder, secondsLeft:number) {
+  builder.addFieldFloat32(2, secondsLeft, 0.0);
+}
+
+static endIdleWarning(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+static createIdleWarning(builder:flatbuffers.Builder, kind:EventKind, action:IdleAction, secondsLeft:number):flatbuffers.Offset {
+  IdleWarning.startIdleWarning(builder);
+  IdleWarning.addKind(builder, kind);
+  IdleWarning.addAction(builder, action);
+  IdleWarning.addSecondsLeft(builder, secondsLeft);
+  return IdleWarning.endIdleWarning(builder);
+}
+}
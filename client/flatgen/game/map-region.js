@@ -0,0 +1,41 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { Rect } from '../../flatgen/game/rect.js';
+export class MapRegion {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsMapRegion(bb, obj) {
+        return (obj || new MapRegion()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsMapRegion(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new MapRegion()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    name(optionalEncoding) {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.__string(this.bb_pos + offset, optionalEncoding) : null;
+    }
+    area(obj) {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? (obj || new Rect()).__init(this.bb_pos + offset, this.bb) : null;
+    }
+    static startMapRegion(builder) {
+        builder.startObject(2);
+    }
+    static addName(builder, nameOffset) {
+        builder.addFieldOffset(0, nameOffset, 0);
+    }
+    static addArea(builder, areaOffset) {
+        builder.addFieldStruct(1, areaOffset, 0);
+    }
+    static endMapRegion(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+}
@@ -0,0 +1,47 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+export class Ping {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsPing(bb, obj) {
+        return (obj || new Ping()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsPing(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new Ping()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    seq() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readUint32(this.bb_pos + offset) : 0;
+    }
+    static startPing(builder) {
+        builder.startObject(2);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addSeq(builder, seq) {
+        builder.addFieldInt32(1, seq, 0);
+    }
+    static endPing(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createPing(builder, kind, seq) {
+        Ping.startPing(builder);
+        Ping.addKind(builder, kind);
+        Ping.addSeq(builder, seq);
+        return Ping.endPing(builder);
+    }
+}
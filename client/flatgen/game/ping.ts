@@ -0,0 +1,61 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EventKind } from '../../flatgen/game/event-kind.js';
+
+
+export class Ping {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):Ping {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsPing(bb:flatbuffers.ByteBuffer, obj?:Ping):Ping {
+  return (obj || new Ping()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsPing(bb:flatbuffers.ByteBuffer, obj?:Ping):Ping {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new Ping()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+seq():number {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.readUint32(this.bb_pos + offset) : 0;
+}
+
+static startPing(builder:flatbuffers.Builder) {
+  builder.startObject(2);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addSeq(builder:flatbuffers.Builder, seq:number) {
+  builder.addFieldInt32(1, seq, 0);
+}
+
+static endPing(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+static createPing(builder:flatbuffers.Builder, kind:EventKind, seq:number):flatbuffers.Offset {
+  Ping.startPing(builder);
+  Ping.addKind(builder, kind);
+  Ping.addSeq(builder, seq);
+  return Ping.endPing(builder);
+}
+}
@@ -0,0 +1,71 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+export class PlayerDamaged {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsPlayerDamaged(bb, obj) {
+        return (obj || new PlayerDamaged()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsPlayerDamaged(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new PlayerDamaged()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    id() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    attacker() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    damage() {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    health() {
+        const offset = this.bb.__offset(this.bb_pos, 12);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    static startPlayerDamaged(builder) {
+        builder.startObject(5);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addId(builder, id) {
+        builder.addFieldInt32(1, id, 0);
+    }
+    static addAttacker(builder, attacker) {
+        builder.addFieldInt32(2, attacker, 0);
+    }
+    static addDamage(builder, damage) {
+        builder.addFieldInt32(3, damage, 0);
+    }
+    static addHealth(builder, health) {
+        builder.addFieldInt32(4, health, 0);
+    }
+    static endPlayerDamaged(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createPlayerDamaged(builder, kind, id, attacker, damage, health) {
+        PlayerDamaged.startPlayerDamaged(builder);
+        PlayerDamaged.addKind(builder, kind);
+        PlayerDamaged.addId(builder, id);
+        PlayerDamaged.addAttacker(builder, attacker);
+        PlayerDamaged.addDamage(builder, damage);
+        PlayerDamaged.addHealth(builder, health);
+        return PlayerDamaged.endPlayerDamaged(builder);
+    }
+}
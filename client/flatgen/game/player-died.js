@@ -0,0 +1,63 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+export class PlayerDied {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsPlayerDied(bb, obj) {
+        return (obj || new PlayerDied()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsPlayerDied(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new PlayerDied()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    id() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    killer() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    respawnIn() {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? this.bb.readFloat32(this.bb_pos + offset) : 0.0;
+    }
+    static startPlayerDied(builder) {
+        builder.startObject(4);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addId(builder, id) {
+        builder.addFieldInt32(1, id, 0);
+    }
+    static addKiller(builder, killer) {
+        builder.addFieldInt32(2, killer, 0);
+    }
+    static addRespawnIn(builder, respawnIn) {
+        builder.addFieldFloat32(3, respawnIn, 0.0);
+    }
+    static endPlayerDied(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createPlayerDied(builder, kind, id, killer, respawnIn) {
+        PlayerDied.startPlayerDied(builder);
+        PlayerDied.addKind(builder, kind);
+        PlayerDied.addId(builder, id);
+        PlayerDied.addKiller(builder, killer);
+        PlayerDied.addRespawnIn(builder, respawnIn);
+        return PlayerDied.endPlayerDied(builder);
+    }
+}
@@ -0,0 +1,81 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EventKind } from '../../flatgen/game/event-kind.js';
+
+
+export class PlayerDied {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):PlayerDied {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsPlayerDied(bb:flatbuffers.ByteBuffer, obj?:PlayerDied):PlayerDied {
+  return (obj || new PlayerDied()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsPlayerDied(bb:flatbuffers.ByteBuffer, obj?:PlayerDied):PlayerDied {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new PlayerDied()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+id():number {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
+}
+
+killer():number {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
+}
+
+respawnIn():number {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? this.bb!.readFloat32(this.bb_pos + offset) : 0.0;
+}
+
+static startPlayerDied(builder:flatbuffers.Builder) {
+  builder.startObject(4);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addId(builder:flatbuffers.Builder, id:number) {
+  builder.addFieldInt32(1, id, 0);
+}
+
+static addKiller(builder:flatbuffers.Builder, killer:number) {
+  builder.addFieldInt32(2, killer, 0);
+}
+
+static addRespawnIn(builder:flatbuffers.Builder, respawnIn:number) {
+  builder.addFieldFloat32(3, respawnIn, 0.0);
+}
+
+static endPlayerDied(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+static createPlayerDied(builder:flatbuffers.Builder, kind:EventKind, id:number, killer:number, respawnIn:number):flatbuffers.Offset {
+  PlayerDied.startPlayerDied(builder);
+  PlayerDied.addKind(builder, kind);
+  PlayerDied.addId(builder, id);
+  PlayerDied.addKiller(builder, killer);
+  PlayerDied.addRespawnIn(builder, respawnIn);
+  return PlayerDied.endPlayerDied(builder);
+}
+}
@@ -0,0 +1,55 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+export class PlayerFire {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsPlayerFire(bb, obj) {
+        return (obj || new PlayerFire()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsPlayerFire(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new PlayerFire()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    dirX() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readFloat32(this.bb_pos + offset) : 0.0;
+    }
+    dirY() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readFloat32(this.bb_pos + offset) : 0.0;
+    }
+    static startPlayerFire(builder) {
+        builder.startObject(3);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addDirX(builder, dirX) {
+        builder.addFieldFloat32(1, dirX, 0.0);
+    }
+    static addDirY(builder, dirY) {
+        builder.addFieldFloat32(2, dirY, 0.0);
+    }
+    static endPlayerFire(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createPlayerFire(builder, kind, dirX, dirY) {
+        PlayerFire.startPlayerFire(builder);
+        PlayerFire.addKind(builder, kind);
+        PlayerFire.addDirX(builder, dirX);
+        PlayerFire.addDirY(builder, dirY);
+        return PlayerFire.endPlayerFire(builder);
+    }
+}
@@ -0,0 +1,71 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EventKind } from '../../flatgen/game/event-kind.js';
+
+
+export class PlayerFire {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):PlayerFire {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsPlayerFire(bb:flatbuffers.ByteBuffer, obj?:PlayerFire):PlayerFire {
+  return (obj || new PlayerFire()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsPlayerFire(bb:flatbuffers.ByteBuffer, obj?:PlayerFire):PlayerFire {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new PlayerFire()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+dirX():number {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.readFloat32(this.bb_pos + offset) : 0.0;
+}
+
+dirY():number {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readFloat32(this.bb_pos + offset) : 0.0;
+}
+
+static startPlayerFire(builder:flatbuffers.Builder) {
+  builder.startObject(3);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addDirX(builder:flatbuffers.Builder, dirX:number) {
+  builder.addFieldFloat32(1, dirX, 0.0);
+}
+
+static addDirY(builder:flatbuffers.Builder, dirY:number) {
+  builder.addFieldFloat32(2, dirY, 0.0);
+}
+
+static endPlayerFire(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+static createPlayerFire(builder:flatbuffers.Builder, kind:EventKind, dirX:number, dirY:number):flatbuffers.Offset {
+  PlayerFire.startPlayerFire(builder);
+  PlayerFire.addKind(builder, kind);
+  PlayerFire.addDirX(builder, dirX);
+  PlayerFire.addDirY(builder, dirY);
+  return PlayerFire.endPlayerFire(builder);
+}
+}
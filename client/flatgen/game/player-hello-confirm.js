@@ -1,7 +1,9 @@
 // automatically generated by the FlatBuffers compiler, do not modify
 /* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
 import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { Compression } from '../../flatgen/game/compression.js';
 import { EventKind } from '../../flatgen/game/event-kind.js';
+import { PositionEncoding } from '../../flatgen/game/position-encoding.js';
 export class PlayerHelloConfirm {
     bb = null;
     bb_pos = 0;
@@ -25,8 +27,24 @@ export class PlayerHelloConfirm {
         const offset = this.bb.__offset(this.bb_pos, 6);
         return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
     }
+    positionEncoding() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : PositionEncoding.Integer;
+    }
+    compression() {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : Compression.None;
+    }
+    profile(optionalEncoding) {
+        const offset = this.bb.__offset(this.bb_pos, 12);
+        return offset ? this.bb.__string(this.bb_pos + offset, optionalEncoding) : null;
+    }
+    dictionaryVersion() {
+        const offset = this.bb.__offset(this.bb_pos, 14);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
     static startPlayerHelloConfirm(builder) {
-        builder.startObject(2);
+        builder.startObject(6);
     }
     static addKind(builder, kind) {
         builder.addFieldInt8(0, kind, EventKind.NilEvent);
@@ -34,14 +52,30 @@ export class PlayerHelloConfirm {
     static addId(builder, id) {
         builder.addFieldInt32(1, id, 0);
     }
+    static addPositionEncoding(builder, positionEncoding) {
+        builder.addFieldInt8(2, positionEncoding, PositionEncoding.Integer);
+    }
+    static addCompression(builder, compression) {
+        builder.addFieldInt8(3, compression, Compression.None);
+    }
+    static addProfile(builder, profileOffset) {
+        builder.addFieldOffset(4, profileOffset, 0);
+    }
+    static addDictionaryVersion(builder, dictionaryVersion) {
+        builder.addFieldInt32(5, dictionaryVersion, 0);
+    }
     static endPlayerHelloConfirm(builder) {
         const offset = builder.endObject();
         return offset;
     }
-    static createPlayerHelloConfirm(builder, kind, id) {
+    static createPlayerHelloConfirm(builder, kind, id, positionEncoding, compression, profileOffset, dictionaryVersion) {
         PlayerHelloConfirm.startPlayerHelloConfirm(builder);
         PlayerHelloConfirm.addKind(builder, kind);
         PlayerHelloConfirm.addId(builder, id);
+        PlayerHelloConfirm.addPositionEncoding(builder, positionEncoding);
+        PlayerHelloConfirm.addCompression(builder, compression);
+        PlayerHelloConfirm.addProfile(builder, profileOffset);
+        PlayerHelloConfirm.addDictionaryVersion(builder, dictionaryVersion);
         return PlayerHelloConfirm.endPlayerHelloConfirm(builder);
     }
 }
@@ -4,7 +4,9 @@
 
 import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
 
+import { Compression } from '../../flatgen/game/compression.js';
 import { EventKind } from '../../flatgen/game/event-kind.js';
+import { PositionEncoding } from '../../flatgen/game/position-encoding.js';
 
 
 export class PlayerHelloConfirm {
@@ -35,8 +37,30 @@ id():number {
   return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
 }
 
+positionEncoding():PositionEncoding {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : PositionEncoding.Integer;
+}
+
+compression():Compression {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : Compression.None;
+}
+
+profile():string|null
+profile(optionalEncoding:flatbuffers.Encoding):string|Uint8Array|null
+profile(optionalEncoding?:any):string|Uint8Array|null {
+  const offset = this.bb!.__offset(this.bb_pos, 12);
+  return offset ? this.bb!.__string(this.bb_pos + offset, optionalEncoding) : null;
+}
+
+dictionaryVersion():number {
+  const offset = this.bb!.__offset(this.bb_pos, 14);
+  return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
+}
+
 static startPlayerHelloConfirm(builder:flatbuffers.Builder) {
-  builder.startObject(2);
+  builder.startObject(6);
 }
 
 static addKind(builder:flatbuffers.Builder, kind:EventKind) {
@@ -47,15 +71,35 @@ static addId(builder:flatbuffers.Builder, id:number) {
   builder.addFieldInt32(1, id, 0);
 }
 
+static addPositionEncoding(builder:flatbuffers.Builder, positionEncoding:PositionEncoding) {
+  builder.addFieldInt8(2, positionEncoding, PositionEncoding.Integer);
+}
+
+static addCompression(builder:flatbuffers.Builder, compression:Compression) {
+  builder.addFieldInt8(3, compression, Compression.None);
+}
+
+static addProfile(builder:flatbuffers.Builder, profileOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(4, profileOffset, 0);
+}
+
+static addDictionaryVersion(builder:flatbuffers.Builder, dictionaryVersion:number) {
+  builder.addFieldInt32(5, dictionaryVersion, 0);
+}
+
 static endPlayerHelloConfirm(builder:flatbuffers.Builder):flatbuffers.Offset {
   const offset = builder.endObject();
   return offset;
 }
 
-static createPlayerHelloConfirm(builder:flatbuffers.Builder, kind:EventKind, id:number):flatbuffers.Offset {
+static createPlayerHelloConfirm(builder:flatbuffers.Builder, kind:EventKind, id:number, positionEncoding:PositionEncoding, compression:Compression, profileOffset:flatbuffers.Offset, dictionaryVersion:number):flatbuffers.Offset {
   PlayerHelloConfirm.startPlayerHelloConfirm(builder);
   PlayerHelloConfirm.addKind(builder, kind);
   PlayerHelloConfirm.addId(builder, id);
+  PlayerHelloConfirm.addPositionEncoding(builder, positionEncoding);
+  PlayerHelloConfirm.addCompression(builder, compression);
+  PlayerHelloConfirm.addProfile(builder, profileOffset);
+  PlayerHelloConfirm.addDictionaryVersion(builder, dictionaryVersion);
   return PlayerHelloConfirm.endPlayerHelloConfirm(builder);
 }
 }
@@ -25,8 +25,44 @@ export class PlayerHello {
         const offset = this.bb.__offset(this.bb_pos, 6);
         return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
     }
+    positionEncodings(index) {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readUint8(this.bb.__vector(this.bb_pos + offset) + index) : 0;
+    }
+    positionEncodingsLength() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    positionEncodingsArray() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? new Uint8Array(this.bb.bytes().buffer, this.bb.bytes().byteOffset + this.bb.__vector(this.bb_pos + offset), this.bb.__vector_len(this.bb_pos + offset)) : null;
+    }
+    fixedPointScale() {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    compressions(index) {
+        const offset = this.bb.__offset(this.bb_pos, 12);
+        return offset ? this.bb.readUint8(this.bb.__vector(this.bb_pos + offset) + index) : 0;
+    }
+    compressionsLength() {
+        const offset = this.bb.__offset(this.bb_pos, 12);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    compressionsArray() {
+        const offset = this.bb.__offset(this.bb_pos, 12);
+        return offset ? new Uint8Array(this.bb.bytes().buffer, this.bb.bytes().byteOffset + this.bb.__vector(this.bb_pos + offset), this.bb.__vector_len(this.bb_pos + offset)) : null;
+    }
+    dictionaryVersion() {
+        const offset = this.bb.__offset(this.bb_pos, 14);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    profile(optionalEncoding) {
+        const offset = this.bb.__offset(this.bb_pos, 16);
+        return offset ? this.bb.__string(this.bb_pos + offset, optionalEncoding) : null;
+    }
     static startPlayerHello(builder) {
-        builder.startObject(2);
+        builder.startObject(7);
     }
     static addKind(builder, kind) {
         builder.addFieldInt8(0, kind, EventKind.NilEvent);
@@ -34,14 +70,54 @@ export class PlayerHello {
     static addId(builder, id) {
         builder.addFieldInt32(1, id, 0);
     }
+    static addPositionEncodings(builder, positionEncodingsOffset) {
+        builder.addFieldOffset(2, positionEncodingsOffset, 0);
+    }
+    static createPositionEncodingsVector(builder, data) {
+        builder.startVector(1, data.length, 1);
+        for (let i = data.length - 1; i >= 0; i--) {
+            builder.addInt8(data[i]);
+        }
+        return builder.endVector();
+    }
+    static startPositionEncodingsVector(builder, numElems) {
+        builder.startVector(1, numElems, 1);
+    }
+    static addFixedPointScale(builder, fixedPointScale) {
+        builder.addFieldInt32(3, fixedPointScale, 0);
+    }
+    static addCompressions(builder, compressionsOffset) {
+        builder.addFieldOffset(4, compressionsOffset, 0);
+    }
+    static createCompressionsVector(builder, data) {
+        builder.startVector(1, data.length, 1);
+        for (let i = data.length - 1; i >= 0; i--) {
+            builder.addInt8(data[i]);
+        }
+        return builder.endVector();
+    }
+    static startCompressionsVector(builder, numElems) {
+        builder.startVector(1, numElems, 1);
+    }
+    static addDictionaryVersion(builder, dictionaryVersion) {
+        builder.addFieldInt32(5, dictionaryVersion, 0);
+    }
+    static addProfile(builder, profileOffset) {
+        builder.addFieldOffset(6, profileOffset, 0);
+    }
     static endPlayerHello(builder) {
         const offset = builder.endObject();
         return offset;
     }
-    static createPlayerHello(builder, kind, id) {
+    static createPlayerHello(builder, kind, id, positionEncodingsOffset, fixedPointScale, compressionsOffset, dictionaryVersion, profileOffset) {
         PlayerHello.startPlayerHello(builder);
         PlayerHello.addKind(builder, kind);
         PlayerHello.addId(builder, id);
+        PlayerHello.addPositionEncodings(builder, positionEncodingsOffset);
+        PlayerHello.addFixedPointScale(builder, fixedPointScale);
+        PlayerHello.addCompressions(builder, compressionsOffset);
+        PlayerHello.addDictionaryVersion(builder, dictionaryVersion);
+        PlayerHello.addProfile(builder, profileOffset);
         return PlayerHello.endPlayerHello(builder);
     }
 }
@@ -4,7 +4,9 @@
 
 import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
 
+import { Compression } from '../../flatgen/game/compression.js';
 import { EventKind } from '../../flatgen/game/event-kind.js';
+import { PositionEncoding } from '../../flatgen/game/position-encoding.js';
 
 
 export class PlayerHello {
@@ -35,8 +37,55 @@ id():number {
   return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
 }
 
+positionEncodings(index: number):PositionEncoding|null {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readUint8(this.bb!.__vector(this.bb_pos + offset) + index) : 0;
+}
+
+positionEncodingsLength():number {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
+}
+
+positionEncodingsArray():Uint8Array|null {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? new Uint8Array(this.bb!.bytes().buffer, this.bb!.bytes().byteOffset + this.bb!.__vector(this.bb_pos + offset), this.bb!.__vector_len(this.bb_pos + offset)) : null;
+}
+
+fixedPointScale():number {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
+}
+
+compressions(index: number):Compression|null {
+  const offset = this.bb!.__offset(this.bb_pos, 12);
+  return offset ? this.bb!.readUint8(this.bb!.__vector(this.bb_pos + offset) + index) : 0;
+}
+
+compressionsLength():number {
+  const offset = this.bb!.__offset(this.bb_pos, 12);
+  return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
+}
+
+compressionsArray():Uint8Array|null {
+  const offset = this.bb!.__offset(this.bb_pos, 12);
+  return offset ? new Uint8Array(this.bb!.bytes().buffer, this.bb!.bytes().byteOffset + this.bb!.__vector(this.bb_pos + offset), this.bb!.__vector_len(this.bb_pos + offset)) : null;
+}
+
+dictionaryVersion():number {
+  const offset = this.bb!.__offset(this.bb_pos, 14);
+  return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
+}
+
+profile():string|null
+profile(optionalEncoding:flatbuffers.Encoding):string|Uint8Array|null
+profile(optionalEncoding?:any):string|Uint8Array|null {
+  const offset = this.bb!.__offset(this.bb_pos, 16);
+  return offset ? this.bb!.__string(this.bb_pos + offset, optionalEncoding) : null;
+}
+
 static startPlayerHello(builder:flatbuffers.Builder) {
-  builder.startObject(2);
+  builder.startObject(7);
 }
 
 static addKind(builder:flatbuffers.Builder, kind:EventKind) {
@@ -47,15 +96,64 @@ static addId(builder:flatbuffers.Builder, id:number) {
   builder.addFieldInt32(1, id, 0);
 }
 
+static addPositionEncodings(builder:flatbuffers.Builder, positionEncodingsOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(2, positionEncodingsOffset, 0);
+}
+
+static createPositionEncodingsVector(builder:flatbuffers.Builder, data:PositionEncoding[]):flatbuffers.Offset {
+  builder.startVector(1, data.length, 1);
+  for (let i = data.length - 1; i >= 0; i--) {
+    builder.addInt8(data[i]!);
+  }
+  return builder.endVector();
+}
+
+static startPositionEncodingsVector(builder:flatbuffers.Builder, numElems:number) {
+  builder.startVector(1, numElems, 1);
+}
+
+static addFixedPointScale(builder:flatbuffers.Builder, fixedPointScale:number) {
+  builder.addFieldInt32(3, fixedPointScale, 0);
+}
+
+static addCompressions(builder:flatbuffers.Builder, compressionsOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(4, compressionsOffset, 0);
+}
+
+static createCompressionsVector(builder:flatbuffers.Builder, data:Compression[]):flatbuffers.Offset {
+  builder.startVector(1, data.length, 1);
+  for (let i = data.length - 1; i >= 0; i--) {
+    builder.addInt8(data[i]!);
+  }
+  return builder.endVector();
+}
+
+static startCompressionsVector(builder:flatbuffers.Builder, numElems:number) {
+  builder.startVector(1, numElems, 1);
+}
+
+static addDictionaryVersion(builder:flatbuffers.Builder, dictionaryVersion:number) {
+  builder.addFieldInt32(5, dictionaryVersion, 0);
+}
+
+static addProfile(builder:flatbuffers.Builder, profileOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(6, profileOffset, 0);
+}
+
 static endPlayerHello(builder:flatbuffers.Builder):flatbuffers.Offset {
   const offset = builder.endObject();
   return offset;
 }
 
-static createPlayerHello(builder:flatbuffers.Builder, kind:EventKind, id:number):flatbuffers.Offset {
+static createPlayerHello(builder:flatbuffers.Builder, kind:EventKind, id:number, positionEncodingsOffset:flatbuffers.Offset, fixedPointScale:number, compressionsOffset:flatbuffers.Offset, dictionaryVersion:number, profileOffset:flatbuffers.Offset):flatbuffers.Offset {
   PlayerHello.startPlayerHello(builder);
   PlayerHello.addKind(builder, kind);
   PlayerHello.addId(builder, id);
+  PlayerHello.addPositionEncodings(builder, positionEncodingsOffset);
+  PlayerHello.addFixedPointScale(builder, fixedPointScale);
+  PlayerHello.addCompressions(builder, compressionsOffset);
+  PlayerHello.addDictionaryVersion(builder, dictionaryVersion);
+  PlayerHello.addProfile(builder, profileOffset);
   return PlayerHello.endPlayerHello(builder);
 }
 }
@@ -24,7 +24,7 @@ export class PlayerMovedList {
     }
     players(index, obj) {
         const offset = this.bb.__offset(this.bb_pos, 6);
-        return offset ? (obj || new Player()).__init(this.bb.__vector(this.bb_pos + offset) + index * 20, this.bb) : null;
+        return offset ? (obj || new Player()).__init(this.bb.__vector(this.bb_pos + offset) + index * 32, this.bb) : null;
     }
     playersLength() {
         const offset = this.bb.__offset(this.bb_pos, 6);
@@ -40,7 +40,7 @@ export class PlayerMovedList {
         builder.addFieldOffset(1, playersOffset, 0);
     }
     static startPlayersVector(builder, numElems) {
-        builder.startVector(20, numElems, 4);
+        builder.startVector(32, numElems, 4);
     }
     static endPlayerMovedList(builder) {
         const offset = builder.endObject();
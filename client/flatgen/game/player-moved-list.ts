@@ -33,7 +33,7 @@ kind():EventKind {
 
 players(index: number, obj?:Player):Player|null {
   const offset = this.bb!.__offset(this.bb_pos, 6);
-  return offset ? (obj || new Player()).__init(this.bb!.__vector(this.bb_pos + offset) + index * 20, this.bb!) : null;
+  return offset ? (obj || new Player()).__init(this.bb!.__vector(this.bb_pos + offset) + index * 32, this.bb!) : null;
 }
 
 playersLength():number {
@@ -54,7 +54,7 @@ static addPlayers(builder:flatbuffers.Builder, playersOffset:flatbuffers.Offset)
 }
 
 static startPlayersVector(builder:flatbuffers.Builder, numElems:number) {
-  builder.startVector(20, numElems, 4);
+  builder.startVector(32, numElems, 4);
 }
 
 static endPlayerMovedList(builder:flatbuffers.Builder):flatbuffers.Offset {
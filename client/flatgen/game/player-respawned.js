@@ -0,0 +1,49 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+import { Player } from '../../flatgen/game/player.js';
+export class PlayerRespawned {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsPlayerRespawned(bb, obj) {
+        return (obj || new PlayerRespawned()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsPlayerRespawned(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new PlayerRespawned()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    player(obj) {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? (obj || new Player()).__init(this.bb_pos + offset, this.bb) : null;
+    }
+    health() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readInt32(this.bb_pos + offset) : 0;
+    }
+    static startPlayerRespawned(builder) {
+        builder.startObject(3);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addPlayer(builder, playerOffset) {
+        builder.addFieldStruct(1, playerOffset, 0);
+    }
+    static addHealth(builder, health) {
+        builder.addFieldInt32(2, health, 0);
+    }
+    static endPlayerRespawned(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+}
@@ -0,0 +1,65 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EventKind } from '../../flatgen/game/event-kind.js';
+import { Player } from '../../flatgen/game/player.js';
+
+
+export class PlayerRespawned {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):PlayerRespawned {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsPlayerRespawned(bb:flatbuffers.ByteBuffer, obj?:PlayerRespawned):PlayerRespawned {
+  return (obj || new PlayerRespawned()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsPlayerRespawned(bb:flatbuffers.ByteBuffer, obj?:PlayerRespawned):PlayerRespawned {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new PlayerRespawned()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+player(obj?:Player):Player|null {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? (obj || new Player()).__init(this.bb_pos + offset, this.bb!) : null;
+}
+
+health():number {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readInt32(this.bb_pos + offset) : 0;
+}
+
+static startPlayerRespawned(builder:flatbuffers.Builder) {
+  builder.startObject(3);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addPlayer(builder:flatbuffers.Builder, playerOffset:flatbuffers.Offset) {
+  builder.addFieldStruct(1, playerOffset, 0);
+}
+
+static addHealth(builder:flatbuffers.Builder, health:number) {
+  builder.addFieldInt32(2, health, 0);
+}
+
+static endPlayerRespawned(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+}
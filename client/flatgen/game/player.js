@@ -19,27 +19,27 @@ export class Player {
     speed() {
         return this.bb.readInt32(this.bb_pos + 12);
     }
-    movingLeft() {
-        return !!this.bb.readInt8(this.bb_pos + 16);
+    inputX() {
+        return this.bb.readFloat32(this.bb_pos + 16);
     }
-    movingRight() {
-        return !!this.bb.readInt8(this.bb_pos + 17);
+    inputY() {
+        return this.bb.readFloat32(this.bb_pos + 20);
     }
-    movingUp() {
-        return !!this.bb.readInt8(this.bb_pos + 18);
+    vx() {
+        return this.bb.readFloat32(this.bb_pos + 24);
     }
-    movingDown() {
-        return !!this.bb.readInt8(this.bb_pos + 19);
+    vy() {
+        return this.bb.readFloat32(this.bb_pos + 28);
     }
     static sizeOf() {
-        return 20;
-    }
-    static createPlayer(builder, id, x, y, speed, moving_left, moving_right, moving_up, moving_down) {
-        builder.prep(4, 20);
-        builder.writeInt8(Number(Boolean(moving_down)));
-        builder.writeInt8(Number(Boolean(moving_up)));
-        builder.writeInt8(Number(Boolean(moving_right)));
-        builder.writeInt8(Number(Boolean(moving_left)));
+        return 32;
+    }
+    static createPlayer(builder, id, x, y, speed, input_x, input_y, vx, vy) {
+        builder.prep(4, 32);
+        builder.writeFloat32(vy);
+        builder.writeFloat32(vx);
+        builder.writeFloat32(input_y);
+        builder.writeFloat32(input_x);
         builder.writeInt32(speed);
         builder.writeInt32(y);
         builder.writeInt32(x);
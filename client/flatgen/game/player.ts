@@ -29,32 +29,32 @@ speed():number {
   return this.bb!.readInt32(this.bb_pos + 12);
 }
 
-movingLeft():boolean {
-  return !!this.bb!.readInt8(this.bb_pos + 16);
+inputX():number {
+  return this.bb!.readFloat32(this.bb_pos + 16);
 }
 
-movingRight():boolean {
-  return !!this.bb!.readInt8(this.bb_pos + 17);
+inputY():number {
+  return this.bb!.readFloat32(this.bb_pos + 20);
 }
 
-movingUp():boolean {
-  return !!this.bb!.readInt8(this.bb_pos + 18);
+vx():number {
+  return this.bb!.readFloat32(this.bb_pos + 24);
 }
 
-movingDown():boolean {
-  return !!this.bb!.readInt8(this.bb_pos + 19);
+vy():number {
+  return this.bb!.readFloat32(this.bb_pos + 28);
 }
 
 static sizeOf():number {
-  return 20;
+  return 32;
 }
 
-static createPlayer(builder:flatbuffers.Builder, id: number, x: number, y: number, speed: number, moving_left: boolean, moving_right: boolean, moving_up: boolean, moving_down: boolean):flatbuffers.Offset {
-  builder.prep(4, 20);
-  builder.writeInt8(Number(Boolean(moving_down)));
-  builder.writeInt8(Number(Boolean(moving_up)));
-  builder.writeInt8(Number(Boolean(moving_right)));
-  builder.writeInt8(Number(Boolean(moving_left)));
+static createPlayer(builder:flatbuffers.Builder, id: number, x: number, y: number, speed: number, input_x: number, input_y: number, vx: number, vy: number):flatbuffers.Offset {
+  builder.prep(4, 32);
+  builder.writeFloat32(vy);
+  builder.writeFloat32(vx);
+  builder.writeFloat32(input_y);
+  builder.writeFloat32(input_x);
   builder.writeInt32(speed);
   builder.writeInt32(y);
   builder.writeInt32(x);
@@ -0,0 +1,7 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+export var PositionEncoding;
+(function (PositionEncoding) {
+    PositionEncoding[PositionEncoding["Integer"] = 0] = "Integer";
+    PositionEncoding[PositionEncoding["FixedPoint"] = 2] = "FixedPoint";
+})(PositionEncoding || (PositionEncoding = {}));
@@ -0,0 +1,33 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+export class Rect {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    x() {
+        return this.bb.readFloat32(this.bb_pos);
+    }
+    y() {
+        return this.bb.readFloat32(this.bb_pos + 4);
+    }
+    width() {
+        return this.bb.readFloat32(this.bb_pos + 8);
+    }
+    height() {
+        return this.bb.readFloat32(this.bb_pos + 12);
+    }
+    static sizeOf() {
+        return 16;
+    }
+    static createRect(builder, x, y, width, height) {
+        builder.prep(4, 16);
+        builder.writeFloat32(height);
+        builder.writeFloat32(width);
+        builder.writeFloat32(y);
+        builder.writeFloat32(x);
+        return builder.offset();
+    }
+}
@@ -0,0 +1,81 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EventKind } from '../../flatgen/game/event-kind.js';
+
+
+export class TimeSyncReply {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):TimeSyncReply {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsTimeSyncReply(bb:flatbuffers.ByteBuffer, obj?:TimeSyncReply):TimeSyncReply {
+  return (obj || new TimeSyncReply()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsTimeSyncReply(bb:flatbuffers.ByteBuffer, obj?:TimeSyncReply):TimeSyncReply {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new TimeSyncReply()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+clientTime():number {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.readFloat64(this.bb_pos + offset) : 0.0;
+}
+
+serverTime():bigint {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readInt64(this.bb_pos + offset) : BigInt('0');
+}
+
+tick():number {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? this.bb!.readUint32(this.bb_pos + offset) : 0;
+}
+
+static startTimeSyncReply(builder:flatbuffers.Builder) {
+  builder.startObject(4);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addClientTime(builder:flatbuffers.Builder, clientTime:number) {
+  builder.addFieldFloat64(1, clientTime, 0.0);
+}
+
+static addServerTime(builder:flatbuffers.Builder, serverTime:bigint) {
+  builder.addFieldInt64(2, serverTime, BigInt('0'));
+}
+
+static addTick(builder:flatbuffers.Builder, tick:number) {
+  builder.addFieldInt32(3, tick, 0);
+}
+
+static endTimeSyncReply(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+static createTimeSyncReply(builder:flatbuffers.Builder, kind:EventKind, clientTime:number, serverTime:bigint, tick:number):flatbuffers.Offset {
+  TimeSyncReply.startTimeSyncReply(builder);
+  TimeSyncReply.addKind(builder, kind);
+  TimeSyncReply.addClientTime(builder, clientTime);
+  TimeSyncReply.addServerTime(builder, serverTime);
+  TimeSyncReply.addTick(builder, tick);
+  return TimeSyncReply.endTimeSyncReply(builder);
+}
+}
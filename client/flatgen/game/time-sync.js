@@ -0,0 +1,47 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+export class TimeSync {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsTimeSync(bb, obj) {
+        return (obj || new TimeSync()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsTimeSync(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new TimeSync()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    clientTime() {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.readFloat64(this.bb_pos + offset) : 0.0;
+    }
+    static startTimeSync(builder) {
+        builder.startObject(2);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addClientTime(builder, clientTime) {
+        builder.addFieldFloat64(1, clientTime, 0.0);
+    }
+    static endTimeSync(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createTimeSync(builder, kind, clientTime) {
+        TimeSync.startTimeSync(builder);
+        TimeSync.addKind(builder, kind);
+        TimeSync.addClientTime(builder, clientTime);
+        return TimeSync.endTimeSync(builder);
+    }
+}
@@ -0,0 +1,124 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+import { EventKind } from '../../flatgen/game/event-kind.js';
+import { MapRegion } from '../../flatgen/game/map-region.js';
+import { Rect } from '../../flatgen/game/rect.js';
+export class WorldMap {
+    bb = null;
+    bb_pos = 0;
+    __init(i, bb) {
+        this.bb_pos = i;
+        this.bb = bb;
+        return this;
+    }
+    static getRootAsWorldMap(bb, obj) {
+        return (obj || new WorldMap()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    static getSizePrefixedRootAsWorldMap(bb, obj) {
+        bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+        return (obj || new WorldMap()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+    }
+    kind() {
+        const offset = this.bb.__offset(this.bb_pos, 4);
+        return offset ? this.bb.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+    }
+    name(optionalEncoding) {
+        const offset = this.bb.__offset(this.bb_pos, 6);
+        return offset ? this.bb.__string(this.bb_pos + offset, optionalEncoding) : null;
+    }
+    width() {
+        const offset = this.bb.__offset(this.bb_pos, 8);
+        return offset ? this.bb.readFloat32(this.bb_pos + offset) : 0.0;
+    }
+    height() {
+        const offset = this.bb.__offset(this.bb_pos, 10);
+        return offset ? this.bb.readFloat32(this.bb_pos + offset) : 0.0;
+    }
+    walls(index, obj) {
+        const offset = this.bb.__offset(this.bb_pos, 12);
+        return offset ? (obj || new Rect()).__init(this.bb.__vector(this.bb_pos + offset) + index * 16, this.bb) : null;
+    }
+    wallsLength() {
+        const offset = this.bb.__offset(this.bb_pos, 12);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    spawnZones(index, obj) {
+        const offset = this.bb.__offset(this.bb_pos, 14);
+        return offset ? (obj || new MapRegion()).__init(this.bb.__indirect(this.bb.__vector(this.bb_pos + offset) + index * 4), this.bb) : null;
+    }
+    spawnZonesLength() {
+        const offset = this.bb.__offset(this.bb_pos, 14);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    regions(index, obj) {
+        const offset = this.bb.__offset(this.bb_pos, 16);
+        return offset ? (obj || new MapRegion()).__init(this.bb.__indirect(this.bb.__vector(this.bb_pos + offset) + index * 4), this.bb) : null;
+    }
+    regionsLength() {
+        const offset = this.bb.__offset(this.bb_pos, 16);
+        return offset ? this.bb.__vector_len(this.bb_pos + offset) : 0;
+    }
+    static startWorldMap(builder) {
+        builder.startObject(7);
+    }
+    static addKind(builder, kind) {
+        builder.addFieldInt8(0, kind, EventKind.NilEvent);
+    }
+    static addName(builder, nameOffset) {
+        builder.addFieldOffset(1, nameOffset, 0);
+    }
+    static addWidth(builder, width) {
+        builder.addFieldFloat32(2, width, 0.0);
+    }
+    static addHeight(builder, height) {
+        builder.addFieldFloat32(3, height, 0.0);
+    }
+    static addWalls(builder, wallsOffset) {
+        builder.addFieldOffset(4, wallsOffset, 0);
+    }
+    static startWallsVector(builder, numElems) {
+        builder.startVector(16, numElems, 4);
+    }
+    static addSpawnZones(builder, spawnZonesOffset) {
+        builder.addFieldOffset(5, spawnZonesOffset, 0);
+    }
+    static createSpawnZonesVector(builder, data) {
+        builder.startVector(4, data.length, 4);
+        for (let i = data.length - 1; i >= 0; i--) {
+            builder.addOffset(data[i]);
+        }
+        return builder.endVector();
+    }
+    static startSpawnZonesVector(builder, numElems) {
+        builder.startVector(4, numElems, 4);
+    }
+    static addRegions(builder, regionsOffset) {
+        builder.addFieldOffset(6, regionsOffset, 0);
+    }
+    static createRegionsVector(builder, data) {
+        builder.startVector(4, data.length, 4);
+        for (let i = data.length - 1; i >= 0; i--) {
+            builder.addOffset(data[i]);
+        }
+        return builder.endVector();
+    }
+    static startRegionsVector(builder, numElems) {
+        builder.startVector(4, numElems, 4);
+    }
+    static endWorldMap(builder) {
+        const offset = builder.endObject();
+        return offset;
+    }
+    static createWorldMap(builder, kind, nameOffset, width, height, wallsOffset, spawnZonesOffset, regionsOffset) {
+        WorldMap.startWorldMap(builder);
+        WorldMap.addKind(builder, kind);
+        WorldMap.addName(builder, nameOffset);
+        WorldMap.addWidth(builder, width);
+        WorldMap.addHeight(builder, height);
+        WorldMap.addWalls(builder, wallsOffset);
+        WorldMap.addSpawnZones(builder, spawnZonesOffset);
+        WorldMap.addRegions(builder, regionsOffset);
+        return WorldMap.endWorldMap(builder);
+    }
+}
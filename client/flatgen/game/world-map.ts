@@ -0,0 +1,158 @@
+// automatically generated by the FlatBuffers compiler, do not modify
+
+/* eslint-disable @typescript-eslint/no-unused-vars, @typescript-eslint/no-explicit-any, @typescript-eslint/no-non-null-assertion */
+
+import * as flatbuffers from '../../flatbuffers/flatbuffers.js';
+
+import { EventKind } from '../../flatgen/game/event-kind.js';
+import { MapRegion } from '../../flatgen/game/map-region.js';
+import { Rect } from '../../flatgen/game/rect.js';
+
+
+export class WorldMap {
+  bb: flatbuffers.ByteBuffer|null = null;
+  bb_pos = 0;
+  __init(i:number, bb:flatbuffers.ByteBuffer):WorldMap {
+  this.bb_pos = i;
+  this.bb = bb;
+  return this;
+}
+
+static getRootAsWorldMap(bb:flatbuffers.ByteBuffer, obj?:WorldMap):WorldMap {
+  return (obj || new WorldMap()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+static getSizePrefixedRootAsWorldMap(bb:flatbuffers.ByteBuffer, obj?:WorldMap):WorldMap {
+  bb.setPosition(bb.position() + flatbuffers.SIZE_PREFIX_LENGTH);
+  return (obj || new WorldMap()).__init(bb.readInt32(bb.position()) + bb.position(), bb);
+}
+
+kind():EventKind {
+  const offset = this.bb!.__offset(this.bb_pos, 4);
+  return offset ? this.bb!.readUint8(this.bb_pos + offset) : EventKind.NilEvent;
+}
+
+name():string|null
+name(optionalEncoding:flatbuffers.Encoding):string|Uint8Array|null
+name(optionalEncoding?:any):string|Uint8Array|null {
+  const offset = this.bb!.__offset(this.bb_pos, 6);
+  return offset ? this.bb!.__string(this.bb_pos + offset, optionalEncoding) : null;
+}
+
+width():number {
+  const offset = this.bb!.__offset(this.bb_pos, 8);
+  return offset ? this.bb!.readFloat32(this.bb_pos + offset) : 0.0;
+}
+
+height():number {
+  const offset = this.bb!.__offset(this.bb_pos, 10);
+  return offset ? this.bb!.readFloat32(this.bb_pos + offset) : 0.0;
+}
+
+walls(index: number, obj?:Rect):Rect|null {
+  const offset = this.bb!.__offset(this.bb_pos, 12);
+  return offset ? (obj || new Rect()).__init(this.bb!.__vector(this.bb_pos + offset) + index * 16, this.bb!) : null;
+}
+
+wallsLength():number {
+  const offset = this.bb!.__offset(this.bb_pos, 12);
+  return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
+}
+
+spawnZones(index: number, obj?:MapRegion):MapRegion|null {
+  const offset = this.bb!.__offset(this.bb_pos, 14);
+  return offset ? (obj || new MapRegion()).__init(this.bb!.__indirect(this.bb!.__vector(this.bb_pos + offset) + index * 4), this.bb!) : null;
+}
+
+spawnZonesLength():number {
+  const offset = this.bb!.__offset(this.bb_pos, 14);
+  return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
+}
+
+regions(index: number, obj?:MapRegion):MapRegion|null {
+  const offset = this.bb!.__offset(this.bb_pos, 16);
+  return offset ? (obj || new MapRegion()).__init(this.bb!.__indirect(this.bb!.__vector(this.bb_pos + offset) + index * 4), this.bb!) : null;
+}
+
+regionsLength():number {
+  const offset = this.bb!.__offset(this.bb_pos, 16);
+  return offset ? this.bb!.__vector_len(this.bb_pos + offset) : 0;
+}
+
+static startWorldMap(builder:flatbuffers.Builder) {
+  builder.startObject(7);
+}
+
+static addKind(builder:flatbuffers.Builder, kind:EventKind) {
+  builder.addFieldInt8(0, kind, EventKind.NilEvent);
+}
+
+static addName(builder:flatbuffers.Builder, nameOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(1, nameOffset, 0);
+}
+
+static addWidth(builder:flatbuffers.Builder, width:number) {
+  builder.addFieldFloat32(2, width, 0.0);
+}
+
+static addHeight(builder:flatbuffers.Builder, height:number) {
+  builder.addFieldFloat32(3, height, 0.0);
+}
+
+static addWalls(builder:flatbuffers.Builder, wallsOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(4, wallsOffset, 0);
+}
+
+static startWallsVector(builder:flatbuffers.Builder, numElems:number) {
+  builder.startVector(16, numElems, 4);
+}
+
+static addSpawnZones(builder:flatbuffers.Builder, spawnZonesOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(5, spawnZonesOffset, 0);
+}
+
+static createSpawnZonesVector(builder:flatbuffers.Builder, data:flatbuffers.Offset[]):flatbuffers.Offset {
+  builder.startVector(4, data.length, 4);
+  for (let i = data.length - 1; i >= 0; i--) {
+    builder.addOffset(data[i]!);
+  }
+  return builder.endVector();
+}
+
+static startSpawnZonesVector(builder:flatbuffers.Builder, numElems:number) {
+  builder.startVector(4, numElems, 4);
+}
+
+static addRegions(builder:flatbuffers.Builder, regionsOffset:flatbuffers.Offset) {
+  builder.addFieldOffset(6, regionsOffset, 0);
+}
+
+static createRegionsVector(builder:flatbuffers.Builder, data:flatbuffers.Offset[]):flatbuffers.Offset {
+  builder.startVector(4, data.length, 4);
+  for (let i = data.length - 1; i >= 0; i--) {
+    builder.addOffset(data[i]!);
+  }
+  return builder.endVector();
+}
+
+static startRegionsVector(builder:flatbuffers.Builder, numElems:number) {
+  builder.startVector(4, numElems, 4);
+}
+
+static endWorldMap(builder:flatbuffers.Builder):flatbuffers.Offset {
+  const offset = builder.endObject();
+  return offset;
+}
+
+static createWorldMap(builder:flatbuffers.Builder, kind:EventKind, nameOffset:flatbuffers.Offset, width:number, height:number, wallsOffset:flatbuffers.Offset, spawnZonesOffset:flatbuffers.Offset, regionsOffset:flatbuffers.Offset):flatbuffers.Offset {
+  WorldMap.startWorldMap(builder);
+  WorldMap.addKind(builder, kind);
+  WorldMap.addName(builder, nameOffset);
+  WorldMap.addWidth(builder, width);
+  WorldMap.addHeight(builder, height);
+  WorldMap.addWalls(builder, wallsOffset);
+  WorldMap.addSpawnZones(builder, spawnZonesOffset);
+  WorldMap.addRegions(builder, regionsOffset);
+  return WorldMap.endWorldMap(builder);
+}
+}
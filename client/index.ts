@@ -4,6 +4,13 @@ import * as flatbuffers from './flatbuffers/flatbuffers.js'
 const Port = 6969;
 const WorldWidth = 800*2;
 const WorldHeight = 600*2;
+// Other players are drawn this far in the past so there is a server update on both sides
+const InterpolationDelay = 100;
+const TimeSyncInterval = 1000;
+// The keys the browser made before the server gave them out are under "profile", they aren't used anymore
+const ProfileKey = "profileKey";
+// Same as the server's MaxHealth, every player starts with it
+const MaxHealth = 100;
 
 function min(a, b) {
     if (a < b) {
@@ -29,6 +36,71 @@ interface Player {
     MovingRight: boolean,
     MovingUp: boolean,
     MovingDown: boolean,
+    InputX: number,
+    InputY: number,
+    VX: number,
+    VY: number,
+    Snapshots: Snapshot[],
+    // Tick of the last move applied, the datagrams can come out of order
+    MoveTick: number,
+    Health: number,
+    // Dead players aren't drawn until they respawn
+    Dead: boolean,
+}
+
+interface Entity {
+    Kind: Game.EntityKind,
+    X: number,
+    Y: number,
+    VX: number,
+    VY: number,
+    Size: number,
+    Snapshots: Snapshot[],
+    // Tick of the last move applied, for the NPCs
+    MoveTick: number,
+}
+
+interface MapRect {
+    X: number,
+    Y: number,
+    Width: number,
+    Height: number,
+}
+
+interface MapRegion {
+    Name: string,
+    Area: MapRect,
+}
+
+// The map the server sends after the hello, until then the world is empty
+interface WorldMap {
+    Name: string,
+    Width: number,
+    Height: number,
+    Walls: MapRect[],
+    SpawnZones: MapRegion[],
+    Regions: MapRegion[],
+}
+
+const EntityColors = {
+    [Game.EntityKind.Prop]: 'gray',
+    [Game.EntityKind.Projectile]: 'black',
+    [Game.EntityKind.Pickup]: 'green',
+    [Game.EntityKind.Npc]: 'blue',
+}
+
+// The server expects an analog direction, so the pressed keys are turned into a vector
+// that is never longer than 1.
+function playerInput(player: Player): [number, number] {
+    let x = Number(player.MovingRight) - Number(player.MovingLeft)
+    let y = Number(player.MovingDown) - Number(player.MovingUp)
+    let length = Math.hypot(x, y)
+
+    if (length > 1) {
+        return [x/length, y/length]
+    }
+
+    return [x, y]
 }
 
 function rawBlobToKindHolder(rawEventBlob) {
@@ -76,13 +148,315 @@ function getFlatPlayerMovedList(array: Uint8Array) {
     return Game.PlayerMovedList.getRootAsPlayerMovedList(eventDataBuf);
 }
 
+function getFlatIdleWarning(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array);
+    return Game.IdleWarning.getRootAsIdleWarning(eventDataBuf);
+}
+
+function getFlatPing(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array);
+    return Game.Ping.getRootAsPing(eventDataBuf);
+}
+
+function getFlatTimeSyncReply(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array);
+    return Game.TimeSyncReply.getRootAsTimeSyncReply(eventDataBuf);
+}
+
+function getFlatEntitySpawned(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array)
+    return Game.EntitySpawned.getRootAsEntitySpawned(eventDataBuf)
+}
+
+function getFlatEntityUpdated(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array)
+    return Game.EntityUpdated.getRootAsEntityUpdated(eventDataBuf)
+}
+
+function getFlatEntityDespawned(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array)
+    return Game.EntityDespawned.getRootAsEntityDespawned(eventDataBuf)
+}
+
+function getFlatPlayerDamaged(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array)
+    return Game.PlayerDamaged.getRootAsPlayerDamaged(eventDataBuf)
+}
+
+function getFlatPlayerDied(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array)
+    return Game.PlayerDied.getRootAsPlayerDied(eventDataBuf)
+}
+
+function getFlatPlayerRespawned(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array)
+    return Game.PlayerRespawned.getRootAsPlayerRespawned(eventDataBuf)
+}
+
+function getFlatWorldMap(array: Uint8Array) {
+    let eventDataBuf = new flatbuffers.ByteBuffer(array)
+    return Game.WorldMap.getRootAsWorldMap(eventDataBuf)
+}
+
+function toMapRect(rect: Game.Rect): MapRect {
+    return { X: rect.x(), Y: rect.y(), Width: rect.width(), Height: rect.height() }
+}
+
+function toMapRegions(length: number, region: (i: number) => Game.MapRegion): MapRegion[] {
+    let regions = []
+    for (let i = 0; i < length; i++) {
+        regions.push({ Name: region(i).name(), Area: toMapRect(region(i).area()) })
+    }
+
+    return regions
+}
+
+function toWorldMap(flatWorldMap: Game.WorldMap): WorldMap {
+    let walls = []
+    for (let i = 0; i < flatWorldMap.wallsLength(); i++) {
+        walls.push(toMapRect(flatWorldMap.walls(i)))
+    }
+
+    return {
+        Name: flatWorldMap.name(),
+        Width: flatWorldMap.width(),
+        Height: flatWorldMap.height(),
+        Walls: walls,
+        SpawnZones: toMapRegions(flatWorldMap.spawnZonesLength(), (i) => flatWorldMap.spawnZones(i)),
+        Regions: toMapRegions(flatWorldMap.regionsLength(), (i) => flatWorldMap.regions(i)),
+    }
+}
+
+// Regions are drawn under everything else with their name, walls on top of them
+function drawWorldMap(ctx: CanvasRenderingContext2D, worldMap: WorldMap) {
+    ctx.fillStyle = '#f4f4f4'
+    for (const region of worldMap.Regions) {
+        ctx.fillRect(region.Area.X, region.Area.Y, region.Area.Width, region.Area.Height)
+    }
+    ctx.fillStyle = 'gray'
+    for (const region of worldMap.Regions) {
+        ctx.fillText(region.Name, region.Area.X + 4, region.Area.Y + 12)
+    }
+
+    ctx.fillStyle = '#444'
+    for (const wall of worldMap.Walls) {
+        ctx.fillRect(wall.X, wall.Y, wall.Width, wall.Height)
+    }
+}
+
+interface Snapshot {
+    T: number,
+    X: number,
+    Y: number,
+}
+
+// Linear interpolation between the two snapshots around renderTime, old snapshots are dropped
+function interpolate(snapshots: Snapshot[], renderTime: number): [number, number] {
+    while (snapshots.length > 2 && snapshots[1].T <= renderTime) {
+        snapshots.shift()
+    }
+
+    if (snapshots.length === 1 || renderTime <= snapshots[0].T) {
+        return [snapshots[0].X, snapshots[0].Y]
+    }
+
+    let [from, to] = snapshots
+    if (renderTime >= to.T) {
+        return [to.X, to.Y]
+    }
+
+    let t = (renderTime - from.T) / (to.T - from.T)
+
+    return [from.X + (to.X - from.X) * t, from.Y + (to.Y - from.Y) * t]
+}
+
+// How long the client waits for WebTransport before it falls back to the websocket
+const WebTransportTimeout = 3000;
+
+// Connection talks to the server over WebTransport when the server and the browser support it,
+// and over the websocket otherwise. On WebTransport the messages come on a stream, each after its
+// size as a 4 byte little endian integer, and the moves come in datagrams. Either way they are
+// dispatched as "message" events with a Blob, like the websocket does.
+class Connection extends EventTarget {
+    socket: WebSocket | undefined
+    writer: WritableStreamDefaultWriter<Uint8Array> | undefined
+
+    constructor() {
+        super()
+        this.connectWebTransport().catch((err) => {
+            console.log("webtransport unavailable, using the websocket", err)
+            this.connectWebSocket()
+        })
+    }
+
+    send(data: Uint8Array) {
+        if (this.writer !== undefined) {
+            let message = new Uint8Array(4 + data.length)
+            new DataView(message.buffer).setUint32(0, data.length, true)
+            message.set(data, 4)
+            this.writer.write(message)
+            return
+        }
+
+        this.socket?.send(data)
+    }
+
+    connectWebSocket() {
+        this.socket = new WebSocket("/websocket")
+        this.socket.addEventListener("open", () => this.dispatchEvent(new Event("open")))
+        this.socket.addEventListener("close", () => this.dispatchEvent(new Event("close")))
+        this.socket.addEventListener("message", (event) => this.dispatchEvent(new MessageEvent("message", { data: event.data })))
+    }
+
+    async connectWebTransport() {
+        if (typeof WebTransport === "undefined") {
+            throw new Error("the browser doesn't support WebTransport")
+        }
+
+        const response = await fetch("/webtransport/info")
+        if (!response.ok) {
+            throw new Error(`the server doesn't serve WebTransport: ${response.status}`)
+        }
+        const info = await response.json()
+        const hash = Uint8Array.from(atob(info.certificateHash), (c) => c.charCodeAt(0))
+
+        // The server has a self signed certificate, it's trusted by its hash
+        const session = new WebTransport(info.url, { serverCertificateHashes: [{ algorithm: "sha-256", value: hash }] })
+        const timeout = new Promise((_, reject) => setTimeout(() => reject(new Error("webtransport timed out")), WebTransportTimeout))
+        try {
+            await Promise.race([session.ready, timeout])
+        } catch (err) {
+            session.close()
+            throw err
+        }
+
+        const stream = await session.createBidirectionalStream()
+        this.writer = stream.writable.getWriter()
+        // The server waits for an empty message before it says hello
+        this.send(new Uint8Array(0))
+        this.dispatchEvent(new Event("open"))
+
+        this.readStream(stream.readable).catch((err) => console.log("webtransport stream closed", err))
+        this.readDatagrams(session.datagrams.readable).catch((err) => console.log("webtransport datagrams closed", err))
+        const closed = () => this.dispatchEvent(new Event("close"))
+        session.closed.then(closed, closed)
+    }
+
+    async readStream(readable: ReadableStream<Uint8Array>) {
+        const reader = readable.getReader()
+        let buffered = new Uint8Array(0)
+
+        while (true) {
+            const { value, done } = await reader.read()
+            if (done) {
+                return
+            }
+
+            let joined = new Uint8Array(buffered.length + value.length)
+            joined.set(buffered)
+            joined.set(value, buffered.length)
+            buffered = joined
+
+            while (buffered.length >= 4) {
+                const size = new DataView(buffered.buffer, buffered.byteOffset).getUint32(0, true)
+                if (buffered.length < 4 + size) {
+                    break
+                }
+
+                this.dispatchMessage(buffered.subarray(4, 4 + size))
+                buffered = buffered.subarray(4 + size)
+            }
+        }
+    }
+
+    async readDatagrams(readable: ReadableStream<Uint8Array>) {
+        const reader = readable.getReader()
+
+        while (true) {
+            const { value, done } = await reader.read()
+            if (done) {
+                return
+            }
+
+            this.dispatchMessage(value)
+        }
+    }
+
+    dispatchMessage(data: Uint8Array) {
+        this.dispatchEvent(new MessageEvent("message", { data: new Blob([data]) }))
+    }
+}
+
+// The server remembers where we left by this key, the first one it offers is kept by the browser
+function playerProfile(offered: string | null): string {
+    let profile = localStorage.getItem(ProfileKey)
+    if (profile === null && offered) {
+        profile = offered
+        localStorage.setItem(ProfileKey, profile)
+    }
+
+    return profile ?? ""
+}
+
 let maxMessageSize = 0;
 let lastMessageSize = 0;
 
 (() => {
-    const conn = new WebSocket("/websocket")
+    const conn = new Connection()
     let myID = undefined
+    // We ask for fixed point positions so players move smoothly between whole pixels
+    let positionScale = 1
+    // Estimated difference between the server clock and performance.now(), in ms
+    let clockOffset = 0
+    let rtt = 0
+    let clockSynced = false
+    // Shown while the server considers us idle, cleared by the next input
+    let idleMessage = ""
+    // Ours, the server tells everyone when a player's health changes
+    let health = MaxHealth
+    // When we respawn after dying, in performance.now() time
+    let respawnAt = 0
     let Players = new Map<Number, Player>()
+    let Entities = new Map<Number, Entity>()
+    let worldMap: WorldMap = { Name: "", Width: WorldWidth, Height: WorldHeight, Walls: [], SpawnZones: [], Regions: [] }
+
+    // Spawned and updated entities carry their whole state, it's kept as a snapshot to interpolate between
+    let setEntity = (flatEntity: Game.Entity, serverTime: number) => {
+        let entity = Entities[flatEntity.id()] ?? { Snapshots: [] }
+
+        entity.Kind = flatEntity.kind()
+        entity.X = flatEntity.x() / positionScale
+        entity.Y = flatEntity.y() / positionScale
+        entity.VX = flatEntity.vx()
+        entity.VY = flatEntity.vy()
+        entity.Size = flatEntity.size()
+        addSnapshot(entity, serverTime)
+
+        Entities[flatEntity.id()] = entity
+        // its moves may have come before it, without it they looked like a player's
+        delete Players[flatEntity.id()]
+    }
+
+    // NPCs are entities that move like players, their moves come in the PlayerMovedLists
+    let moveEntity = (entity: Entity, playerMoved: Game.Player, tick: number, serverTime: number) => {
+        if (entity.MoveTick !== undefined && tick < entity.MoveTick) {
+            return
+        }
+
+        entity.MoveTick = tick
+        entity.X = playerMoved.x() / positionScale
+        entity.Y = playerMoved.y() / positionScale
+        entity.VX = playerMoved.vx()
+        entity.VY = playerMoved.vy()
+        addSnapshot(entity, serverTime)
+    }
+
+    let addSnapshot = (entity: Entity, serverTime: number) => {
+        entity.Snapshots.push({ T: serverTime, X: entity.X, Y: entity.Y })
+        if (entity.Snapshots.length > 32) {
+            entity.Snapshots.shift()
+        }
+    }
 
     let gameCanvas = document.getElementById("canvas") as HTMLCanvasElement
 
@@ -91,23 +465,41 @@ let lastMessageSize = 0;
     let ctx = gameCanvas.getContext("2d")
 
     conn.addEventListener("open", (event) => {
-        console.log("websocket connected")
+        console.log("connected")
+        setInterval(() => {
+            if (myID === undefined) {
+                return
+            }
+            let builder = new flatbuffers.Builder(64)
+            builder.finish(Game.TimeSync.createTimeSync(builder, Game.EventKind.TimeSync, performance.now()))
+            conn.send(builder.asUint8Array())
+        }, TimeSyncInterval)
     })
 
     conn.addEventListener('close', ev => {
-        console.log("websocket disconnected")
+        console.log("disconnected")
     })
 
-    conn.addEventListener("message", (event) => {
+    conn.addEventListener("message", (event: MessageEvent) => {
         if (myID === undefined) {
             event.data.arrayBuffer().then((rawEventBlob) => {
                 let playerHello = getFlatPlayerHello(rawEventBlob)
 
                 myID = playerHello.id()
+
+                let positionEncoding = Game.PositionEncoding.Integer
+                for (let i = 0; i < playerHello.positionEncodingsLength(); i++) {
+                    if (playerHello.positionEncodings(i) === Game.PositionEncoding.FixedPoint && playerHello.fixedPointScale() > 0) {
+                        positionEncoding = Game.PositionEncoding.FixedPoint
+                        positionScale = playerHello.fixedPointScale()
+                    }
+                }
                 console.log("We got hello!", `Our id = "${myID}"`)
                 
+                // The browser can't inflate with a preset dictionary, the websocket's permessage-deflate is used instead, so it has no dictionary version
                 let builder = new flatbuffers.Builder(256)
-                let helloResponse = Game.PlayerHelloConfirm.createPlayerHelloConfirm(builder, Game.EventKind.PlayerHelloConfirm , myID)
+                let profile = builder.createString(playerProfile(playerHello.profile()))
+                let helloResponse = Game.PlayerHelloConfirm.createPlayerHelloConfirm(builder, Game.EventKind.PlayerHelloConfirm, myID, positionEncoding, Game.Compression.None, profile, 0)
                 builder.finish(helloResponse)
                 let eventData = builder.asUint8Array()
 
@@ -116,6 +508,7 @@ let lastMessageSize = 0;
         }  else {
             event.data.arrayBuffer().then((rawEventBlob) => {
                 let flatEventList = rawBlobToFlatEventList(rawEventBlob) as Game.EventList
+                let serverTime = Number(flatEventList.serverTime()) / 1000
                 
                 console.log(`Received events len=${flatEventList.eventsLength()}`)
 
@@ -132,12 +525,12 @@ let lastMessageSize = 0;
                             Players[playerJoined.player().id()] = {
                                 Id: playerJoined.player().id(),
                                 Speed: playerJoined.player().speed(),
-                                X: playerJoined.player().x(),
-                                Y: playerJoined.player().y(),
-                                MovingLeft:  playerJoined.player().movingLeft(),
-                                MovingRight:  playerJoined.player().movingRight(),
-                                MovingUp:  playerJoined.player().movingUp(),
-                                MovingDown:  playerJoined.player().movingDown()
+                                X: playerJoined.player().x() / positionScale,
+                                Y: playerJoined.player().y() / positionScale,
+                                InputX: playerJoined.player().inputX(),
+                                InputY: playerJoined.player().inputY(),
+                                VX: playerJoined.player().vx(),
+                                VY: playerJoined.player().vy()
                             }
                             break
                         case Game.EventKind.PlayerJoinedList:
@@ -151,12 +544,12 @@ let lastMessageSize = 0;
                                 Players[playerJoined.id()] = {
                                     Id: playerJoined.id(),
                                     Speed: playerJoined.speed(),
-                                    X: playerJoined.x(),
-                                    Y: playerJoined.y(),
-                                    MovingLeft:  playerJoined.movingLeft(),
-                                    MovingRight:  playerJoined.movingRight(),
-                                    MovingUp:  playerJoined.movingUp(),
-                                    MovingDown:  playerJoined.movingDown()
+                                    X: playerJoined.x() / positionScale,
+                                    Y: playerJoined.y() / positionScale,
+                                    InputX: playerJoined.inputX(),
+                                    InputY: playerJoined.inputY(),
+                                    VX: playerJoined.vx(),
+                                    VY: playerJoined.vy()
                                 }
                             }
                             break
@@ -172,20 +565,132 @@ let lastMessageSize = 0;
                             for (let i = 0; i < playerMovedList.playersLength(); i++) {
                                 const playerMoved = playerMovedList.players(i) 
     
+                                if (Entities[playerMoved.id()] !== undefined) {
+                                    moveEntity(Entities[playerMoved.id()], playerMoved, flatEventList.tick(), serverTime)
+                                    continue
+                                }
+
                                 let player = Players[playerMoved.id()]
                                 if (player === undefined) {
                                     player = {}
                                 }
-                                player.X = playerMoved.x()
-                                player.Y = playerMoved.y()
-                                player.MovingLeft = playerMoved.movingLeft()
-                                player.MovingRight = playerMoved.movingRight()
-                                player.MovingUp = playerMoved.movingUp()
-                                player.MovingDown = playerMoved.movingDown()
+                                if (player.MoveTick !== undefined && flatEventList.tick() < player.MoveTick) {
+                                    continue
+                                }
+                                player.MoveTick = flatEventList.tick()
+                                if (player.Snapshots === undefined) {
+                                    player.Snapshots = []
+                                }
+                                player.Snapshots.push({ T: serverTime, X: playerMoved.x() / positionScale, Y: playerMoved.y() / positionScale })
+                                if (player.Snapshots.length > 32) {
+                                    player.Snapshots.shift()
+                                }
+                                player.X = playerMoved.x() / positionScale
+                                player.Y = playerMoved.y() / positionScale
+                                player.InputX = playerMoved.inputX()
+                                player.InputY = playerMoved.inputY()
+                                player.VX = playerMoved.vx()
+                                player.VY = playerMoved.vy()
             
                                 Players[playerMoved.id()] = player
                             }
 
+                            break
+                        case Game.EventKind.IdleWarning:
+                            const idleWarning = getFlatIdleWarning(rawFlatEvent.rawDataArray())
+                            const action = idleWarning.action() === Game.IdleAction.Kick ? "disconnected" : "moved to spectators"
+
+                            if (idleWarning.secondsLeft() > 0) {
+                                idleMessage = `You are idle, you will be ${action} in ${Math.round(idleWarning.secondsLeft())}s`
+                            } else {
+                                idleMessage = "You are spectating, move to join again"
+                            }
+                            break
+                        case Game.EventKind.Ping:
+                            const ping = getFlatPing(rawFlatEvent.rawDataArray())
+                            let pongBuilder = new flatbuffers.Builder(32)
+                            pongBuilder.finish(Game.Pong.createPong(pongBuilder, Game.EventKind.Pong, ping.seq()))
+                            conn.send(pongBuilder.asUint8Array())
+                            break
+                        case Game.EventKind.TimeSyncReply:
+                            const timeSyncReply = getFlatTimeSyncReply(rawFlatEvent.rawDataArray())
+                            const now = performance.now()
+                            const sampleRtt = now - timeSyncReply.clientTime()
+                            const sampleOffset = Number(timeSyncReply.serverTime()) / 1000 + sampleRtt / 2 - now
+
+                            if (!clockSynced) {
+                                rtt = sampleRtt
+                                clockOffset = sampleOffset
+                                clockSynced = true
+                            } else {
+                                rtt += (sampleRtt - rtt) * 0.1
+                                clockOffset += (sampleOffset - clockOffset) * 0.1
+                            }
+                            break
+                        case Game.EventKind.EntitySpawned:
+                            const entitySpawned = getFlatEntitySpawned(rawFlatEvent.rawDataArray())
+                            for (let i = 0; i < entitySpawned.entitiesLength(); i++) {
+                                setEntity(entitySpawned.entities(i), serverTime)
+                            }
+                            break
+                        case Game.EventKind.EntityUpdated:
+                            const entityUpdated = getFlatEntityUpdated(rawFlatEvent.rawDataArray())
+                            for (let i = 0; i < entityUpdated.entitiesLength(); i++) {
+                                setEntity(entityUpdated.entities(i), serverTime)
+                            }
+                            break
+                        case Game.EventKind.EntityDespawned:
+                            const entityDespawned = getFlatEntityDespawned(rawFlatEvent.rawDataArray())
+                            for (let i = 0; i < entityDespawned.idsLength(); i++) {
+                                delete Entities[entityDespawned.ids(i)]
+                            }
+                            break
+                        case Game.EventKind.PlayerDamaged:
+                            const playerDamaged = getFlatPlayerDamaged(rawFlatEvent.rawDataArray())
+                            if (playerDamaged.id() === myID) {
+                                health = playerDamaged.health()
+                            }
+                            if (Players[playerDamaged.id()] !== undefined) {
+                                Players[playerDamaged.id()].Health = playerDamaged.health()
+                            }
+                            break
+                        case Game.EventKind.PlayerDied:
+                            const playerDied = getFlatPlayerDied(rawFlatEvent.rawDataArray())
+                            console.log("Player Died", `His id = "${playerDied.id()}", killed by "${playerDied.killer()}"`)
+                            if (playerDied.id() === myID) {
+                                respawnAt = performance.now() + playerDied.respawnIn() * 1000
+                            }
+                            if (Players[playerDied.id()] !== undefined) {
+                                Players[playerDied.id()].Dead = true
+                            }
+                            break
+                        case Game.EventKind.WorldMap:
+                            worldMap = toWorldMap(getFlatWorldMap(rawFlatEvent.rawDataArray()))
+                            gameCanvas.width = worldMap.Width
+                            gameCanvas.height = worldMap.Height
+                            console.log("World Map", `"${worldMap.Name}" ${worldMap.Width}x${worldMap.Height}, ${worldMap.Walls.length} walls`)
+                            break
+                        case Game.EventKind.PlayerRespawned:
+                            const playerRespawned = getFlatPlayerRespawned(rawFlatEvent.rawDataArray())
+                            const respawnedPlayer = playerRespawned.player()
+                            if (respawnedPlayer.id() === myID) {
+                                health = playerRespawned.health()
+                            }
+
+                            Players[respawnedPlayer.id()] = {
+                                ...Players[respawnedPlayer.id()],
+                                Speed: respawnedPlayer.speed(),
+                                X: respawnedPlayer.x() / positionScale,
+                                Y: respawnedPlayer.y() / positionScale,
+                                InputX: respawnedPlayer.inputX(),
+                                InputY: respawnedPlayer.inputY(),
+                                VX: respawnedPlayer.vx(),
+                                VY: respawnedPlayer.vy(),
+                                Health: playerRespawned.health(),
+                                Dead: false,
+                                // it didn't walk there, it's not interpolated from where it died
+                                Snapshots: [],
+                            }
                             break
                         default:
                             console.log("bogus amogus", event.data)
@@ -205,38 +710,82 @@ let lastMessageSize = 0;
 
         ctx.fillStyle = 'white'
         ctx.fillRect(0, 0, ctx.canvas.width, ctx.canvas.height)
+        drawWorldMap(ctx, worldMap)
+        if (idleMessage !== "") {
+            ctx.fillStyle = 'black'
+            ctx.fillText(idleMessage, 10, 20)
+        }
+        if (myID !== undefined) {
+            ctx.fillStyle = 'black'
+            ctx.fillText(`Health: ${health}/${MaxHealth}`, 10, 40)
+        }
+        if (respawnAt > performance.now()) {
+            ctx.fillStyle = 'black'
+            ctx.fillText(`You died, respawning in ${Math.ceil((respawnAt - performance.now()) / 1000)}s`, 10, 60)
+        }
         ctx.fillStyle = 'red'
 
         
         for (const [id, player] of Object.entries(Players)) {
-            let movedDelta = delta * player.Speed
-
-            if (player.MovingLeft && player.X-movedDelta >= 0) {
-                player.X = player.X-movedDelta
-			}
-			if (player.MovingRight && player.X+movedDelta < WorldWidth - 20) {
-				player.X = player.X+movedDelta
-                // console.log("movedDelta: ", movedDelta)
-			}
-			if (player.MovingUp && player.Y-movedDelta >= 0) {
-				player.Y = player.Y-movedDelta
-                // console.log("movedDelta: ", movedDelta)
-			}
-			if (player.MovingDown && player.Y+movedDelta < WorldHeight - 20) {
-				player.Y = player.Y+movedDelta
-                // console.log("movedDelta: ", movedDelta)
-			}
+            if (player.Dead) {
+                continue
+            }
+            if (clockSynced && player.Snapshots !== undefined && player.Snapshots.length > 0) {
+                [player.X, player.Y] = interpolate(player.Snapshots, performance.now() + clockOffset - InterpolationDelay)
+                ctx.fillRect(player.X, player.Y, 8, 8)
+                continue
+            }
+
+            // The server owns the physics, we only move players along their last known velocity
+            let newX = player.X + delta * player.VX
+            let newY = player.Y + delta * player.VY
+
+            if (newX >= 0 && newX < worldMap.Width - 8) {
+                player.X = newX
+            }
+            if (newY >= 0 && newY < worldMap.Height - 8) {
+                player.Y = newY
+            }
 
             Players[id] = player
 
             ctx.fillRect(player.X, player.Y, 8, 8)
         }
 
+        for (const [id, entity] of Object.entries(Entities)) {
+            if (clockSynced && entity.Snapshots.length > 0) {
+                [entity.X, entity.Y] = interpolate(entity.Snapshots, performance.now() + clockOffset - InterpolationDelay)
+            } else {
+                entity.X += delta * entity.VX
+                entity.Y += delta * entity.VY
+            }
+
+            ctx.fillStyle = EntityColors[entity.Kind] ?? 'black'
+            ctx.fillRect(entity.X, entity.Y, max(entity.Size, 2), max(entity.Size, 2))
+        }
+
         window.requestAnimationFrame(frame)
     }
 
+    // A click fires towards where it is, the server moves the projectile and checks what it hits
+    gameCanvas.addEventListener("mousedown", (e) => {
+        let player = Players[myID] as Player
+        if (player === undefined) {
+            return
+        }
+
+        let rect = gameCanvas.getBoundingClientRect()
+        let x = (e.clientX - rect.left) * gameCanvas.width / rect.width
+        let y = (e.clientY - rect.top) * gameCanvas.height / rect.height
+
+        let builder = new flatbuffers.Builder(64)
+        builder.finish(Game.PlayerFire.createPlayerFire(builder, Game.EventKind.PlayerFire, x - (player.X + 4), y - (player.Y + 4)))
+        conn.send(builder.asUint8Array())
+    })
+
     window.addEventListener("keydown", (e) => {
         if (!e.repeat) {
+            idleMessage = ""
             // console.log("keydown")
             switch (e.code) {
                 case "KeyW": {Players[myID].MovingUp = true} break;
@@ -247,8 +796,9 @@ let lastMessageSize = 0;
 
             let builder = new flatbuffers.Builder(256)
             let player = Players[myID] as Player
+            let [inputX, inputY] = playerInput(player)
             let flatPlayer = Game.Player.createPlayer(builder, myID, player.X, player.Y,
-                player.Speed, player.MovingLeft, player.MovingRight, player.MovingUp, player.MovingDown
+                player.Speed, inputX, inputY, player.VX, player.VY
             )
 
             Game.PlayerMoved.startPlayerMoved(builder)
@@ -274,8 +824,9 @@ let lastMessageSize = 0;
 
             let builder = new flatbuffers.Builder(256)
             let player = Players[myID] as Player
+            let [inputX, inputY] = playerInput(player)
             let flatPlayer = Game.Player.createPlayer(builder, myID, player.X, player.Y,
-                player.Speed, player.MovingLeft, player.MovingRight, player.MovingUp, player.MovingDown
+                player.Speed, inputX, inputY, player.VX, player.VY
             )
 
             Game.PlayerMoved.startPlayerMoved(builder)
@@ -2,14 +2,28 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/compression"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/physics"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/timesync"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
 	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
 	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/verify"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
 
 	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
 
@@ -20,41 +34,106 @@ import (
 var ServerFPT = 30
 var WorldWidth = float64(800 * 2)
 var WorldHeight = float64(600 * 2)
+var PlayerSize = float64(8)
+var TimeSyncInterval = 1 * time.Second
+
+// Transport is how the bots connect to the server: websocket, tcp, udp or webtransport.
+var Transport = "websocket"
+
+// ProfilesFile is where the bots keep the profile keys the server gave them, by their number,
+// so a bot army that starts again finds its bots where they left. Empty joins without profiles.
+var ProfilesFile = "bot-profiles.json"
+
+// Compression is what the bots ask the server to compress their EventLists with, when it's
+// offered in the hello.
+var Compression = flatgen.CompressionDeflate
+
+// Fire makes the bots shoot in a random direction with every move.
+var Fire = false
+
+var worldMap = world.NewEmptyMap(WorldWidth, WorldHeight)
+
+// botProfiles are the profile keys of the bots, by their number.
+type botProfiles struct {
+	mu   sync.Mutex
+	keys map[int]string
+}
+
+var profiles = &botProfiles{keys: map[int]string{}}
+
+// key returns the key the bot joins with, the one the server offered when it has none yet.
+func (bp *botProfiles) key(id int, offered string) string {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	if key, ok := bp.keys[id]; ok {
+		return key
+	}
+
+	if offered != "" {
+		bp.keys[id] = offered
+	}
+
+	return offered
+}
+
+func (bp *botProfiles) load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	return json.Unmarshal(data, &bp.keys)
+}
+
+func (bp *botProfiles) save(path string) error {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	if len(bp.keys) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(bp.keys)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
 
 func GetMoveUpEvent(builder *flatbuffers.Builder, player Player) *flatgen.PlayerMoved {
-	player.MovingUp = true
-	player.MovingDown = false
-	player.MovingLeft = false
-	player.MovingRight = false
+	player.InputX = 0
+	player.InputY = -1
 
-	return utils.NewFlatPlayerMoved(builder, player)
+	return utils.NewFlatPlayerMoved(builder, player, IntegerCodec)
 }
 func GetMoveDownEvent(builder *flatbuffers.Builder, player Player) *flatgen.PlayerMoved {
-	player.MovingUp = false
-	player.MovingDown = true
-	player.MovingLeft = false
-	player.MovingRight = false
+	player.InputX = 0
+	player.InputY = 1
 
-	return utils.NewFlatPlayerMoved(builder, player)
+	return utils.NewFlatPlayerMoved(builder, player, IntegerCodec)
 }
 func GetMoveLeftEvent(builder *flatbuffers.Builder, player Player) *flatgen.PlayerMoved {
-	player.MovingUp = false
-	player.MovingDown = false
-	player.MovingLeft = true
-	player.MovingRight = false
+	player.InputX = -1
+	player.InputY = 0
 
-	return utils.NewFlatPlayerMoved(builder, player)
+	return utils.NewFlatPlayerMoved(builder, player, IntegerCodec)
 }
 func GetMoveRightEvent(builder *flatbuffers.Builder, player Player) *flatgen.PlayerMoved {
-	player.MovingUp = false
-	player.MovingDown = false
-	player.MovingLeft = false
-	player.MovingRight = true
+	player.InputX = 1
+	player.InputY = 0
 
-	return utils.NewFlatPlayerMoved(builder, player)
+	return utils.NewFlatPlayerMoved(builder, player, IntegerCodec)
 }
 
-func GameLoop(ctx context.Context, conn *websocket.Conn, playerUpdateChan <-chan Player, Id int) {
+func GameLoop(ctx context.Context, conn transport.Conn, playerUpdateChan <-chan Player, Id int) {
 	defer func() {
 		for len(playerUpdateChan) > 0 {
 			<-playerUpdateChan
@@ -82,20 +161,7 @@ func GameLoop(ctx context.Context, conn *websocket.Conn, playerUpdateChan <-chan
 
 			delta, previousTime = time.Since(previousTime), time.Now()
 
-			movedDelta := delta.Seconds() * myPlayer.Speed
-
-			if myPlayer.MovingLeft && myPlayer.X-movedDelta >= 0 {
-				myPlayer.X = myPlayer.X - movedDelta
-			}
-			if myPlayer.MovingRight && myPlayer.X+movedDelta < WorldWidth {
-				myPlayer.X = myPlayer.X + movedDelta
-			}
-			if myPlayer.MovingUp && myPlayer.Y-movedDelta >= 0 {
-				myPlayer.Y = myPlayer.Y - movedDelta
-			}
-			if myPlayer.MovingDown && myPlayer.Y+movedDelta < WorldHeight {
-				myPlayer.Y = myPlayer.Y + movedDelta
-			}
+			physics.DefaultModel.Step(&myPlayer, worldMap, PlayerSize, delta.Seconds())
 		case <-moveTicker.C:
 			// napTime := 1000*time.Millisecond + (time.Duration(rand.Intn(600))-1000)*time.Millisecond
 			// time.Sleep(napTime)
@@ -103,25 +169,25 @@ func GameLoop(ctx context.Context, conn *websocket.Conn, playerUpdateChan <-chan
 
 			switch moveCount {
 			case 0:
-				err := conn.Write(ctx, websocket.MessageBinary, GetMoveUpEvent(builder, myPlayer).Table().Bytes)
+				err := conn.Write(ctx, GetMoveUpEvent(builder, myPlayer).Table().Bytes)
 				if err != nil {
 					// fmt.Printf("error: %s\n", err.Error())
 					return
 				}
 			case 1:
-				err := conn.Write(ctx, websocket.MessageBinary, GetMoveRightEvent(builder, myPlayer).Table().Bytes)
+				err := conn.Write(ctx, GetMoveRightEvent(builder, myPlayer).Table().Bytes)
 				if err != nil {
 					// fmt.Printf("error: %s\n", err.Error())
 					return
 				}
 			case 2:
-				err := conn.Write(ctx, websocket.MessageBinary, GetMoveDownEvent(builder, myPlayer).Table().Bytes)
+				err := conn.Write(ctx, GetMoveDownEvent(builder, myPlayer).Table().Bytes)
 				if err != nil {
 					// fmt.Printf("error: %s\n", err.Error())
 					return
 				}
 			case 3:
-				err := conn.Write(ctx, websocket.MessageBinary, GetMoveLeftEvent(builder, myPlayer).Table().Bytes)
+				err := conn.Write(ctx, GetMoveLeftEvent(builder, myPlayer).Table().Bytes)
 				if err != nil {
 					// fmt.Printf("error: %s\n", err.Error())
 					return
@@ -129,6 +195,15 @@ func GameLoop(ctx context.Context, conn *websocket.Conn, playerUpdateChan <-chan
 			}
 
 			moveCount = (moveCount + 1) % 4
+
+			if Fire {
+				angle := rand.Float64() * 2 * math.Pi
+
+				err := conn.Write(ctx, utils.NewFlatPlayerFire(flatbuffers.NewBuilder(64), math.Cos(angle), math.Sin(angle)).Table().Bytes)
+				if err != nil {
+					return
+				}
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -136,18 +211,81 @@ func GameLoop(ctx context.Context, conn *websocket.Conn, playerUpdateChan <-chan
 	}
 }
 
+// Dial connects to the server with the bots' Transport.
+func Dial(ctx context.Context) (transport.Conn, error) {
+	switch Transport {
+	case "tcp":
+		return transport.DialTCP(ctx, "localhost:6970")
+	case "udp":
+		return transport.DialUDP(ctx, "localhost:6971")
+	case "webtransport":
+		conn, err := DialWebTransport(ctx)
+		if err != nil {
+			fmt.Printf("webtransport unavailable, using the websocket: %s\n", err)
+			return DialWebsocket(ctx)
+		}
+
+		return conn, nil
+	case "websocket":
+		return DialWebsocket(ctx)
+	}
+
+	return nil, fmt.Errorf("unknown transport '%s'", Transport)
+}
+
+func DialWebsocket(ctx context.Context) (transport.Conn, error) {
+	conn, _, err := websocket.Dial(ctx, "http://localhost:6969/websocket", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetReadLimit(-1)
+
+	return transport.NewWebsocketConn(conn, "localhost:6969"), nil
+}
+
+// DialWebTransport asks the server where it serves WebTransport, like the browser does, and
+// trusts its self signed certificate by the hash it gets.
+func DialWebTransport(ctx context.Context) (transport.Conn, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:6969/webtransport/info", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the server doesn't serve webtransport: %s", response.Status)
+	}
+
+	var info struct {
+		URL             string `json:"url"`
+		CertificateHash []byte `json:"certificateHash"`
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+
+	return transport.DialWebTransport(ctx, info.URL, transport.VerifyCertificateHash(info.CertificateHash))
+}
+
 func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 	defer func() {
 		fmt.Printf("Finishing Bot %v\n", Id)
 		wg.Done()
 	}()
 
-	conn, _, err := websocket.Dial(ctx, "http://localhost:6969/websocket", nil)
+	conn, err := Dial(ctx)
 	if err != nil {
 		fmt.Printf("Bot%v error: %s\n", Id, err)
+		return
 	}
-
-	conn.SetReadLimit(-1)
+	defer conn.Close()
 
 	playerUpdateChan := make(chan Player)
 
@@ -155,7 +293,7 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 
 	builder := flatbuffers.NewBuilder(256)
 
-	_, bytes, err := conn.Read(ctx)
+	bytes, err := conn.Read(ctx)
 	if err != nil {
 		fmt.Printf("Bot%v error: %s\n", Id, err)
 		return
@@ -171,10 +309,28 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 	myId = int(playerHello.Id())
 	fmt.Printf("Bot%v Got Id: '%v'\n", Id, myId)
 
+	chosenCompression := flatgen.CompressionNone
+	for i := range playerHello.CompressionsLength() {
+		if playerHello.Compressions(i) == Compression {
+			chosenCompression = Compression
+		}
+	}
+
+	// a dictionary of another version would inflate garbage
+	if chosenCompression == flatgen.CompressionDeflate && int(playerHello.DictionaryVersion()) != compression.DictionaryVersion {
+		fmt.Printf("Bot%v: the server has the dictionary v%v, not v%v, joining without compression\n", Id, playerHello.DictionaryVersion(), compression.DictionaryVersion)
+		chosenCompression = flatgen.CompressionNone
+	}
+
 	// Confirm the hello message
-	playerHelloConfirm := utils.NewFlatPlayerHelloConfirm(builder, myId)
+	profile := ""
+	if ProfilesFile != "" {
+		profile = profiles.key(Id, string(playerHello.Profile()))
+	}
 
-	err = conn.Write(ctx, websocket.MessageBinary, playerHelloConfirm.Table().Bytes)
+	playerHelloConfirm := utils.NewFlatPlayerHelloConfirm(builder, myId, flatgen.PositionEncodingInteger, chosenCompression, compression.DictionaryVersion, profile)
+
+	err = conn.Write(ctx, playerHelloConfirm.Table().Bytes)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -182,6 +338,15 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 
 	go GameLoop(ctx, conn, playerUpdateChan, Id)
 
+	clock := timesync.NewClock()
+
+	go TimeSyncLoop(ctx, conn, clock)
+
+	decompressor := compression.NewDecompressor(compression.Dictionary())
+
+	// The moves can come in datagrams, out of order, the ones older than the last we saw are dropped
+	var moveTick uint32
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -189,13 +354,34 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 		default:
 		}
 
-		_, dataBytes, err := conn.Read(ctx)
+		dataBytes, err := conn.Read(ctx)
 		if err != nil {
 			fmt.Printf("Bot%v stop at reading: %s\n", Id, err)
 			return
 		}
 
+		if err := verify.Root(dataBytes, verify.EventList); err != nil {
+			fmt.Printf("Bot%v got a bad event list: %s\n", Id, err)
+			continue
+		}
+
 		rawEventList := flatgen.GetRootAsEventList(dataBytes, 0)
+
+		if rawEventList.CompressedLength() > 0 {
+			dataBytes, err = decompressor.Decompress(rawEventList.CompressedBytes())
+			if err != nil {
+				fmt.Printf("Bot%v got a bad compressed event list: %s\n", Id, err)
+				continue
+			}
+
+			if err := verify.Root(dataBytes, verify.EventList); err != nil {
+				fmt.Printf("Bot%v got a bad event list: %s\n", Id, err)
+				continue
+			}
+
+			rawEventList = flatgen.GetRootAsEventList(dataBytes, 0)
+		}
+
 		rawEvent := &flatgen.RawEvent{}
 
 		for i := range rawEventList.EventsLength() {
@@ -214,10 +400,14 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 				if playerJoined.Player(player).Id() == int32(myId) {
 					select {
 					case playerUpdateChan <- Player{
-						Id:    int(player.Id()),
-						X:     float64(player.X()),
-						Y:     float64(player.Y()),
-						Speed: float64(player.Speed()),
+						Id:     int(player.Id()),
+						X:      float64(player.X()),
+						Y:      float64(player.Y()),
+						Speed:  float64(player.Speed()),
+						InputX: float64(player.InputX()),
+						InputY: float64(player.InputY()),
+						VX:     float64(player.Vx()),
+						VY:     float64(player.Vy()),
 					}:
 					case <-ctx.Done():
 						return
@@ -236,10 +426,14 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 					if player.Id() == int32(myId) {
 						select {
 						case playerUpdateChan <- Player{
-							Id:    int(player.Id()),
-							X:     float64(player.X()),
-							Y:     float64(player.Y()),
-							Speed: float64(player.Speed()),
+							Id:     int(player.Id()),
+							X:      float64(player.X()),
+							Y:      float64(player.Y()),
+							Speed:  float64(player.Speed()),
+							InputX: float64(player.InputX()),
+							InputY: float64(player.InputY()),
+							VX:     float64(player.Vx()),
+							VY:     float64(player.Vy()),
 						}:
 						case <-ctx.Done():
 							return
@@ -249,19 +443,30 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 					}
 				}
 			} else if kind == flatgen.EventKindPlayerMovedList {
+				if rawEventList.Tick() < moveTick {
+					continue
+				}
+				moveTick = rawEventList.Tick()
+
 				playerMovedList := data.(*flatgen.PlayerMovedList)
 
 				player := &flatgen.Player{}
 				for i := range playerMovedList.PlayersLength() {
 					playerMovedList.Players(player, i)
 
+					// The server's position is the one the bot moves on, a delayed view of it is only
+					// for drawing the other players, which the bots don't do.
 					if player.Id() == int32(myId) {
 						select {
 						case playerUpdateChan <- Player{
-							Id:    int(player.Id()),
-							X:     float64(player.X()),
-							Y:     float64(player.Y()),
-							Speed: float64(player.Speed()),
+							Id:     int(player.Id()),
+							X:      float64(player.X()),
+							Y:      float64(player.Y()),
+							Speed:  float64(player.Speed()),
+							InputX: float64(player.InputX()),
+							InputY: float64(player.InputY()),
+							VX:     float64(player.Vx()),
+							VY:     float64(player.Vy()),
 						}:
 						case <-ctx.Done():
 							return
@@ -269,12 +474,55 @@ func RunBot(ctx context.Context, wg *sync.WaitGroup, Id int) {
 
 					}
 				}
+			} else if kind == flatgen.EventKindTimeSyncReply {
+				timeSyncReply := data.(*flatgen.TimeSyncReply)
+
+				clock.AddSample(timeSyncReply.ClientTime(), time.Now(), time.Duration(timeSyncReply.ServerTime())*time.Microsecond)
+			} else if kind == flatgen.EventKindPing {
+				ping := data.(*flatgen.Ping)
+
+				err := conn.Write(ctx, utils.NewFlatPong(flatbuffers.NewBuilder(32), ping.Seq()).Table().Bytes)
+				if err != nil {
+					fmt.Printf("Bot%v stop at pong: %s\n", Id, err)
+					return
+				}
+			}
+		}
+	}
+}
+
+// TimeSyncLoop keeps asking the server for its time so the bot clock doesn't drift.
+func TimeSyncLoop(ctx context.Context, conn transport.Conn, clock *timesync.Clock) {
+	ticker := time.NewTicker(TimeSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			timeSync := utils.NewFlatTimeSync(flatbuffers.NewBuilder(64), clock.LocalTime(time.Now()))
+
+			err := conn.Write(ctx, timeSync.Table().Bytes)
+			if err != nil {
+				return
 			}
+		case <-ctx.Done():
+			return
 		}
 	}
 }
 
 func main() {
+	flag.StringVar(&Transport, "transport", Transport, "how the bots connect: websocket, tcp, udp or webtransport")
+	flag.StringVar(&ProfilesFile, "profiles", ProfilesFile, "the file the bots keep their profile keys in, empty for no profiles")
+	flag.BoolVar(&Fire, "fire", Fire, "the bots shoot in a random direction with every move")
+	flag.Parse()
+
+	if ProfilesFile != "" {
+		if err := profiles.load(ProfilesFile); err != nil {
+			fmt.Printf("can't load the profiles: %s\n", err)
+		}
+	}
+
 	NumBots := 800
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -292,4 +540,10 @@ func main() {
 
 	fmt.Println("Finishing execution")
 	wg.Wait()
+
+	if ProfilesFile != "" {
+		if err := profiles.save(ProfilesFile); err != nil {
+			fmt.Printf("can't save the profiles: %s\n", err)
+		}
+	}
 }
@@ -0,0 +1,27 @@
+// Command train-dictionary trains the Deflate dictionary on compression.Samples and writes it
+// where pkg/compression embeds it from. Run it with `go generate ./pkg/compression`, then bump
+// compression.DictionaryVersion and add the hash it prints to the tests' dictionaryHashes.
+package main
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/compression"
+)
+
+func main() {
+	out := flag.String("out", "dictionary.bin", "file the dictionary is written to")
+	flag.Parse()
+
+	dict := compression.Train(compression.Samples(), compression.DictionarySize)
+
+	if err := os.WriteFile(*out, dict, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "can't write the dictionary: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("wrote %v bytes to %s, sha256 %x\n", len(dict), *out, sha256.Sum256(dict))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"runtime/pprof"
@@ -11,6 +12,9 @@ import (
 )
 
 func main() {
+	flag.StringVar(&server.ProfilesPath, "profiles", server.ProfilesPath, "the file the players are remembered in, empty to not remember them")
+	flag.Parse()
+
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
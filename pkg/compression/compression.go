@@ -0,0 +1,92 @@
+// Package compression compresses the EventLists the server sends. It uses deflate with a preset
+// dictionary trained on the flatbuffers of our events, the vtables, kinds and Player structs
+// repeat in every frame so even small frames shrink. Both ends have to use the same dictionary.
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxDecompressedSize bounds what a frame can decompress to, so a small malicious frame can't
+// make a client allocate a lot of memory.
+var MaxDecompressedSize = 16 << 20
+
+var ErrTooBig = errors.New("decompressed frame is too big")
+
+// Compressor compresses frames one at a time, it's not safe for concurrent use.
+type Compressor struct {
+	writer *flate.Writer
+	buf    bytes.Buffer
+}
+
+// NewCompressor returns a Compressor with the given flate level. Levels below 2 don't use the
+// dictionary at all.
+func NewCompressor(level int, dict []byte) (*Compressor, error) {
+	c := &Compressor{}
+
+	writer, err := flate.NewWriterDict(&c.buf, level, dict)
+	if err != nil {
+		return nil, fmt.Errorf("can't create compressor: %w", err)
+	}
+
+	c.writer = writer
+
+	return c, nil
+}
+
+// Compress compresses data on its own, the result is only valid until the next call.
+func (c *Compressor) Compress(data []byte) ([]byte, error) {
+	c.buf.Reset()
+	c.writer.Reset(&c.buf)
+
+	if _, err := c.writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := c.writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return c.buf.Bytes(), nil
+}
+
+// Decompressor decompresses frames made by a Compressor with the same dictionary, it's not safe
+// for concurrent use.
+type Decompressor struct {
+	dict   []byte
+	reader io.ReadCloser
+	src    bytes.Reader
+	buf    bytes.Buffer
+}
+
+func NewDecompressor(dict []byte) *Decompressor {
+	d := &Decompressor{dict: dict}
+	d.reader = flate.NewReaderDict(&d.src, dict)
+
+	return d
+}
+
+// Decompress decompresses a frame, the result is only valid until the next call.
+func (d *Decompressor) Decompress(data []byte) ([]byte, error) {
+	d.src.Reset(data)
+	d.buf.Reset()
+
+	if err := d.reader.(flate.Resetter).Reset(&d.src, d.dict); err != nil {
+		return nil, err
+	}
+
+	n, err := d.buf.ReadFrom(io.LimitReader(d.reader, int64(MaxDecompressedSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("can't decompress frame: %w", err)
+	}
+
+	if n > int64(MaxDecompressedSize) {
+		return nil, ErrTooBig
+	}
+
+	return d.buf.Bytes(), nil
+}
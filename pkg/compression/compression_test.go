@@ -0,0 +1,144 @@
+package compression_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/compression"
+)
+
+func compressedSize(t *testing.T, level int, dict []byte, samples [][]byte) int {
+	t.Helper()
+
+	compressor, err := compression.NewCompressor(level, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := 0
+
+	for _, sample := range samples {
+		compressed, err := compressor.Compress(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		total += len(compressed)
+	}
+
+	return total
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	dict := compression.Dictionary()
+
+	compressor, err := compression.NewCompressor(2, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decompressor := compression.NewDecompressor(dict)
+
+	for i, sample := range compression.Samples() {
+		compressed, err := compressor.Compress(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decompressed, err := decompressor.Decompress(compressed)
+		if err != nil {
+			t.Fatalf("sample %v: %v", i, err)
+		}
+
+		if !bytes.Equal(decompressed, sample) {
+			t.Fatalf("sample %v doesn't round trip", i)
+		}
+	}
+}
+
+func TestDictionaryIsDeterministic(t *testing.T) {
+	dict := compression.Train(compression.Samples(), compression.DictionarySize)
+
+	if !bytes.Equal(dict, compression.Train(compression.Samples(), compression.DictionarySize)) {
+		t.Fatal("training twice gives different dictionaries")
+	}
+
+	if len(dict) == 0 || len(dict) > compression.DictionarySize {
+		t.Fatalf("dictionary has %v bytes, expected at most %v", len(dict), compression.DictionarySize)
+	}
+}
+
+func TestDictionaryIsUpToDate(t *testing.T) {
+	// The dictionary is committed, so the samples changing doesn't change what the clients use.
+	// A new one needs a new version, or the clients with the old one would decompress garbage.
+	if !bytes.Equal(compression.Dictionary(), compression.Train(compression.Samples(), compression.DictionarySize)) {
+		t.Fatal("the samples changed since the dictionary was trained, bump compression.DictionaryVersion and run go generate ./pkg/compression")
+	}
+}
+
+// dictionaryHashes is the sha256 of the dictionary of every DictionaryVersion, a version is
+// never given to another dictionary.
+var dictionaryHashes = map[int]string{
+	1: "43b2c91c9a3bd87c15b4411cd103c74f4f2415926869067f951b5df42ee0ca3e",
+}
+
+func TestDictionaryMatchesItsVersion(t *testing.T) {
+	hash, ok := dictionaryHashes[compression.DictionaryVersion]
+	if !ok {
+		t.Fatalf("no hash for dictionary version %v, add the one go generate printed to dictionaryHashes", compression.DictionaryVersion)
+	}
+
+	if got := fmt.Sprintf("%x", sha256.Sum256(compression.Dictionary())); got != hash {
+		t.Fatalf("the embedded dictionary has sha256 %s, version %v is %s: a new dictionary needs a new version", got, compression.DictionaryVersion, hash)
+	}
+}
+
+func TestTrainRespectsSize(t *testing.T) {
+	for _, size := range []int{0, 100, 1000} {
+		if dict := compression.Train(compression.Samples(), size); len(dict) > size {
+			t.Fatalf("dictionary has %v bytes, expected at most %v", len(dict), size)
+		}
+	}
+}
+
+func TestDictionaryImprovesRatio(t *testing.T) {
+	samples := compression.Samples()
+
+	without := compressedSize(t, 2, nil, samples)
+	with := compressedSize(t, 2, compression.Dictionary(), samples)
+
+	if with >= without {
+		t.Fatalf("dictionary didn't help, %v bytes with it and %v without", with, without)
+	}
+}
+
+func TestDecompressRejectsTooBigFrames(t *testing.T) {
+	maxDecompressedSize := compression.MaxDecompressedSize
+	t.Cleanup(func() { compression.MaxDecompressedSize = maxDecompressedSize })
+
+	compression.MaxDecompressedSize = 1024
+
+	compressor, err := compression.NewCompressor(2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compressed, err := compressor.Compress(make([]byte, 4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = compression.NewDecompressor(nil).Decompress(compressed)
+	if !errors.Is(err, compression.ErrTooBig) {
+		t.Fatalf("expected ErrTooBig, got %v", err)
+	}
+}
+
+func TestDecompressRejectsGarbage(t *testing.T) {
+	if _, err := compression.NewDecompressor(nil).Decompress([]byte{0xff, 0xff, 0xff}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,271 @@
+package compression
+
+import (
+	"container/heap"
+	_ "embed"
+	"encoding/binary"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// DictionarySize is the size of the dictionary trained by Train, deflate can't look back
+// further than 32KB anyway.
+var DictionarySize = 8 << 10
+
+// DictionaryVersion is the version of the dictionary in dictionary.bin. The server and the
+// clients tell it to each other in PlayerHello and PlayerHelloConfirm and only use Deflate when
+// they have the same one, so it has to be bumped every time the dictionary is trained again.
+// The tests know the hash of every version's dictionary.
+const DictionaryVersion = 1
+
+const (
+	// kmerSize is the length of the substrings Train counts, segmentSize the length of the
+	// pieces of samples it copies in the dictionary.
+	kmerSize    = 8
+	segmentSize = 64
+)
+
+//go:generate go run ../../cmd/train-dictionary
+
+//go:embed dictionary.bin
+var dictionary []byte
+
+// Dictionary is the dictionary the server and the clients use for the Deflate compression. It
+// was trained on Samples once and committed, a change to the schema or the encoders doesn't
+// change it for the clients that already have it. Run `go generate ./pkg/compression`, bump
+// DictionaryVersion and add the new hash to the tests to train a new one.
+func Dictionary() []byte {
+	return dictionary
+}
+
+// Samples returns EventLists like the ones the server sends, built from a fixed seed so every
+// build trains the same dictionary.
+func Samples() [][]byte {
+	random := rand.New(rand.NewPCG(4, 2))
+	builder := flatbuffers.NewBuilder(4096)
+	worldMap := world.NewEmptyMap(1600, 1200)
+
+	codecs := []types.PositionCodec{
+		types.IntegerCodec,
+		{Encoding: flatgen.PositionEncodingFixedPoint, Scale: 100},
+	}
+
+	randomPlayers := func(count int) []types.Player {
+		players := make([]types.Player, count)
+
+		for i := range players {
+			players[i] = types.Player{
+				Id:     random.IntN(1000) + 1,
+				X:      random.Float64() * worldMap.Width,
+				Y:      random.Float64() * worldMap.Height,
+				Speed:  250,
+				InputX: float64(random.IntN(3) - 1),
+				InputY: float64(random.IntN(3) - 1),
+			}
+			players[i].VX, players[i].VY = players[i].InputX*players[i].Speed, players[i].InputY*players[i].Speed
+		}
+
+		return players
+	}
+
+	event := func(table flatbuffers.Table) []byte {
+		return slices.Clone(table.Bytes)
+	}
+
+	samples := [][]byte{}
+
+	for i := range 64 {
+		codec := codecs[i%len(codecs)]
+		events := [][]byte{}
+
+		newBuilder := func() *flatbuffers.Builder {
+			builder.Reset()
+			return builder
+		}
+
+		events = append(events, event(utils.NewFlatPlayerMovedList(newBuilder(), randomPlayers(1+random.IntN(64)), codec).Table()))
+
+		switch i % 8 {
+		case 0:
+			events = append(events, event(utils.NewFlatPlayerJoinedList(newBuilder(), randomPlayers(1+random.IntN(8)), codec).Table()))
+			events = append(events, event(utils.NewFlatPlayerJoined(newBuilder(), randomPlayers(1)[0], codec).Table()))
+		case 1:
+			events = append(events, event(utils.NewFlatPlayerQuit(newBuilder(), random.IntN(1000)).Table()))
+		case 2:
+			events = append(events, event(utils.NewFlatPing(newBuilder(), uint32(i)).Table()))
+		case 3:
+			events = append(events, event(utils.NewFlatTimeSyncReply(newBuilder(), random.Float64()*1e6, random.Int64N(1e9), uint32(i)).Table()))
+		case 4:
+			events = append(events, event(utils.NewFlatIdleWarning(newBuilder(), flatgen.IdleActionSpectate, 30).Table()))
+		case 5:
+			events = append(events, event(utils.NewFlatWorldMap(newBuilder(), worldMap).Table()))
+		}
+
+		samples = append(samples, eventList(newBuilder(), uint32(i), int64(i)*33_000, events))
+	}
+
+	return samples
+}
+
+// eventList wraps the events in an EventList, the same way the server's EventListBuilder does.
+func eventList(builder *flatbuffers.Builder, tick uint32, serverTime int64, events [][]byte) []byte {
+	rawEvents := make([]flatbuffers.UOffsetT, len(events))
+
+	for i, event := range events {
+		rawData := builder.CreateByteVector(event)
+
+		flatgen.RawEventStart(builder)
+		flatgen.RawEventAddRawData(builder, rawData)
+		rawEvents[i] = flatgen.RawEventEnd(builder)
+	}
+
+	flatgen.EventListStartEventsVector(builder, len(rawEvents))
+	for i := len(rawEvents) - 1; i >= 0; i-- {
+		builder.PrependUOffsetT(rawEvents[i])
+	}
+	eventsVector := builder.EndVector(len(rawEvents))
+
+	flatgen.EventListStart(builder)
+	flatgen.EventListAddEvents(builder, eventsVector)
+	flatgen.EventListAddTick(builder, tick)
+	flatgen.EventListAddServerTime(builder, serverTime)
+	builder.Finish(flatgen.EventListEnd(builder))
+
+	return slices.Clone(builder.FinishedBytes())
+}
+
+type segment struct {
+	score  int
+	sample int
+	start  int
+}
+
+type segmentHeap []segment
+
+func (sh segmentHeap) Len() int { return len(sh) }
+
+func (sh segmentHeap) Less(i, j int) bool {
+	if sh[i].score != sh[j].score {
+		return sh[i].score > sh[j].score
+	}
+
+	if sh[i].sample != sh[j].sample {
+		return sh[i].sample < sh[j].sample
+	}
+
+	return sh[i].start < sh[j].start
+}
+
+func (sh segmentHeap) Swap(i, j int) { sh[i], sh[j] = sh[j], sh[i] }
+
+func (sh *segmentHeap) Push(x any) { *sh = append(*sh, x.(segment)) }
+
+func (sh *segmentHeap) Pop() any {
+	old := *sh
+	last := old[len(old)-1]
+	*sh = old[:len(old)-1]
+
+	return last
+}
+
+// Train builds a dictionary of at most size bytes out of the pieces of the samples that have
+// the substrings most of the samples share. Pieces are picked greedily, a substring only counts
+// for the first piece that has it. The best pieces go at the end of the dictionary, deflate
+// writes matches that are closer with fewer bits.
+func Train(samples [][]byte, size int) []byte {
+	kmer := func(data []byte, i int) uint64 {
+		return binary.LittleEndian.Uint64(data[i:])
+	}
+
+	// in how many samples every substring is
+	frequency := map[uint64]int{}
+
+	for _, sample := range samples {
+		seen := map[uint64]bool{}
+
+		for i := 0; i+kmerSize <= len(sample); i++ {
+			if key := kmer(sample, i); !seen[key] {
+				seen[key] = true
+				frequency[key]++
+			}
+		}
+	}
+
+	covered := map[uint64]bool{}
+
+	score := func(s segment) int {
+		data := samples[s.sample][s.start:min(s.start+segmentSize, len(samples[s.sample]))]
+		seen := map[uint64]bool{}
+		total := 0
+
+		for i := 0; i+kmerSize <= len(data); i++ {
+			key := kmer(data, i)
+
+			// substrings in a single sample don't help the others
+			if seen[key] || covered[key] || frequency[key] < 2 {
+				continue
+			}
+
+			seen[key] = true
+			total += frequency[key]
+		}
+
+		return total
+	}
+
+	segments := &segmentHeap{}
+
+	for i, sample := range samples {
+		for start := 0; start+kmerSize <= len(sample); start += segmentSize / 2 {
+			s := segment{sample: i, start: start}
+			s.score = score(s)
+
+			if s.score > 0 {
+				*segments = append(*segments, s)
+			}
+		}
+	}
+
+	heap.Init(segments)
+
+	pieces := [][]byte{}
+	total := 0
+
+	for total < size && segments.Len() > 0 {
+		best := heap.Pop(segments).(segment)
+
+		// The score only goes down as more substrings are covered, so it's fine to take the
+		// segment if it still beats the next best one.
+		best.score = score(best)
+		if best.score == 0 {
+			continue
+		}
+
+		if segments.Len() > 0 && best.score < (*segments)[0].score {
+			heap.Push(segments, best)
+			continue
+		}
+
+		piece := samples[best.sample][best.start:min(best.start+segmentSize, len(samples[best.sample]))]
+		for i := 0; i+kmerSize <= len(piece); i++ {
+			covered[kmer(piece, i)] = true
+		}
+
+		pieces = append(pieces, piece)
+		total += len(piece)
+	}
+
+	dict := make([]byte, 0, total)
+	for i := len(pieces) - 1; i >= 0; i-- {
+		dict = append(dict, pieces[i]...)
+	}
+
+	return dict[max(0, len(dict)-size):]
+}
@@ -0,0 +1,150 @@
+// Package dispatch maps event kinds to the decoder that parses them and to the handlers
+// that act on them, so a new event only has to be registered once.
+package dispatch
+
+import (
+	"fmt"
+
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// Decoder turns the bytes of an event into its typed value.
+type Decoder func(data []byte) (any, error)
+
+type Handler func(event Event) error
+
+type Registry struct {
+	decoders map[flatgen.EventKind]Decoder
+	handlers map[flatgen.EventKind][]Handler
+	// Lifecycle events have their own handlers, so a client sending the same kind over the
+	// wire can't reach them.
+	lifecycleHandlers map[flatgen.EventKind][]Handler
+	fallback          func(event Event)
+	// verifyKind checks the buffer can be read as a KindHolder before Parse reads the kind
+	verifyKind func(data []byte) error
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		decoders:          map[flatgen.EventKind]Decoder{},
+		handlers:          map[flatgen.EventKind][]Handler{},
+		lifecycleHandlers: map[flatgen.EventKind][]Handler{},
+		fallback:          func(event Event) {},
+		verifyKind:        func(data []byte) error { return nil },
+	}
+}
+
+// Flat returns the decoder of a flatbuffer table, given its generated GetRootAs function.
+func Flat[T any](getRoot func(buf []byte, offset flatbuffers.UOffsetT) T) Decoder {
+	return func(data []byte) (any, error) {
+		return getRoot(data, 0), nil
+	}
+}
+
+// Decode sets the decoder used by Parse for kind, replacing the previous one.
+func (r *Registry) Decode(kind flatgen.EventKind, decoder Decoder) {
+	r.decoders[kind] = decoder
+}
+
+func (r *Registry) HasDecoder(kind flatgen.EventKind) bool {
+	_, ok := r.decoders[kind]
+
+	return ok
+}
+
+// On adds a handler for kind. The event data is asserted to T before the handler is called,
+// an event with any other data is reported by Dispatch instead. Handlers of the same kind
+// run in the order they were added.
+func On[T any](r *Registry, kind flatgen.EventKind, handler func(event Event, data T)) {
+	r.handlers[kind] = append(r.handlers[kind], typed(kind, handler))
+}
+
+// OnLifecycle adds a handler for the lifecycle events of kind, see On.
+func OnLifecycle[T any](r *Registry, kind flatgen.EventKind, handler func(event Event, data T)) {
+	r.lifecycleHandlers[kind] = append(r.lifecycleHandlers[kind], typed(kind, handler))
+}
+
+func typed[T any](kind flatgen.EventKind, handler func(event Event, data T)) Handler {
+	return func(event Event) error {
+		data, ok := event.Data.(T)
+		if !ok {
+			return fmt.Errorf("event '%s' has data of type %T, expected %T",
+				flatgen.EnumNamesEventKind[kind], event.Data, *new(T))
+		}
+
+		handler(event, data)
+
+		return nil
+	}
+}
+
+// Fallback sets what happens to events no handler was added for, by default they are dropped.
+func (r *Registry) Fallback(fallback func(event Event)) {
+	r.fallback = fallback
+}
+
+// VerifyKind sets the check Parse runs on every buffer before reading its kind.
+func (r *Registry) VerifyKind(verify func(data []byte) error) {
+	r.verifyKind = verify
+}
+
+// Parse reads the kind of an event and decodes it. Kinds without a decoder are returned
+// with their raw bytes, so they can still reach the fallback.
+func (r *Registry) Parse(data []byte) (eventKind flatgen.EventKind, eventData any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("was panic, returned panic value '%v'", rec)
+		}
+	}()
+
+	if err := r.verifyKind(data); err != nil {
+		return 0, nil, err
+	}
+
+	eventKind = flatgen.GetRootAsKindHolder(data, 0).Kind()
+
+	decoder, ok := r.decoders[eventKind]
+	if !ok {
+		return eventKind, data, nil
+	}
+
+	eventData, err = decoder(data)
+	if err != nil {
+		return eventKind, nil, fmt.Errorf("can't decode '%s': %w", flatgen.EnumNamesEventKind[eventKind], err)
+	}
+
+	return eventKind, eventData, nil
+}
+
+// Dispatch calls the handlers of the event kind, or the fallback when there are none. A
+// handler that panics on a malformed event doesn't take the caller down, the panic is
+// returned as an error.
+func (r *Registry) Dispatch(event Event) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("handling '%s' panicked: %v", flatgen.EnumNamesEventKind[event.Kind], rec)
+		}
+	}()
+
+	handlers, ok := r.handlers[event.Kind]
+	if event.Lifecycle {
+		handlers, ok = r.lifecycleHandlers[event.Kind]
+	}
+
+	if !ok {
+		r.fallback(event)
+
+		return nil
+	}
+
+	for _, handler := range handlers {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package dispatch_test
+
+import (
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/dispatch"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func TestRegistryDispatch(t *testing.T) {
+	registry := dispatch.NewRegistry()
+	utils.RegisterFlatDecoders(registry)
+
+	var pongs []uint32
+
+	dispatch.On(registry, flatgen.EventKindPong, func(event types.Event, pong *flatgen.Pong) {
+		pongs = append(pongs, pong.Seq())
+	})
+	dispatch.On(registry, flatgen.EventKindPong, func(event types.Event, pong *flatgen.Pong) {
+		pongs = append(pongs, pong.Seq()*10)
+	})
+
+	kind, data, err := registry.Parse(utils.NewFlatPong(flatbuffers.NewBuilder(32), 7).Table().Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := registry.Dispatch(types.Event{Kind: kind, Data: data}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pongs) != 2 || pongs[0] != 7 || pongs[1] != 70 {
+		t.Errorf("expected both handlers to run in order, got %v", pongs)
+	}
+
+	err = registry.Dispatch(types.Event{Kind: flatgen.EventKindPong, Data: "not a pong"})
+	if err == nil {
+		t.Errorf("expected an error for data of the wrong type")
+	}
+}
+
+func TestRegistryFallback(t *testing.T) {
+	registry := dispatch.NewRegistry()
+
+	var unhandled []types.Event
+
+	registry.Fallback(func(event types.Event) {
+		unhandled = append(unhandled, event)
+	})
+
+	// Nothing is registered, so the ping comes back as raw bytes and goes to the fallback
+	pingBytes := utils.NewFlatPing(flatbuffers.NewBuilder(32), 1).Table().Bytes
+
+	kind, data, err := registry.Parse(pingBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kind != flatgen.EventKindPing {
+		t.Errorf("expected kind Ping, got %v", kind)
+	}
+
+	if _, ok := data.([]byte); !ok {
+		t.Errorf("expected raw bytes for a kind without decoder, got %T", data)
+	}
+
+	if err := registry.Dispatch(types.Event{Kind: kind, Data: data}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(unhandled) != 1 || unhandled[0].Kind != flatgen.EventKindPing {
+		t.Errorf("expected the ping to reach the fallback, got %v", unhandled)
+	}
+}
+
+func TestRegistryParseGarbage(t *testing.T) {
+	registry := dispatch.NewRegistry()
+
+	if _, _, err := registry.Parse([]byte{1}); err == nil {
+		t.Errorf("expected an error for a truncated event")
+	}
+}
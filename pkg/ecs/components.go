@@ -0,0 +1,97 @@
+package ecs
+
+import "iter"
+
+// Components keeps one type of component in a slice the systems walk in order, the entity ids
+// only go through a map to find their slot. Freed slots are reused by the next entities, so
+// an entity keeps its place until it's deleted.
+type Components[T any] struct {
+	values   []T
+	entities []int
+	used     []bool
+	slots    map[int]int32
+	free     []int32
+}
+
+func NewComponents[T any]() *Components[T] {
+	return &Components[T]{
+		values:   []T{},
+		entities: []int{},
+		used:     []bool{},
+		slots:    map[int]int32{},
+		free:     []int32{},
+	}
+}
+
+// Get returns the entity's component so it can be changed in place. The pointer is only valid
+// until the next Set.
+func (c *Components[T]) Get(id int) (*T, bool) {
+	slot, ok := c.slots[id]
+	if !ok {
+		return nil, false
+	}
+
+	return &c.values[slot], true
+}
+
+func (c *Components[T]) Has(id int) bool {
+	_, ok := c.slots[id]
+
+	return ok
+}
+
+func (c *Components[T]) Set(id int, value T) {
+	if slot, ok := c.slots[id]; ok {
+		c.values[slot] = value
+
+		return
+	}
+
+	if last := len(c.free) - 1; last >= 0 {
+		slot := c.free[last]
+		c.free = c.free[:last]
+
+		c.values[slot], c.entities[slot], c.used[slot] = value, id, true
+		c.slots[id] = slot
+
+		return
+	}
+
+	c.slots[id] = int32(len(c.values))
+	c.values = append(c.values, value)
+	c.entities = append(c.entities, id)
+	c.used = append(c.used, true)
+}
+
+func (c *Components[T]) Delete(id int) {
+	slot, ok := c.slots[id]
+	if !ok {
+		return
+	}
+
+	delete(c.slots, id)
+
+	var zero T
+	c.values[slot], c.used[slot] = zero, false
+	c.free = append(c.free, slot)
+}
+
+func (c *Components[T]) Len() int {
+	return len(c.slots)
+}
+
+// All yields every entity with the component and a pointer to it. Entities deleted during the
+// iteration are skipped, the ones added to a free slot behind it are not seen until the next one.
+func (c *Components[T]) All() iter.Seq2[int, *T] {
+	return func(yield func(int, *T) bool) {
+		for slot := 0; slot < len(c.values); slot++ {
+			if !c.used[slot] {
+				continue
+			}
+
+			if !yield(c.entities[slot], &c.values[slot]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package ecs keeps everything in the world that isn't a player: projectiles, pickups, NPCs
+// and props. An entity is an id with components, the systems change them every tick and the
+// server sends the entities that changed to the players.
+package ecs
+
+import (
+	"iter"
+	"slices"
+
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+type Kind = flatgen.EntityKind
+
+type Transform struct {
+	X, Y float64
+}
+
+type Velocity struct {
+	VX, VY float64
+}
+
+// Collider makes an entity stop at walls and the map edges like a player does, Size is the
+// side of its square.
+type Collider struct {
+	Size float64
+	// HitWall is set for the tick the entity ran into a wall
+	HitWall bool
+}
+
+// Ownership is the player that made the entity, its entities go away when it leaves.
+type Ownership struct {
+	Owner int
+}
+
+// State is everything the clients know about an entity.
+type State struct {
+	Id     int
+	Kind   Kind
+	Owner  int
+	X, Y   float64
+	VX, VY float64
+	Size   float64
+}
+
+// System changes the entities, it's run once every tick with the seconds since the last one.
+type System func(entities *World, worldMap *world.Map, dt float64)
+
+// World has every entity and its components. Like the players, it's only used by the tick's
+// goroutine, nothing in it is safe for concurrent use.
+type World struct {
+	Transforms *Components[Transform]
+	Velocities *Components[Velocity]
+	Colliders  *Components[Collider]
+	Owners     *Components[Ownership]
+
+	kinds   *Components[Kind]
+	systems []System
+	tracked []interface{ Delete(id int) }
+
+	// the changes since the last ClearChanges, for the events
+	spawned   []int
+	updated   []int
+	despawned []int
+	changed   map[int]change
+}
+
+type change uint8
+
+const (
+	unchanged change = iota
+	spawned
+	updated
+)
+
+func NewWorld(systems ...System) *World {
+	return &World{
+		Transforms: NewComponents[Transform](),
+		Velocities: NewComponents[Velocity](),
+		Colliders:  NewComponents[Collider](),
+		Owners:     NewComponents[Ownership](),
+		kinds:      NewComponents[Kind](),
+		systems:    systems,
+		spawned:    []int{},
+		updated:    []int{},
+		despawned:  []int{},
+		changed:    map[int]change{},
+	}
+}
+
+// Track makes Despawn delete the entity from components kept outside the World, for the modules
+// that have their own.
+func (w *World) Track(components interface{ Delete(id int) }) {
+	w.tracked = append(w.tracked, components)
+}
+
+// AddSystem runs the system every tick, after the ones added before it.
+func (w *World) AddSystem(system System) {
+	w.systems = append(w.systems, system)
+}
+
+func (w *World) Run(worldMap *world.Map, dt float64) {
+	for _, system := range w.systems {
+		system(w, worldMap, dt)
+	}
+}
+
+// Spawn adds an entity with the given id, its components are set after. The ids are shared
+// with the players, so the server gives out both.
+func (w *World) Spawn(id int, kind Kind) {
+	if w.kinds.Has(id) {
+		return
+	}
+
+	w.kinds.Set(id, kind)
+	w.spawned = append(w.spawned, id)
+	w.changed[id] = spawned
+}
+
+// Despawn removes the entity and all its components.
+func (w *World) Despawn(id int) {
+	if !w.kinds.Has(id) {
+		return
+	}
+
+	w.kinds.Delete(id)
+	w.Transforms.Delete(id)
+	w.Velocities.Delete(id)
+	w.Colliders.Delete(id)
+	w.Owners.Delete(id)
+
+	for _, components := range w.tracked {
+		components.Delete(id)
+	}
+
+	change := w.changed[id]
+	delete(w.changed, id)
+
+	// the players never heard of an entity spawned since the last changes
+	if change != spawned {
+		w.despawned = append(w.despawned, id)
+	}
+}
+
+// DespawnOwnedBy removes every entity of the owner.
+func (w *World) DespawnOwnedBy(owner int) {
+	for id, ownership := range w.Owners.All() {
+		if ownership.Owner == owner {
+			w.Despawn(id)
+		}
+	}
+}
+
+// Changed marks the entity as updated, it's sent to the players with the next events. The
+// Move system marks the entities it moves, other systems have to mark what they change.
+func (w *World) Changed(id int) {
+	if w.changed[id] != unchanged || !w.kinds.Has(id) {
+		return
+	}
+
+	w.changed[id] = updated
+	w.updated = append(w.updated, id)
+}
+
+// Changes returns the entities spawned, updated and despawned since the last ClearChanges.
+// An entity is in at most one of them.
+func (w *World) Changes() ([]int, []int, []int) {
+	// the despawned entities are only dropped from the lists here, so Despawn stays cheap
+	w.spawned = slices.DeleteFunc(w.spawned, func(id int) bool { return !w.kinds.Has(id) })
+	w.updated = slices.DeleteFunc(w.updated, func(id int) bool { return !w.kinds.Has(id) })
+
+	return w.spawned, w.updated, w.despawned
+}
+
+func (w *World) ClearChanges() {
+	w.spawned = w.spawned[:0]
+	w.updated = w.updated[:0]
+	w.despawned = w.despawned[:0]
+
+	clear(w.changed)
+}
+
+func (w *World) Kind(id int) (Kind, bool) {
+	kind, ok := w.kinds.Get(id)
+	if !ok {
+		return 0, false
+	}
+
+	return *kind, true
+}
+
+func (w *World) Len() int {
+	return w.kinds.Len()
+}
+
+// All yields the id and kind of every entity.
+func (w *World) All() iter.Seq2[int, Kind] {
+	return func(yield func(int, Kind) bool) {
+		for id, kind := range w.kinds.All() {
+			if !yield(id, *kind) {
+				return
+			}
+		}
+	}
+}
+
+// State gathers the components of the entity the clients know about, the ones it doesn't have
+// are left zero.
+func (w *World) State(id int) State {
+	state := State{Id: id}
+
+	if kind, ok := w.kinds.Get(id); ok {
+		state.Kind = *kind
+	}
+	if transform, ok := w.Transforms.Get(id); ok {
+		state.X, state.Y = transform.X, transform.Y
+	}
+	if velocity, ok := w.Velocities.Get(id); ok {
+		state.VX, state.VY = velocity.VX, velocity.VY
+	}
+	if collider, ok := w.Colliders.Get(id); ok {
+		state.Size = collider.Size
+	}
+	if ownership, ok := w.Owners.Get(id); ok {
+		state.Owner = ownership.Owner
+	}
+
+	return state
+}
+
+// States appends the state of every entity in ids to states.
+func (w *World) States(states []State, ids []int) []State {
+	for _, id := range ids {
+		states = append(states, w.State(id))
+	}
+
+	return states
+}
@@ -0,0 +1,115 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+func TestChanges(t *testing.T) {
+	entities := ecs.NewWorld()
+
+	for id := 1; id <= 4; id++ {
+		entities.Spawn(id, flatgen.EntityKindProp)
+	}
+
+	// spawned and despawned before anyone heard of it
+	entities.Despawn(4)
+
+	spawned, updated, despawned := entities.Changes()
+	if !slices.Equal(spawned, []int{1, 2, 3}) || len(updated) != 0 || len(despawned) != 0 {
+		t.Fatalf("expected 1, 2 and 3 spawned, got %v %v %v", spawned, updated, despawned)
+	}
+
+	entities.ClearChanges()
+
+	entities.Changed(1)
+	entities.Changed(2)
+	entities.Changed(1)
+	entities.Despawn(2)
+	entities.Despawn(3)
+	entities.Changed(3)
+
+	spawned, updated, despawned = entities.Changes()
+	if len(spawned) != 0 || !slices.Equal(updated, []int{1}) || !slices.Equal(despawned, []int{2, 3}) {
+		t.Fatalf("expected 1 updated, 2 and 3 despawned, got %v %v %v", spawned, updated, despawned)
+	}
+
+	if entities.Len() != 1 {
+		t.Fatalf("expected one entity left, got %v", entities.Len())
+	}
+}
+
+func TestDespawnOwnedBy(t *testing.T) {
+	entities := ecs.NewWorld()
+
+	for id, owner := range []int{7, 8, 7} {
+		entities.Spawn(id+1, flatgen.EntityKindProjectile)
+		entities.Owners.Set(id+1, ecs.Ownership{Owner: owner})
+		entities.Transforms.Set(id+1, ecs.Transform{X: 1, Y: 1})
+	}
+
+	entities.DespawnOwnedBy(7)
+
+	ids := []int{}
+	for id := range entities.All() {
+		ids = append(ids, id)
+	}
+
+	if !slices.Equal(ids, []int{2}) {
+		t.Fatalf("expected only the entity of 8 left, got %v", ids)
+	}
+
+	if entities.Transforms.Has(1) || entities.Transforms.Len() != 1 {
+		t.Fatal("expected the components of the despawned entities to be gone")
+	}
+}
+
+func TestMove(t *testing.T) {
+	worldMap := world.NewEmptyMap(100, 100)
+	worldMap.Walls = []world.Rect{{X: 50, Y: 0, Width: 10, Height: 100}}
+
+	entities := ecs.NewWorld(ecs.Move)
+
+	// a projectile flying into the wall, and a prop that goes through everything
+	entities.Spawn(1, flatgen.EntityKindProjectile)
+	entities.Transforms.Set(1, ecs.Transform{X: 30, Y: 10})
+	entities.Velocities.Set(1, ecs.Velocity{VX: 200, VY: 10})
+	entities.Colliders.Set(1, ecs.Collider{Size: 4})
+
+	entities.Spawn(2, flatgen.EntityKindProp)
+	entities.Transforms.Set(2, ecs.Transform{X: 30, Y: 50})
+	entities.Velocities.Set(2, ecs.Velocity{VX: 100})
+
+	entities.Spawn(3, flatgen.EntityKindPickup)
+	entities.Transforms.Set(3, ecs.Transform{X: 10, Y: 10})
+
+	entities.ClearChanges()
+	entities.Run(worldMap, 0.1)
+
+	projectile := entities.State(1)
+	if projectile.X != 30 || projectile.Y != 11 || projectile.VX != 0 || projectile.VY != 10 {
+		t.Fatalf("expected the projectile stopped on x by the wall, got %+v", projectile)
+	}
+
+	if collider, _ := entities.Colliders.Get(1); !collider.HitWall {
+		t.Fatal("expected the projectile to hit the wall")
+	}
+
+	if prop := entities.State(2); prop.X != 40 {
+		t.Fatalf("expected the prop to move without a collider, got %+v", prop)
+	}
+
+	if _, updated, _ := entities.Changes(); !slices.Equal(updated, []int{1, 2}) {
+		t.Fatalf("expected the moved entities to be updated, got %v", updated)
+	}
+
+	entities.Run(worldMap, 0.1)
+
+	if collider, _ := entities.Colliders.Get(1); collider.HitWall {
+		t.Fatal("expected HitWall only for the tick the wall was hit")
+	}
+}
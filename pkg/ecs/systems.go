@@ -0,0 +1,42 @@
+package ecs
+
+import "github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+
+// Move moves the entities with a Transform along their Velocity. The ones with a Collider stop
+// on the axis they hit a wall on, like the players do.
+func Move(w *World, worldMap *world.Map, dt float64) {
+	for _, collider := range w.Colliders.All() {
+		collider.HitWall = false
+	}
+
+	for id, velocity := range w.Velocities.All() {
+		if velocity.VX == 0 && velocity.VY == 0 {
+			continue
+		}
+
+		transform, ok := w.Transforms.Get(id)
+		if !ok {
+			continue
+		}
+
+		collider, solid := w.Colliders.Get(id)
+
+		if newX := transform.X + velocity.VX*dt; velocity.VX != 0 {
+			if solid && worldMap.Blocked(newX, transform.Y, collider.Size) {
+				velocity.VX, collider.HitWall = 0, true
+			} else {
+				transform.X = newX
+			}
+		}
+
+		if newY := transform.Y + velocity.VY*dt; velocity.VY != 0 {
+			if solid && worldMap.Blocked(transform.X, newY, collider.Size) {
+				velocity.VY, collider.HitWall = 0, true
+			} else {
+				transform.Y = newY
+			}
+		}
+
+		w.Changed(id)
+	}
+}
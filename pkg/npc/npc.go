@@ -0,0 +1,133 @@
+// Package npc steers the characters the server plays itself. A Behavior picks the direction an
+// NPC wants to move in every tick, the server then moves it with that input like a player.
+package npc
+
+import (
+	"math"
+	"math/rand"
+)
+
+type Kind uint8
+
+const (
+	// Wander walks in a random direction and picks another one every WanderTurn.
+	Wander Kind = iota
+	// FollowNearest walks to the nearest player it can see, it wanders when there is none.
+	FollowNearest
+	// Flee walks away from the nearest player it can see, it wanders when there is none.
+	Flee
+	// Patrol walks to its waypoints in order, over and over.
+	Patrol
+)
+
+// WanderTurn is how long, in seconds, a wandering NPC keeps its direction. ArriveDistance is
+// how close it has to get to a player it follows or a waypoint to stop there.
+var WanderTurn = 2.0
+var ArriveDistance = 16.0
+
+type Point struct {
+	X, Y float64
+}
+
+// Behavior is what an NPC does, it keeps what it needs between ticks so it has to be used
+// through a pointer.
+type Behavior struct {
+	Kind Kind
+	// Sight is how far a following or fleeing NPC notices players.
+	Sight float64
+	// Waypoints are walked in order by a patrolling NPC, then again from the first.
+	Waypoints []Point
+
+	// the wander direction and the seconds until it changes, the waypoint walked to
+	dirX, dirY float64
+	turnIn     float64
+	next       int
+}
+
+// Input returns the direction the NPC at (x, y) wants to move in, dt seconds after the last
+// time it was asked. players are the positions of the players it could see.
+func (b *Behavior) Input(x, y float64, players []Point, rng *rand.Rand, dt float64) (float64, float64) {
+	switch b.Kind {
+	case FollowNearest:
+		target, ok := nearest(x, y, players, b.Sight)
+		if !ok {
+			return b.wander(rng, dt)
+		}
+
+		return towards(x, y, target)
+	case Flee:
+		target, ok := nearest(x, y, players, b.Sight)
+		if !ok {
+			return b.wander(rng, dt)
+		}
+
+		// anywhere is far enough, so it doesn't stop like when it arrives
+		dx, dy := x-target.X, y-target.Y
+		if length := math.Hypot(dx, dy); length > 0 {
+			return dx / length, dy / length
+		}
+
+		return b.wander(rng, dt)
+	case Patrol:
+		if len(b.Waypoints) == 0 {
+			return 0, 0
+		}
+
+		b.next %= len(b.Waypoints)
+
+		if waypoint := b.Waypoints[b.next]; math.Hypot(waypoint.X-x, waypoint.Y-y) <= ArriveDistance {
+			b.next = (b.next + 1) % len(b.Waypoints)
+		}
+
+		return towards(x, y, b.Waypoints[b.next])
+	default:
+		return b.wander(rng, dt)
+	}
+}
+
+// wander keeps the direction until it's time to turn, a quarter of the turns it stands still.
+func (b *Behavior) wander(rng *rand.Rand, dt float64) (float64, float64) {
+	b.turnIn -= dt
+	if b.turnIn > 0 {
+		return b.dirX, b.dirY
+	}
+
+	b.turnIn = WanderTurn * (0.5 + rng.Float64())
+
+	if rng.Intn(4) == 0 {
+		b.dirX, b.dirY = 0, 0
+	} else {
+		angle := rng.Float64() * 2 * math.Pi
+		b.dirX, b.dirY = math.Cos(angle), math.Sin(angle)
+	}
+
+	return b.dirX, b.dirY
+}
+
+// nearest is the closest of the points at most sight away, a sight of 0 sees everything.
+func nearest(x, y float64, points []Point, sight float64) (Point, bool) {
+	best, found := math.Inf(1), false
+	var target Point
+
+	for _, point := range points {
+		distance := math.Hypot(point.X-x, point.Y-y)
+
+		if distance < best && (sight <= 0 || distance <= sight) {
+			best, target, found = distance, point, true
+		}
+	}
+
+	return target, found
+}
+
+// towards is the direction to the target, nothing once the NPC arrived.
+func towards(x, y float64, target Point) (float64, float64) {
+	dx, dy := target.X-x, target.Y-y
+
+	length := math.Hypot(dx, dy)
+	if length <= ArriveDistance {
+		return 0, 0
+	}
+
+	return dx / length, dy / length
+}
@@ -0,0 +1,72 @@
+package npc_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/npc"
+)
+
+func TestInput(t *testing.T) {
+	players := []npc.Point{{X: 100, Y: 0}, {X: 0, Y: 50}, {X: 1000, Y: 1000}}
+
+	testCases := []struct {
+		name     string
+		behavior npc.Behavior
+		x, y     float64
+		expected [2]float64
+	}{
+		{"follow the nearest", npc.Behavior{Kind: npc.FollowNearest, Sight: 300}, 0, 0, [2]float64{0, 1}},
+		{"stop next to it", npc.Behavior{Kind: npc.FollowNearest, Sight: 300}, 0, 40, [2]float64{0, 0}},
+		{"flee from the nearest", npc.Behavior{Kind: npc.Flee, Sight: 300}, 90, 0, [2]float64{-1, 0}},
+		{"see everything without a sight", npc.Behavior{Kind: npc.FollowNearest}, 1000, 1500, [2]float64{0, -1}},
+		{"patrol to the first waypoint", npc.Behavior{Kind: npc.Patrol, Waypoints: []npc.Point{{X: 0, Y: 100}, {X: 100, Y: 100}}}, 0, 0, [2]float64{0, 1}},
+		{"patrol to the next waypoint", npc.Behavior{Kind: npc.Patrol, Waypoints: []npc.Point{{X: 0, Y: 100}, {X: 100, Y: 100}}}, 0, 100, [2]float64{1, 0}},
+		{"stand without waypoints", npc.Behavior{Kind: npc.Patrol}, 0, 0, [2]float64{0, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			x, y := tc.behavior.Input(tc.x, tc.y, players, rand.New(rand.NewSource(1)), 0.1)
+
+			if math.Abs(x-tc.expected[0]) > 1e-9 || math.Abs(y-tc.expected[1]) > 1e-9 {
+				t.Fatalf("expected %v, got (%v, %v)", tc.expected, x, y)
+			}
+		})
+	}
+}
+
+func TestPatrolLoops(t *testing.T) {
+	behavior := npc.Behavior{Kind: npc.Patrol, Waypoints: []npc.Point{{X: 0, Y: 0}, {X: 100, Y: 0}}}
+	rng := rand.New(rand.NewSource(1))
+
+	behavior.Input(0, 0, nil, rng, 0.1)
+
+	if x, _ := behavior.Input(100, 0, nil, rng, 0.1); x != -1 {
+		t.Fatalf("expected to walk back to the first waypoint, got %v", x)
+	}
+}
+
+func TestWanderKeepsItsDirection(t *testing.T) {
+	npc.WanderTurn = 1
+	t.Cleanup(func() { npc.WanderTurn = 2 })
+
+	behavior := npc.Behavior{Kind: npc.Wander}
+	rng := rand.New(rand.NewSource(3))
+
+	x, y := behavior.Input(0, 0, nil, rng, 0.1)
+	if length := math.Hypot(x, y); length != 0 && math.Abs(length-1) > 1e-9 {
+		t.Fatalf("expected a unit direction or standing still, got (%v, %v)", x, y)
+	}
+
+	if nextX, nextY := behavior.Input(0, 0, nil, rng, 0.1); nextX != x || nextY != y {
+		t.Fatalf("expected the same direction before the turn, got (%v, %v) then (%v, %v)", x, y, nextX, nextY)
+	}
+
+	// a fleeing NPC that sees nobody wanders
+	fleeing := npc.Behavior{Kind: npc.Flee, Sight: 10}
+	if x, y := fleeing.Input(0, 0, []npc.Point{{X: 500, Y: 500}}, rng, 0.1); math.Hypot(x, y) > 1+1e-9 {
+		t.Fatalf("expected a fleeing NPC that sees nobody to wander, got (%v, %v)", x, y)
+	}
+}
@@ -0,0 +1,86 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+// Model moves players using their input as an acceleration. The player Speed is the
+// max speed they can reach, in world units per second.
+type Model struct {
+	// Acceleration in units/s^2 applied in the direction of the input.
+	Acceleration float64
+	// Friction is how fast the velocity decays when there is no input, in 1/s: every step
+	// keeps 1-Friction*dt of it, so it's divided by about e every 1/Friction seconds (0.1s
+	// with the default 10). A step of 1/Friction seconds or longer stops the player at once.
+	Friction float64
+}
+
+// stopSpeed is the speed under which a player with no input is considered stopped.
+const stopSpeed = 1.0
+
+var DefaultModel = Model{
+	Acceleration: 1500,
+	Friction:     10,
+}
+
+// NormalizeInput clamps the input direction to the unit circle, so moving on a diagonal
+// is not faster than moving on a single axis.
+func NormalizeInput(x, y float64) (float64, float64) {
+	if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return 0, 0
+	}
+
+	length := math.Hypot(x, y)
+	if length > 1 {
+		return x / length, y / length
+	}
+
+	return x, y
+}
+
+// Step advances the player by dt seconds. Walls and the map edges stop the player on
+// the axis they hit.
+func (m Model) Step(player *types.Player, worldMap *world.Map, size, dt float64) {
+	inputX, inputY := NormalizeInput(player.InputX, player.InputY)
+
+	if inputX == 0 && inputY == 0 {
+		damping := max(0, 1-m.Friction*dt)
+
+		player.VX *= damping
+		player.VY *= damping
+	} else {
+		player.VX += inputX * m.Acceleration * dt
+		player.VY += inputY * m.Acceleration * dt
+	}
+
+	if speed := math.Hypot(player.VX, player.VY); speed > player.Speed && speed > 0 {
+		player.VX = player.VX / speed * player.Speed
+		player.VY = player.VY / speed * player.Speed
+	}
+
+	if inputX == 0 && math.Abs(player.VX) < stopSpeed {
+		player.VX = 0
+	}
+	if inputY == 0 && math.Abs(player.VY) < stopSpeed {
+		player.VY = 0
+	}
+
+	if newX := player.X + player.VX*dt; player.VX != 0 {
+		if worldMap.Blocked(newX, player.Y, size) {
+			player.VX = 0
+		} else {
+			player.X = newX
+		}
+	}
+
+	if newY := player.Y + player.VY*dt; player.VY != 0 {
+		if worldMap.Blocked(player.X, newY, size) {
+			player.VY = 0
+		} else {
+			player.Y = newY
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package physics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/physics"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+const dt = 1.0 / 30
+
+func TestDiagonalIsNotFaster(t *testing.T) {
+	worldMap := world.NewEmptyMap(10000, 10000)
+
+	straight := types.Player{X: 5000, Y: 5000, Speed: 250, InputX: 1}
+	diagonal := types.Player{X: 5000, Y: 5000, Speed: 250, InputX: 1, InputY: 1}
+
+	for range 60 {
+		physics.DefaultModel.Step(&straight, worldMap, 8, dt)
+		physics.DefaultModel.Step(&diagonal, worldMap, 8, dt)
+	}
+
+	straightDistance := math.Hypot(straight.X-5000, straight.Y-5000)
+	diagonalDistance := math.Hypot(diagonal.X-5000, diagonal.Y-5000)
+
+	if math.Abs(straightDistance-diagonalDistance) > 1e-6 {
+		t.Errorf("expected the same distance, straight=%v diagonal=%v", straightDistance, diagonalDistance)
+	}
+
+	if speed := math.Hypot(diagonal.VX, diagonal.VY); speed > 250+1e-9 {
+		t.Errorf("speed %v is over the max speed", speed)
+	}
+}
+
+func TestFrictionStopsThePlayer(t *testing.T) {
+	worldMap := world.NewEmptyMap(10000, 10000)
+	player := types.Player{X: 5000, Y: 5000, Speed: 250, VX: 250}
+
+	for range 30 {
+		physics.DefaultModel.Step(&player, worldMap, 8, dt)
+	}
+
+	if player.VX != 0 {
+		t.Errorf("expected the player to stop, VX=%v", player.VX)
+	}
+}
+
+func TestFrictionIsARate(t *testing.T) {
+	worldMap := world.NewEmptyMap(10000, 10000)
+	model := physics.Model{Acceleration: 1500, Friction: 10}
+	player := types.Player{X: 5000, Y: 5000, Speed: 250, VX: 200}
+
+	model.Step(&player, worldMap, 8, 0.05)
+
+	// every step keeps 1-Friction*dt of the velocity
+	if math.Abs(player.VX-100) > 1e-9 {
+		t.Errorf("expected half of the velocity to be left, VX=%v", player.VX)
+	}
+
+	model.Step(&player, worldMap, 8, 0.1)
+
+	if player.VX != 0 {
+		t.Errorf("a step of 1/Friction seconds should stop the player, VX=%v", player.VX)
+	}
+}
+
+func TestWallsStopThePlayer(t *testing.T) {
+	worldMap := world.NewEmptyMap(100, 100)
+	worldMap.Walls = []world.Rect{{X: 50, Y: 0, Width: 10, Height: 100}}
+
+	player := types.Player{X: 10, Y: 10, Speed: 250, InputX: 1}
+
+	for range 60 {
+		physics.DefaultModel.Step(&player, worldMap, 8, dt)
+	}
+
+	if player.X+8 > 50 {
+		t.Errorf("player walked into the wall, X=%v", player.X)
+	}
+}
+
+func TestNormalizeInput(t *testing.T) {
+	x, y := physics.NormalizeInput(3, 4)
+	if math.Abs(x-0.6) > 1e-9 || math.Abs(y-0.8) > 1e-9 {
+		t.Errorf("unexpected normalized input (%v, %v)", x, y)
+	}
+
+	x, y = physics.NormalizeInput(0.5, 0)
+	if x != 0.5 || y != 0 {
+		t.Errorf("small inputs should be kept as they are, got (%v, %v)", x, y)
+	}
+
+	x, y = physics.NormalizeInput(math.NaN(), 1)
+	if x != 0 || y != 0 {
+		t.Errorf("NaN input should be ignored, got (%v, %v)", x, y)
+	}
+}
@@ -0,0 +1,185 @@
+// Package profile keeps what the server remembers about players between their connections,
+// in a bbolt file. Players are known by the profile key their client sends when it joins, a
+// secret the store made for them with NewKey.
+package profile
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// MaxKeyLength is the longest profile key that is remembered, bbolt keys have to be short.
+const MaxKeyLength = 128
+
+var bucket = []byte("profiles")
+
+// metaBucket has the secret the keys are signed with, it stays with the profiles so a key
+// works as long as its profile is kept.
+var (
+	metaBucket = []byte("meta")
+	secretKey  = []byte("secret")
+)
+
+const (
+	// idSize is the random part of a key, macSize the part that signs it
+	idSize  = 16
+	macSize = 16
+)
+
+// ErrInvalidKey is returned for a key that is empty or longer than MaxKeyLength.
+var ErrInvalidKey = errors.New("invalid profile key")
+
+type Profile struct {
+	Key  string  `json:"-"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	Team int     `json:"team"`
+	// LastSeen is when the player was last saved, while playing or when it left.
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// ValidKey tells if key can be used to save a profile.
+func ValidKey(key string) bool {
+	return key != "" && len(key) <= MaxKeyLength
+}
+
+// Store is a bbolt file of profiles. It's safe for concurrent use, but only one process can
+// have the file open.
+type Store struct {
+	db     *bolt.DB
+	secret []byte
+}
+
+// Open opens the store at path, creating it if it doesn't exist.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("can't open profiles '%s': %w", path, err)
+	}
+
+	var secret []byte
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+			return err
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		if saved := meta.Get(secretKey); saved != nil {
+			secret = bytes.Clone(saved)
+			return nil
+		}
+
+		secret = make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+
+		return meta.Put(secretKey, secret)
+	})
+	if err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("can't open profiles '%s': %w", path, err)
+	}
+
+	return &Store{db: db, secret: secret}, nil
+}
+
+// NewKey returns a new profile key: a random id followed by its signature, so Issued can tell
+// it from a key a client made up. It's the secret a player is remembered by, whoever sends it
+// plays as that player.
+func (s *Store) NewKey() string {
+	id := make([]byte, idSize)
+	rand.Read(id)
+
+	return hex.EncodeToString(id) + hex.EncodeToString(s.sign(id))
+}
+
+// Issued tells if the key was made by NewKey, with the secret of this file.
+func (s *Store) Issued(key string) bool {
+	raw, err := hex.DecodeString(key)
+	if err != nil || len(raw) != idSize+macSize {
+		return false
+	}
+
+	return hmac.Equal(raw[idSize:], s.sign(raw[:idSize]))
+}
+
+func (s *Store) sign(id []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(id)
+
+	return mac.Sum(nil)[:macSize]
+}
+
+// Load returns the profile saved for key, ok is false when there is none.
+func (s *Store) Load(key string) (profile Profile, ok bool, err error) {
+	if !ValidKey(key) {
+		return profile, false, ErrInvalidKey
+	}
+
+	err = s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+
+		ok = true
+
+		return json.Unmarshal(data, &profile)
+	})
+	if err != nil {
+		return Profile{}, false, fmt.Errorf("can't load profile '%s': %w", key, err)
+	}
+
+	profile.Key = key
+
+	return profile, ok, nil
+}
+
+// Save writes the profiles in one transaction, so a batch costs one sync of the file.
+func (s *Store) Save(profiles []Profile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		profilesBucket := tx.Bucket(bucket)
+
+		for _, profile := range profiles {
+			if !ValidKey(profile.Key) {
+				return fmt.Errorf("can't save profile '%s': %w", profile.Key, ErrInvalidKey)
+			}
+
+			data, err := json.Marshal(profile)
+			if err != nil {
+				return err
+			}
+
+			if err := profilesBucket.Put([]byte(profile.Key), data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Close closes the file, the store can't be used after.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,126 @@
+package profile_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/profile"
+)
+
+func TestStoreKeepsProfilesAcrossOpens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.db")
+
+	store, err := profile.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastSeen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	err = store.Save([]profile.Profile{
+		{Key: "alice", X: 10, Y: 20, Team: 1, LastSeen: lastSeen},
+		{Key: "bob", X: 30, Y: 40, Team: 2, LastSeen: lastSeen},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = profile.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	alice, ok, err := store.Load("alice")
+	if err != nil || !ok {
+		t.Fatalf("expected alice to be saved, got %v %v", ok, err)
+	}
+
+	if alice.Key != "alice" || alice.X != 10 || alice.Y != 20 || alice.Team != 1 || !alice.LastSeen.Equal(lastSeen) {
+		t.Errorf("unexpected profile %+v", alice)
+	}
+
+	if _, ok, err := store.Load("carol"); ok || err != nil {
+		t.Errorf("expected no profile for carol, got %v %v", ok, err)
+	}
+}
+
+func TestStoreRefusesInvalidKeys(t *testing.T) {
+	store, err := profile.Open(filepath.Join(t.TempDir(), "profiles.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	long := strings.Repeat("a", profile.MaxKeyLength+1)
+
+	if _, _, err := store.Load(""); !errors.Is(err, profile.ErrInvalidKey) {
+		t.Errorf("expected an empty key to be refused, got %v", err)
+	}
+
+	if err := store.Save([]profile.Profile{{Key: "alice"}, {Key: long}}); !errors.Is(err, profile.ErrInvalidKey) {
+		t.Errorf("expected a long key to be refused, got %v", err)
+	}
+
+	// the batch is one transaction
+	if _, ok, _ := store.Load("alice"); ok {
+		t.Errorf("expected nothing of a refused batch to be saved")
+	}
+}
+
+func TestKeysAreIssuedByTheStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.db")
+
+	store, err := profile.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := store.NewKey()
+	if !profile.ValidKey(key) || !store.Issued(key) {
+		t.Fatalf("expected %q to be a key of the store", key)
+	}
+
+	if key == store.NewKey() {
+		t.Fatal("expected every key to be new")
+	}
+
+	forged := strings.Repeat("0", len(key)-1) + "1"
+	for _, madeUp := range []string{"", "alice", forged, key[:len(key)-2]} {
+		if store.Issued(madeUp) {
+			t.Errorf("%q wasn't made by the store", madeUp)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the keys stay good as long as the file
+	store, err = profile.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if !store.Issued(key) {
+		t.Fatal("expected the key to be issued after opening the store again")
+	}
+
+	other, err := profile.Open(filepath.Join(t.TempDir(), "other.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	if other.Issued(key) {
+		t.Fatal("expected the key of another store to be refused")
+	}
+}
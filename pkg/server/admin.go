@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+)
+
+type AdminPlayer struct {
+	Id          int       `json:"id"`
+	X           float64   `json:"x"`
+	Y           float64   `json:"y"`
+	Team        int       `json:"team"`
+	Health      int       `json:"health"`
+	Dead        bool      `json:"dead"`
+	Address     string    `json:"address"`
+	RTT         float64   `json:"rttMs"`
+	Jitter      float64   `json:"jitterMs"`
+	MissedPongs int       `json:"missedPongs"`
+	PacketLoss  float64   `json:"packetLoss"`
+	LastPong    time.Time `json:"lastPong"`
+}
+
+func remoteAddr(player types.PlayerWithSocket) string {
+	if player.Conn == nil {
+		return ""
+	}
+
+	return player.Conn.RemoteAddr()
+}
+
+// AdminPlayers lists the connected players and the quality of their connection as JSON. The
+// players are read by the next tick, the store isn't safe to read from the handler.
+func (game *GameServer) AdminPlayers(w http.ResponseWriter, r *http.Request) {
+	listed := make(chan []AdminPlayer, 1)
+
+	select {
+	case game.tickCalls <- func() { listed <- game.adminPlayers() }:
+	case <-r.Context().Done():
+		return
+	}
+
+	var players []AdminPlayer
+
+	select {
+	case players = <-listed:
+	case <-r.Context().Done():
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(players)
+	if err != nil {
+		game.log.Errorf("err: %s\n", err.Error())
+	}
+}
+
+func (game *GameServer) adminPlayers() []AdminPlayer {
+	players := []AdminPlayer{}
+
+	for _, player := range game.Players.All() {
+		players = append(players, AdminPlayer{
+			Id:          player.Id,
+			X:           player.X,
+			Y:           player.Y,
+			Team:        player.Team,
+			Health:      player.Health,
+			Dead:        player.State == types.Dead,
+			Address:     remoteAddr(player),
+			RTT:         float64(player.Latency.RTT) / float64(time.Millisecond),
+			Jitter:      float64(player.Latency.Jitter) / float64(time.Millisecond),
+			MissedPongs: player.Latency.MissedPongs,
+			PacketLoss:  player.Latency.PacketLoss(),
+			LastPong:    player.Latency.LastPong,
+		})
+	}
+
+	slices.SortFunc(players, func(a, b AdminPlayer) int { return a.Id - b.Id })
+
+	return players
+}
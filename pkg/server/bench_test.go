@@ -0,0 +1,354 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/log"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/profile"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+var playerCounts = []int{10, 100, 1000}
+
+func BenchmarkEventCollector(b *testing.B) {
+	for _, count := range playerCounts {
+		b.Run(fmt.Sprintf("players=%v", count), func(b *testing.B) {
+			ec := server.NewEventCollector()
+			pool := server.NewBuilderPool(512, 64)
+			players := testPlayers(count)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for range b.N {
+				fillTick(ec, pool, players)
+
+				for _, player := range players {
+					ec.GetPlayerEventList(player.Id)
+				}
+
+				ec.Reset()
+				pool.Reset()
+			}
+		})
+	}
+}
+
+func BenchmarkFlatCacheGetMutatedPlayerJoined(b *testing.B) {
+	players := testPlayers(100)
+
+	b.Run("cached", func(b *testing.B) {
+		flatCache := server.NewFlatCache()
+		for _, player := range players {
+			flatCache.GetMutatedPlayerJoined(player.Id, player, types.IntegerCodec)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := range b.N {
+			player := players[i%len(players)]
+			player.X += float64(i % 7)
+
+			flatCache.GetMutatedPlayerJoined(player.Id, player, types.IntegerCodec)
+		}
+	})
+
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := range b.N {
+			flatCache := server.NewFlatCache()
+			player := players[i%len(players)]
+
+			flatCache.GetMutatedPlayerJoined(player.Id, player, types.IntegerCodec)
+		}
+	})
+}
+
+func BenchmarkBuilderPool(b *testing.B) {
+	for _, count := range []int{4, 64} {
+		b.Run(fmt.Sprintf("builders=%v", count), func(b *testing.B) {
+			pool := server.NewBuilderPool(512, 4)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for range b.N {
+				for i := range count {
+					utils.NewFlatPlayerQuit(pool.GetFreeBuilder(), i)
+				}
+
+				pool.Reset()
+			}
+		})
+	}
+}
+
+var playerStores = []struct {
+	name     string
+	newStore func(tb testing.TB) server.PlayerStore
+}{
+	{"base", func(testing.TB) server.PlayerStore { return server.NewPlayerStore() }},
+	{"dense", func(testing.TB) server.PlayerStore { return server.NewDensePlayerStore() }},
+	// what the game runs on with ProfilesPath set
+	{"persistent", func(tb testing.TB) server.PlayerStore {
+		profiles, err := profile.Open(filepath.Join(tb.TempDir(), "profiles.db"))
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		store := server.NewPersistentPlayerStore(server.NewDensePlayerStore(), profiles, log.New(io.Discard))
+		tb.Cleanup(func() { store.Close() })
+
+		return store
+	}},
+}
+
+// BenchmarkPlayerStore does what a tick does to the store, tick copies the moved players back
+// with Set and tick-in-place changes them through a MutablePlayerStore's Ref when it can.
+func BenchmarkPlayerStore(b *testing.B) {
+	for _, count := range []int{1000, 10000} {
+		for _, ps := range playerStores {
+			store := ps.newStore(b)
+
+			for _, player := range testPlayers(count) {
+				store.Set(player.Id, types.PlayerWithSocket{Player: player})
+			}
+
+			b.Run(fmt.Sprintf("store=%v/players=%v/op=get", ps.name, count), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := range b.N {
+					store.Get(i%count + 1)
+				}
+			})
+
+			b.Run(fmt.Sprintf("store=%v/players=%v/op=set", ps.name, count), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := range b.N {
+					store.Set(i%count+1, types.PlayerWithSocket{Player: types.Player{Id: i%count + 1, X: float64(i)}})
+				}
+			})
+
+			b.Run(fmt.Sprintf("store=%v/players=%v/op=all", ps.name, count), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for range b.N {
+					x := 0.0
+					for _, player := range store.All() {
+						x += player.X
+					}
+				}
+			})
+
+			b.Run(fmt.Sprintf("store=%v/players=%v/op=tick", ps.name, count), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for range b.N {
+					for id, player := range store.All() {
+						player.X++
+						store.Set(id, player)
+					}
+				}
+			})
+
+			mutable, ok := store.(server.MutablePlayerStore)
+			if !ok {
+				continue
+			}
+
+			b.Run(fmt.Sprintf("store=%v/players=%v/op=tick-in-place", ps.name, count), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for range b.N {
+					for id := range mutable.All() {
+						ref, _ := mutable.Ref(id)
+						ref.X++
+					}
+				}
+			})
+		}
+	}
+}
+
+// fakeConns returns the server side of count in memory connections, the client side of them
+// reads and drops everything it gets.
+func fakeConns(b *testing.B, count int) []transport.Conn {
+	b.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conns := make([]transport.Conn, count)
+
+	for i := range conns {
+		client, conn := transport.NewPipeConns(fmt.Sprintf("bot-%d", i))
+		conns[i] = conn
+
+		go func() {
+			for {
+				if _, err := client.Read(ctx); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	b.Cleanup(func() {
+		cancel()
+
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+
+	return conns
+}
+
+// joinPlayers connects a player on every conn and confirms its hello, then runs ticks until
+// the players got everything they were owed for joining.
+func joinPlayers(b *testing.B, game *server.GameServer, conns []transport.Conn) []int {
+	b.Helper()
+
+	ctx := context.Background()
+	builder := flatbuffers.NewBuilder(256)
+	ids := make([]int, len(conns))
+
+	for i, conn := range conns {
+		ids[i] = game.IdGenerator.NewId()
+		game.EventQueue <- types.Event{PlayerId: ids[i], Kind: flatgen.EventKindPlayerHello, Conn: conn, Data: types.PlayerConnected{}, Lifecycle: true}
+	}
+
+	game.RunTick(ctx, time.Millisecond)
+
+	for i, id := range ids {
+		builder.Reset()
+
+		confirm := utils.NewFlatPlayerHelloConfirm(builder, id, flatgen.PositionEncodingInteger, flatgen.CompressionNone, 0, "")
+
+		event := wireEvent(b, game, id, confirm.Table().Bytes)
+		event.Conn = conns[i]
+		game.EventQueue <- event
+	}
+
+	for range 100 {
+		game.RunTick(ctx, time.Millisecond)
+
+		deferred := 0
+		for _, id := range ids {
+			deferred += game.EventCollector.Deferred(id)
+		}
+
+		if deferred == 0 {
+			return ids
+		}
+	}
+
+	b.Fatal("the joins are still deferred after 100 ticks")
+
+	return nil
+}
+
+// moveEvents returns a PlayerMoved for every player, going left or right.
+func moveEvents(b *testing.B, game *server.GameServer, ids []int, conns []transport.Conn, inputX float64) []types.Event {
+	b.Helper()
+
+	events := make([]types.Event, len(ids))
+
+	for i, id := range ids {
+		moved := utils.NewFlatPlayerMoved(flatbuffers.NewBuilder(128), types.Player{Id: id, InputX: inputX}, types.IntegerCodec)
+
+		events[i] = wireEvent(b, game, id, moved.Table().Bytes)
+		events[i].Conn = conns[i]
+	}
+
+	return events
+}
+
+func BenchmarkTick(b *testing.B) {
+	pingInterval := server.PingInterval
+	b.Cleanup(func() { server.PingInterval = pingInterval })
+
+	// the fake clients don't answer pings
+	server.PingInterval = time.Hour
+
+	for _, count := range playerCounts {
+		b.Run(fmt.Sprintf("players=%v", count), func(b *testing.B) {
+			game := newTestGame()
+			game.SetLogEnabled(false)
+
+			conns := fakeConns(b, count)
+			ids := joinPlayers(b, game, conns)
+
+			moves := [][]types.Event{
+				moveEvents(b, game, ids, conns, -1),
+				moveEvents(b, game, ids, conns, 1),
+			}
+
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := range b.N {
+				for _, event := range moves[i%len(moves)] {
+					game.EventQueue <- event
+				}
+
+				game.RunTick(ctx, time.Second/time.Duration(server.ServerFPS))
+			}
+		})
+	}
+}
+
+// BenchmarkEntityUpdates is a tick where every entity moved, they go in one EntityUpdated per
+// codec however many there are.
+func BenchmarkEntityUpdates(b *testing.B) {
+	pingInterval := server.PingInterval
+	b.Cleanup(func() { server.PingInterval = pingInterval })
+
+	server.PingInterval = time.Hour
+
+	for _, entities := range []int{100, 300, 600} {
+		b.Run(fmt.Sprintf("players=100/entities=%v", entities), func(b *testing.B) {
+			game := newTestGame()
+			game.SetLogEnabled(false)
+
+			joinPlayers(b, game, fakeConns(b, 100))
+
+			ids := make([]int, entities)
+			for i := range ids {
+				ids[i] = game.SpawnEntity(flatgen.EntityKindNpc)
+				game.Entities.Transforms.Set(ids[i], ecs.Transform{X: float64(i), Y: 100})
+				game.Entities.Colliders.Set(ids[i], ecs.Collider{Size: 8})
+			}
+
+			ctx := context.Background()
+			game.RunTick(ctx, time.Millisecond)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := range b.N {
+				for j, id := range ids {
+					game.Entities.Transforms.Set(id, ecs.Transform{X: float64(j + i%2), Y: 100})
+					game.Entities.Changed(id)
+				}
+
+				game.RunTick(ctx, time.Second/time.Duration(server.ServerFPS))
+			}
+		})
+	}
+}
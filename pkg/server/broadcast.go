@@ -0,0 +1,195 @@
+package server
+
+import (
+	"io"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// SharedBroadcast makes the events sent to everyone be serialized once per tick and codec
+// instead of once per player, see broadcast.
+var SharedBroadcast = true
+
+// Frame is an EventList made of the part built for one player, Head, followed by the part
+// shared by every player using the same codec, Shared. The parts are written one after the
+// other, so the shared one is never copied for a player.
+type Frame struct {
+	Head   []byte
+	Shared []byte
+
+	// full is Head followed by the space left for Shared in the player's buffer
+	full []byte
+}
+
+// Len is the size of the EventList.
+func (f Frame) Len() int {
+	return len(f.Head) + len(f.Shared)
+}
+
+// Bytes returns the whole EventList in one slice, it's valid until the EventCollector is reset.
+func (f Frame) Bytes() []byte {
+	if len(f.Shared) == 0 {
+		return f.Head
+	}
+
+	copy(f.full[len(f.Head):], f.Shared)
+
+	return f.full
+}
+
+// WriteTo writes the EventList to w, it implements io.WriterTo.
+func (f Frame) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(f.Head)
+	if err != nil || len(f.Shared) == 0 {
+		return int64(n), err
+	}
+
+	m, err := w.Write(f.Shared)
+
+	return int64(n + m), err
+}
+
+type sharedEvent struct {
+	event  types.EventHolder
+	offset flatbuffers.UOffsetT
+}
+
+// broadcast is what every player using a codec gets in a tick: the general events, the encoded
+// ones of the codec, its PlayerMovedList and its EntityUpdated. Their RawEvents are serialized
+// once at the end of a buffer. Flatbuffers are built back to front and reference everything by its offset from
+// the end, so an EventList built in front of the same number of bytes can reference them and
+// be sent followed by the broadcast.
+type broadcast struct {
+	builder *flatbuffers.Builder
+	// events are the RawEvents in the broadcast, by seq
+	events map[uint64]sharedEvent
+	ready  bool
+}
+
+func newBroadcast() *broadcast {
+	return &broadcast{
+		builder: flatbuffers.NewBuilder(1024),
+		events:  map[uint64]sharedEvent{},
+	}
+}
+
+func (b *broadcast) reset() {
+	b.builder.Reset()
+	clear(b.events)
+	b.ready = false
+}
+
+func (b *broadcast) add(collected collectedEvent) {
+	rawDataOffset := b.builder.CreateByteVector(collected.event.Bytes())
+
+	flatgen.RawEventStart(b.builder)
+	flatgen.RawEventAddRawData(b.builder, rawDataOffset)
+
+	b.events[collected.seq] = sharedEvent{event: collected.event, offset: flatgen.RawEventEnd(b.builder)}
+}
+
+// finish pads the broadcast so what's built in front of it is aligned like it would be at the
+// end of an empty buffer.
+func (b *broadcast) finish() {
+	b.builder.Prep(8, 0)
+	b.ready = true
+}
+
+func (b *broadcast) bytes() []byte {
+	return b.builder.Bytes[b.builder.Head():]
+}
+
+// rawEvent returns the RawEvent of the event if it's in the broadcast. Events are matched by
+// their bytes, a PlayerMovedList made for one player has the seq of the shared one.
+func (b *broadcast) rawEvent(collected collectedEvent) (flatbuffers.UOffsetT, bool) {
+	shared, ok := b.events[collected.seq]
+	if !ok {
+		return 0, false
+	}
+
+	sharedBytes, eventBytes := shared.event.Bytes(), collected.event.Bytes()
+	if len(sharedBytes) == 0 || len(sharedBytes) != len(eventBytes) || &sharedBytes[0] != &eventBytes[0] {
+		return 0, false
+	}
+
+	return shared.offset, true
+}
+
+// coveredBy tells if the events have the whole broadcast, it's only worth sending to a
+// player that gets all of it.
+func (b *broadcast) coveredBy(events []collectedEvent) bool {
+	if len(b.events) == 0 {
+		return false
+	}
+
+	found := 0
+	for _, collected := range events {
+		if _, ok := b.rawEvent(collected); ok {
+			found++
+		}
+	}
+
+	return found == len(b.events)
+}
+
+// broadcasts has the broadcast of every codec, each is serialized the first time it's needed
+// in a tick and again after an event for everyone was added.
+type broadcasts map[types.PositionCodec]*broadcast
+
+// get returns the broadcast of the codec, made of the events of the queues, the
+// PlayerMovedList of the moves and the EntityUpdated of the updates.
+func (bs broadcasts) get(codec types.PositionCodec, queues []*eventQueue, moves *moveSet, updates *updateSet) *broadcast {
+	b, ok := bs[codec]
+	if !ok {
+		b = newBroadcast()
+		bs[codec] = b
+	}
+
+	if b.ready {
+		return b
+	}
+
+	b.reset()
+
+	for _, queue := range queues {
+		if queue == nil {
+			continue
+		}
+
+		for _, collected := range queue.reliable {
+			b.add(collected)
+		}
+
+		for _, collected := range queue.latest {
+			b.add(collected)
+		}
+	}
+
+	if movesList, ok := moves.lists[codec]; ok {
+		b.add(collectedEvent{seq: moves.seq, event: movesList})
+	}
+
+	if updatesList, ok := updates.lists[codec]; ok {
+		b.add(collectedEvent{seq: updates.seq, event: updatesList})
+	}
+
+	b.finish()
+
+	return b
+}
+
+// invalidate makes the broadcasts be serialized again.
+func (bs broadcasts) invalidate() {
+	for _, b := range bs {
+		b.ready = false
+	}
+}
+
+func (bs broadcasts) reset() {
+	for _, b := range bs {
+		b.reset()
+	}
+}
@@ -0,0 +1,208 @@
+package server_test
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/verify"
+)
+
+func withSharedBroadcast(t testing.TB, shared bool) {
+	t.Helper()
+
+	sharedBroadcast := server.SharedBroadcast
+	t.Cleanup(func() { server.SharedBroadcast = sharedBroadcast })
+
+	server.SharedBroadcast = shared
+}
+
+var fixedPointCodec = types.PositionCodec{Encoding: flatgen.PositionEncodingFixedPoint, Scale: 100}
+
+// fillTick adds the events of a tick where every player moved, one joined and one quit. Every
+// third player uses FixedPoint positions and every fifth one gets a Ping of its own.
+func fillTick(ec *server.EventCollector, pool *server.BuilderPool, players []types.Player) {
+	for i, player := range players {
+		ec.AddPlayer(player.Id)
+
+		if i%3 == 0 {
+			ec.SetPositionCodec(player.Id, fixedPointCodec)
+		} else {
+			ec.SetPositionCodec(player.Id, types.IntegerCodec)
+		}
+	}
+
+	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(pool.GetFreeBuilder(), 9999)))
+	ec.AddMoves(pool, players)
+
+	for _, codec := range ec.PositionCodecs() {
+		joinedList := utils.NewFlatPlayerJoinedList(pool.GetFreeBuilder(), players[:1], codec)
+		ec.AddEncodedEvent(codec, utils.NewEventHolder(flatgen.EventKindPlayerJoinedList, joinedList))
+	}
+
+	for i := 0; i < len(players); i += 5 {
+		ec.AddEvent(players[i].Id, utils.NewEventHolder(flatgen.EventKindPing, utils.NewFlatPing(pool.GetFreeBuilder(), uint32(i))))
+	}
+}
+
+func testPlayers(count int) []types.Player {
+	players := make([]types.Player, count)
+
+	for i := range players {
+		players[i] = types.Player{Id: i + 1, X: float64(i * 7 % 1600), Y: float64(i * 13 % 1200), Speed: 250}
+	}
+
+	return players
+}
+
+// rawEvents returns the bytes of every event in the list.
+func rawEvents(eventList *flatgen.EventList) [][]byte {
+	rawEvent := &flatgen.RawEvent{}
+	events := [][]byte{}
+
+	for i := range eventList.EventsLength() {
+		eventList.Events(rawEvent, i)
+		events = append(events, bytes.Clone(rawEvent.RawDataBytes()))
+	}
+
+	return events
+}
+
+func collectTick(t *testing.T, shared bool, players []types.Player) map[int][][]byte {
+	t.Helper()
+	withSharedBroadcast(t, shared)
+
+	ec := server.NewEventCollector()
+	pool := server.NewBuilderPool(512, 64)
+
+	fillTick(ec, pool, players)
+
+	received := map[int][][]byte{}
+	sharedFrames := 0
+
+	for _, player := range players {
+		frame, count := ec.GetPlayerFrame(player.Id)
+		if count == 0 {
+			t.Fatalf("player %v got no events", player.Id)
+		}
+
+		var written bytes.Buffer
+		if _, err := frame.WriteTo(&written); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(written.Bytes(), frame.Bytes()) || written.Len() != frame.Len() {
+			t.Fatalf("player %v: the written frame isn't the EventList", player.Id)
+		}
+
+		if err := verify.Root(frame.Bytes(), verify.EventList); err != nil {
+			t.Fatalf("player %v: %v", player.Id, err)
+		}
+
+		if len(frame.Shared) > 0 {
+			sharedFrames++
+		}
+
+		received[player.Id] = rawEvents(flatgen.GetRootAsEventList(frame.Bytes(), 0))
+	}
+
+	if shared != (sharedFrames == len(players)) {
+		t.Fatalf("expected shared %v, %v of %v frames were shared", shared, sharedFrames, len(players))
+	}
+
+	return received
+}
+
+func TestBroadcastMatchesCopiedEvents(t *testing.T) {
+	players := testPlayers(50)
+
+	copied := collectTick(t, false, players)
+	shared := collectTick(t, true, players)
+
+	for _, player := range players {
+		if !slices.EqualFunc(copied[player.Id], shared[player.Id], bytes.Equal) {
+			t.Fatalf("player %v got different events from the broadcast", player.Id)
+		}
+	}
+}
+
+func TestBroadcastSkippedWhenNotAllSent(t *testing.T) {
+	withSharedBroadcast(t, true)
+	withBudget(t, 512)
+
+	ec := server.NewEventCollector()
+	pool := server.NewBuilderPool(512, 64)
+
+	players := testPlayers(100)
+	fillTick(ec, pool, players)
+
+	frame, count := ec.GetPlayerFrame(players[1].Id)
+	if count == 0 {
+		t.Fatal("expected events")
+	}
+
+	if len(frame.Shared) != 0 {
+		t.Fatal("a player that only gets some of the moves shouldn't get the broadcast")
+	}
+
+	if err := verify.Root(frame.Bytes(), verify.EventList); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBroadcastAcrossTicks(t *testing.T) {
+	withSharedBroadcast(t, true)
+
+	ec := server.NewEventCollector()
+	pool := server.NewBuilderPool(512, 64)
+
+	for tick := range 3 {
+		players := testPlayers(10 + tick*40)
+		fillTick(ec, pool, players)
+
+		for _, player := range players {
+			eventList, _ := ec.GetPlayerEventList(player.Id)
+
+			_, moves := receivedEvents(eventList, 0)
+			if len(moves) != len(players) {
+				t.Fatalf("tick %v: player %v got %v moves, expected %v", tick, player.Id, len(moves), len(players))
+			}
+		}
+
+		ec.Reset()
+		pool.Reset()
+	}
+}
+
+func BenchmarkGetPlayerFrame(b *testing.B) {
+	for _, count := range []int{100, 500} {
+		for _, shared := range []bool{false, true} {
+			b.Run(fmt.Sprintf("players=%v/shared=%v", count, shared), func(b *testing.B) {
+				withSharedBroadcast(b, shared)
+
+				ec := server.NewEventCollector()
+				pool := server.NewBuilderPool(512, 64)
+				players := testPlayers(count)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for range b.N {
+					fillTick(ec, pool, players)
+
+					for _, player := range players {
+						ec.GetPlayerFrame(player.Id)
+					}
+
+					ec.Reset()
+					pool.Reset()
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+// Players start with MaxHealth, every projectile that hits takes ProjectileDamage of it.
+var MaxHealth = 100
+var ProjectileDamage = 20
+
+// A projectile flies ProjectileSpeed units per second for ProjectileLifetime, or until it hits a
+// wall or a player. A player can fire once every FireCooldown.
+var ProjectileSpeed = float64(600)
+var ProjectileLifetime = 1500 * time.Millisecond
+var ProjectileSize = float64(4)
+var FireCooldown = 250 * time.Millisecond
+
+// A player that dies comes back RespawnDelay later, where the Spawner picks.
+var RespawnDelay = 3 * time.Second
+
+// MaxRewind is as far back as the hits of a projectile are checked, players with a higher RTT
+// have to lead their shots more.
+var MaxRewind = 300 * time.Millisecond
+
+// Projectile is the server's side of a projectile entity, the players only get the entity.
+type Projectile struct {
+	Damage int
+	// Lifetime is how long the projectile has left to fly.
+	Lifetime time.Duration
+	// Rewind is the RTT of the shooter when it fired, the players are checked where they were
+	// that long ago.
+	Rewind time.Duration
+	// LastX and LastY are where the projectile was before it last moved.
+	LastX, LastY float64
+}
+
+func (game *GameServer) OnPlayerFire(event Event, fire *flatgen.PlayerFire) {
+	player, ok := game.Players.Get(event.PlayerId)
+	if !ok || player.Spectator || player.State == Dead || game.state.Start.Sub(player.LastFire) < FireCooldown {
+		return
+	}
+
+	dirX, dirY := float64(fire.DirX()), float64(fire.DirY())
+
+	length := math.Hypot(dirX, dirY)
+	if length == 0 || math.IsNaN(length) || math.IsInf(length, 0) {
+		return
+	}
+
+	player.LastFire = game.state.Start
+	game.Players.Set(player.Id, player)
+
+	game.SpawnProjectile(player, dirX/length, dirY/length)
+}
+
+// SpawnProjectile fires a projectile from the middle of the player in the (dirX, dirY) direction,
+// which has to be of length 1.
+func (game *GameServer) SpawnProjectile(player PlayerWithSocket, dirX, dirY float64) int {
+	id := game.SpawnEntity(flatgen.EntityKindProjectile)
+
+	x, y := player.X+(PlayerSize-ProjectileSize)/2, player.Y+(PlayerSize-ProjectileSize)/2
+
+	game.Entities.Transforms.Set(id, ecs.Transform{X: x, Y: y})
+	game.Entities.Velocities.Set(id, ecs.Velocity{VX: dirX * ProjectileSpeed, VY: dirY * ProjectileSpeed})
+	game.Entities.Colliders.Set(id, ecs.Collider{Size: ProjectileSize})
+	game.Entities.Owners.Set(id, ecs.Ownership{Owner: player.Id})
+
+	game.projectiles.Set(id, Projectile{
+		Damage:   ProjectileDamage,
+		Lifetime: ProjectileLifetime,
+		Rewind:   min(player.Latency.RTT, MaxRewind),
+		LastX:    x,
+		LastY:    y,
+	})
+
+	return id
+}
+
+// recordHistory adds where every player is at the start of the tick to its history, that's
+// what the players are sent in the tick.
+func (game *GameServer) recordHistory(at time.Time) {
+	for id, player := range game.Players.All() {
+		if player.Spectator || player.State == Dead {
+			continue
+		}
+
+		history, ok := game.history[id]
+		if !ok {
+			history = &PositionHistory{}
+			game.history[id] = history
+		}
+
+		history.Record(at, player.X, player.Y)
+	}
+}
+
+// moveProjectiles checks what the projectiles hit since they last moved and despawns the ones
+// that hit something or flew for too long. A projectile hits the first player in its way, where
+// the player was Rewind ago.
+func (game *GameServer) moveProjectiles(dt time.Duration) {
+	for id, projectile := range game.projectiles.All() {
+		transform, ok := game.Entities.Transforms.Get(id)
+		if !ok {
+			continue
+		}
+
+		owner := 0
+		if ownership, ok := game.Entities.Owners.Get(id); ok {
+			owner = ownership.Owner
+		}
+
+		if target, hit := game.projectileTarget(projectile, owner, transform.X, transform.Y); hit {
+			// the despawn clears the projectile
+			damage := projectile.Damage
+
+			game.Entities.Despawn(id)
+			game.damagePlayer(target, owner, damage)
+
+			continue
+		}
+
+		projectile.Lifetime -= dt
+		projectile.LastX, projectile.LastY = transform.X, transform.Y
+
+		if collider, ok := game.Entities.Colliders.Get(id); projectile.Lifetime <= 0 || (ok && collider.HitWall) {
+			game.Entities.Despawn(id)
+		}
+	}
+}
+
+// projectileTarget is the first player the projectile went through on its way to (x, y).
+func (game *GameServer) projectileTarget(projectile *Projectile, owner int, x, y float64) (int, bool) {
+	shooter, _ := game.Players.Get(owner)
+	at := game.state.Start.Add(-projectile.Rewind)
+
+	target, first := 0, math.Inf(1)
+
+	for id, player := range game.Players.All() {
+		if id == owner || player.Spectator || player.State == Dead || (player.Team != 0 && player.Team == shooter.Team) {
+			continue
+		}
+
+		targetX, targetY := player.X, player.Y
+		if history, ok := game.history[id]; ok {
+			targetX, targetY, _ = history.At(at)
+		}
+
+		// the projectile hits when its corner goes through the player grown by its size
+		hitbox := world.Rect{X: targetX - ProjectileSize, Y: targetY - ProjectileSize, Width: PlayerSize + ProjectileSize, Height: PlayerSize + ProjectileSize}
+
+		if enter, hit := hitbox.IntersectsSegment(projectile.LastX, projectile.LastY, x, y); hit && enter < first {
+			target, first = id, enter
+		}
+	}
+
+	return target, !math.IsInf(first, 1)
+}
+
+// damagePlayer takes the damage from the player's health and tells everyone, a player with
+// no health left dies.
+func (game *GameServer) damagePlayer(id, attacker, damage int) {
+	player, ok := game.Players.Get(id)
+	if !ok || player.State == Dead {
+		return
+	}
+
+	player.Health = max(player.Health-damage, 0)
+
+	playerDamaged := utils.NewFlatPlayerDamaged(game.state.BufferPool.GetFreeBuilder(), id, attacker, damage, player.Health)
+	game.Send(ToAll(), utils.NewEventHolder(flatgen.EventKindPlayerDamaged, playerDamaged))
+
+	if player.Health == 0 {
+		game.killPlayer(&player, attacker)
+	}
+
+	game.Players.Set(id, player)
+}
+
+// killPlayer stops the player where it is until it respawns, RespawnDelay from now.
+func (game *GameServer) killPlayer(player *PlayerWithSocket, killer int) {
+	player.State = Dead
+	player.InputX, player.InputY = 0, 0
+	player.VX, player.VY = 0, 0
+	player.RespawnAt = game.state.Start.Add(RespawnDelay)
+
+	playerDied := utils.NewFlatPlayerDied(game.state.BufferPool.GetFreeBuilder(), player.Id, killer, float32(RespawnDelay.Seconds()))
+	game.Send(ToAll(), utils.NewEventHolder(flatgen.EventKindPlayerDied, playerDied))
+}
+
+// sendHealth tells a player that just joined that the other player is dead or hurt, a player
+// it's told about is alive with MaxHealth otherwise. The killer or attacker isn't known anymore,
+// it's 0.
+func (game *GameServer) sendHealth(to int, player PlayerWithSocket) {
+	switch {
+	case player.State == Dead:
+		respawnIn := max(player.RespawnAt.Sub(game.state.Start), 0)
+
+		playerDied := utils.NewFlatPlayerDied(game.state.BufferPool.GetFreeBuilder(), player.Id, 0, float32(respawnIn.Seconds()))
+		game.Send(ToPlayer(to), utils.NewEventHolder(flatgen.EventKindPlayerDied, playerDied))
+	case player.Health < MaxHealth:
+		playerDamaged := utils.NewFlatPlayerDamaged(game.state.BufferPool.GetFreeBuilder(), player.Id, 0, MaxHealth-player.Health, player.Health)
+		game.Send(ToPlayer(to), utils.NewEventHolder(flatgen.EventKindPlayerDamaged, playerDamaged))
+	}
+}
+
+// respawnPlayers brings back the dead players whose RespawnAt passed and tells everyone where
+// they are.
+func (game *GameServer) respawnPlayers(now time.Time) {
+	for id, player := range game.Players.All() {
+		if player.State != Dead || now.Before(player.RespawnAt) {
+			continue
+		}
+
+		game.spawnPlayer(&player.Player)
+		game.Players.Set(id, player)
+
+		if history, ok := game.history[id]; ok {
+			history.Reset()
+		}
+
+		for _, codec := range game.EventCollector.PositionCodecs() {
+			playerRespawned := utils.NewFlatPlayerRespawned(game.state.BufferPool.GetFreeBuilder(), player.Player, player.Health, codec)
+			game.EventCollector.AddEncodedEvent(codec, utils.NewEventHolder(flatgen.EventKindPlayerRespawned, playerRespawned))
+		}
+	}
+}
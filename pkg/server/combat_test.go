@@ -0,0 +1,195 @@
+package server_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// newCombatGame has a shooter, player 1 at (100, 400), and a target, player 2 at (400, 400).
+func newCombatGame(t *testing.T) (*server.GameServer, transport.Conn) {
+	t.Helper()
+
+	game := newTestGame()
+	game.Spawner = server.NewSpawner(&server.FixedSpawn{Points: []server.Point{{X: 100, Y: 400}, {X: 400, Y: 400}}}, 1)
+
+	shooter := joinOnPipe(t, game, 1)
+	joinOnPipe(t, game, 2)
+	game.RunTick(context.Background(), time.Millisecond)
+
+	return game, shooter
+}
+
+func fire(t *testing.T, game *server.GameServer, id int, dirX, dirY float64) {
+	t.Helper()
+
+	playerFire := utils.NewFlatPlayerFire(flatbuffers.NewBuilder(64), dirX, dirY)
+	game.EventQueue <- wireEvent(t, game, id, playerFire.Table().Bytes)
+}
+
+func TestProjectilesHitWhereTheShooterSawThePlayer(t *testing.T) {
+	testCases := []struct {
+		name string
+		rtt  time.Duration
+		hit  bool
+	}{
+		// the target is rewound to where it was before it moved away
+		{"with the shooter's RTT", 200 * time.Millisecond, true},
+		{"without lag", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			game, shooter := newCombatGame(t)
+			ctx := context.Background()
+
+			target, _ := game.Players.Get(2)
+			target.X = 600
+			game.Players.Set(2, target)
+
+			player, _ := game.Players.Get(1)
+			player.Latency.RTT = tc.rtt
+			game.Players.Set(1, player)
+
+			game.RunTick(ctx, time.Millisecond)
+			receivedKinds(t, shooter, func(flatgen.EventKind, []byte) {})
+
+			fire(t, game, 1, 1, 0)
+
+			// far enough for the projectile to pass x 400, not to reach x 600
+			for range 6 {
+				game.RunTick(ctx, 100*time.Millisecond)
+			}
+
+			kinds := receivedKinds(t, shooter, func(flatgen.EventKind, []byte) {})
+
+			if target, _ := game.Players.Get(2); (target.Health < server.MaxHealth) != tc.hit {
+				t.Fatalf("expected hit %v, the target has %v health", tc.hit, target.Health)
+			}
+
+			if slices.Contains(kinds, flatgen.EventKindPlayerDamaged) != tc.hit {
+				t.Fatalf("expected the damage to be sent %v, got %v", tc.hit, kinds)
+			}
+
+			if game.Entities.Len() != 0 == tc.hit {
+				t.Fatalf("expected the projectile to be despawned %v, %v entities left", tc.hit, game.Entities.Len())
+			}
+		})
+	}
+}
+
+func TestPlayersDieAndRespawn(t *testing.T) {
+	server.FireCooldown, server.RespawnDelay = 0, 100*time.Millisecond
+	t.Cleanup(func() { server.FireCooldown, server.RespawnDelay = 250*time.Millisecond, 3*time.Second })
+
+	game, shooter := newCombatGame(t)
+	ctx := context.Background()
+
+	// the shooter can't hit itself
+	fire(t, game, 1, 0, 1)
+
+	for range server.MaxHealth / server.ProjectileDamage {
+		fire(t, game, 1, 1, 0)
+	}
+
+	for range 8 {
+		game.RunTick(ctx, 100*time.Millisecond)
+	}
+
+	died := false
+	kinds := receivedKinds(t, shooter, func(kind flatgen.EventKind, data []byte) {
+		if kind == flatgen.EventKindPlayerDied {
+			event := flatgen.GetRootAsPlayerDied(data, 0)
+			died = event.Id() == 2 && event.Killer() == 1 && event.RespawnIn() == float32(server.RespawnDelay.Seconds())
+		}
+	})
+
+	if !died || slices.Contains(kinds, flatgen.EventKindPlayerRespawned) {
+		t.Fatalf("expected the target to die and wait to respawn, got died %v and %v", died, kinds)
+	}
+
+	if player, _ := game.Players.Get(1); player.Health != server.MaxHealth {
+		t.Fatalf("expected the shooter to be unharmed, it has %v health", player.Health)
+	}
+
+	// the dead don't fire or move
+	entities := game.Entities.Len()
+
+	game.EventQueue <- wireEvent(t, game, 2, utils.NewFlatPlayerMoved(flatbuffers.NewBuilder(64), types.Player{Id: 2, InputX: 1}, types.IntegerCodec).Table().Bytes)
+	fire(t, game, 2, -1, 0)
+	game.RunTick(ctx, 100*time.Millisecond)
+
+	if target, _ := game.Players.Get(2); target.State != types.Dead || target.X != 400 || game.Entities.Len() > entities {
+		t.Fatalf("expected the target dead where it died, it's %v at %v with %v entities", target.State, target.X, game.Entities.Len())
+	}
+
+	time.Sleep(server.RespawnDelay)
+	game.RunTick(ctx, time.Millisecond)
+
+	respawned := false
+	receivedKinds(t, shooter, func(kind flatgen.EventKind, data []byte) {
+		if kind == flatgen.EventKindPlayerRespawned {
+			event := flatgen.GetRootAsPlayerRespawned(data, 0)
+			respawned = event.Player(nil).Id() == 2 && int(event.Health()) == server.MaxHealth
+		}
+	})
+
+	if target, _ := game.Players.Get(2); !respawned || target.State != types.Alive || target.Health != server.MaxHealth {
+		t.Fatalf("expected the target respawned with full health, got %v, it's %v with %v", respawned, target.State, target.Health)
+	}
+}
+
+func TestLateJoinersGetTheHealthOfThePlayers(t *testing.T) {
+	game, _ := newCombatGame(t)
+	ctx := context.Background()
+
+	hurt, _ := game.Players.Get(1)
+	hurt.Health = server.MaxHealth - server.ProjectileDamage
+	game.Players.Set(1, hurt)
+
+	dead, _ := game.Players.Get(2)
+	dead.Health, dead.State, dead.RespawnAt = 0, types.Dead, time.Now().Add(time.Minute)
+	game.Players.Set(2, dead)
+
+	late := joinOnPipe(t, game, 3)
+	game.RunTick(ctx, time.Millisecond)
+
+	joined, damaged, died := []int32{}, map[int32]int32{}, map[int32]float32{}
+	receivedKinds(t, late, func(kind flatgen.EventKind, data []byte) {
+		switch kind {
+		case flatgen.EventKindPlayerJoined:
+			joined = append(joined, flatgen.GetRootAsPlayerJoined(data, 0).Player(nil).Id())
+		case flatgen.EventKindPlayerDamaged:
+			event := flatgen.GetRootAsPlayerDamaged(data, 0)
+			if !slices.Contains(joined, event.Id()) {
+				t.Errorf("expected player %v to be joined before its damage", event.Id())
+			}
+
+			damaged[event.Id()] = event.Health()
+		case flatgen.EventKindPlayerDied:
+			event := flatgen.GetRootAsPlayerDied(data, 0)
+			if !slices.Contains(joined, event.Id()) {
+				t.Errorf("expected player %v to be joined before its death", event.Id())
+			}
+
+			died[event.Id()] = event.RespawnIn()
+		}
+	})
+
+	if len(damaged) != 1 || damaged[1] != int32(hurt.Health) {
+		t.Errorf("expected the late joiner to get the health of player 1, got %v", damaged)
+	}
+
+	if respawnIn, ok := died[2]; len(died) != 1 || !ok || respawnIn <= 0 || respawnIn > 60 {
+		t.Errorf("expected the late joiner to know player 2 is dead for a minute, got %v", died)
+	}
+}
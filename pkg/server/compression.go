@@ -0,0 +1,91 @@
+package server
+
+import (
+	"slices"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/compression"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+
+	"github.com/coder/websocket"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// WebsocketCompression is the permessage-deflate mode offered to clients when they connect.
+// Browsers negotiate it on their own and decompress transparently, but it compresses every
+// message of every connection so it's disabled by default.
+var WebsocketCompression = websocket.CompressionDisabled
+
+// Compressions are the compressions offered to clients in PlayerHello, a client that picks
+// anything else gets uncompressed EventLists.
+var Compressions = []flatgen.Compression{flatgen.CompressionDeflate}
+
+// CompressionThreshold is the smallest EventList worth compressing, in bytes.
+var CompressionThreshold = 256
+
+// CompressionLevel is the flate level of the Deflate compression, 2 is the fastest level that
+// uses the dictionary.
+var CompressionLevel = 2
+
+// NewCompression returns the compression a client asked for, falling back to None when it's
+// not one we offer or when the client has another version of the Deflate dictionary.
+func (game *GameServer) NewCompression(kind flatgen.Compression, dictionaryVersion int) flatgen.Compression {
+	if !slices.Contains(Compressions, kind) {
+		return flatgen.CompressionNone
+	}
+
+	if kind == flatgen.CompressionDeflate && dictionaryVersion != compression.DictionaryVersion {
+		return flatgen.CompressionNone
+	}
+
+	return kind
+}
+
+// compresses tells if a frame of the given size is compressed for the player.
+func compresses(player PlayerWithSocket, size int) bool {
+	return player.Compression == flatgen.CompressionDeflate && size >= CompressionThreshold
+}
+
+// Compress returns the frame to send to the player for the EventList in data. A compressed
+// EventList is wrapped in another one with only the compressed field set. Frames that are small
+// or don't shrink are sent as they are.
+func (game *GameServer) Compress(player PlayerWithSocket, data []byte) []byte {
+	if !compresses(player, len(data)) {
+		return data
+	}
+
+	if game.compressor == nil {
+		compressor, err := compression.NewCompressor(CompressionLevel, compression.Dictionary())
+		if err != nil {
+			game.log.Errorf("can't compress frames: %v", err)
+			return data
+		}
+
+		game.compressor = compressor
+		game.compressionBuilder = flatbuffers.NewBuilder(1024)
+	}
+
+	compressed, err := game.compressor.Compress(data)
+	if err != nil {
+		game.log.Errorf("can't compress frame for player '%v': %v", player.Id, err)
+		return data
+	}
+
+	builder := game.compressionBuilder
+	builder.Reset()
+
+	compressedVector := builder.CreateByteVector(compressed)
+
+	flatgen.EventListStart(builder)
+	flatgen.EventListAddCompressed(builder, compressedVector)
+	builder.Finish(flatgen.EventListEnd(builder))
+
+	frame := builder.FinishedBytes()
+	if len(frame) >= len(data) {
+		frame = data
+	}
+
+	game.StatCollector.Tick().AddCompression(len(data), len(frame))
+
+	return frame
+}
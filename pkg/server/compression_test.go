@@ -0,0 +1,82 @@
+package server_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/compression"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/verify"
+)
+
+func TestCompressWrapsEventList(t *testing.T) {
+	game := newTestGame()
+	player := types.PlayerWithSocket{Player: types.Player{Id: 1}, Compression: flatgen.CompressionDeflate}
+
+	for i, sample := range compression.Samples() {
+		if len(sample) < 1024 {
+			continue
+		}
+
+		frame := game.Compress(player, sample)
+		if len(frame) >= len(sample) {
+			t.Fatalf("sample %v: frame of %v bytes isn't smaller than %v", i, len(frame), len(sample))
+		}
+
+		if err := verify.Root(frame, verify.EventList); err != nil {
+			t.Fatal(err)
+		}
+
+		eventList := flatgen.GetRootAsEventList(frame, 0)
+		if eventList.EventsLength() != 0 {
+			t.Fatal("compressed EventList shouldn't have events")
+		}
+
+		decompressed, err := compression.NewDecompressor(compression.Dictionary()).Decompress(eventList.CompressedBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(decompressed, sample) {
+			t.Fatalf("sample %v doesn't round trip", i)
+		}
+	}
+
+	if stats := game.StatCollector.Tick().AvgTickStat(); stats.CompressedData == 0 || stats.CompressedData >= stats.UncompressedData {
+		t.Fatalf("expected the compression in the stats, got %v of %v bytes", stats.CompressedData, stats.UncompressedData)
+	}
+}
+
+func TestCompressSkipsFrames(t *testing.T) {
+	game := newTestGame()
+	sample := compression.Samples()[0]
+
+	uncompressed := types.PlayerWithSocket{Player: types.Player{Id: 1}}
+	if frame := game.Compress(uncompressed, sample); !bytes.Equal(frame, sample) {
+		t.Fatal("frame compressed for a player that didn't ask for it")
+	}
+
+	compressed := types.PlayerWithSocket{Player: types.Player{Id: 2}, Compression: flatgen.CompressionDeflate}
+	small := sample[:server.CompressionThreshold-1]
+	if frame := game.Compress(compressed, small); !bytes.Equal(frame, small) {
+		t.Fatal("frame smaller than the threshold was compressed")
+	}
+}
+
+func TestNewCompressionFallsBackToNone(t *testing.T) {
+	game := newTestGame()
+
+	if got := game.NewCompression(flatgen.Compression(42), compression.DictionaryVersion); got != flatgen.CompressionNone {
+		t.Fatalf("expected None, got %v", got)
+	}
+
+	if got := game.NewCompression(flatgen.CompressionDeflate, compression.DictionaryVersion); got != flatgen.CompressionDeflate {
+		t.Fatalf("expected Deflate, got %v", got)
+	}
+
+	if got := game.NewCompression(flatgen.CompressionDeflate, compression.DictionaryVersion+1); got != flatgen.CompressionNone {
+		t.Fatalf("expected None for another dictionary, got %v", got)
+	}
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// DatagramRepeats is for how many ticks a player that moved is sent again to the players
+// whose moves go in datagrams, with the state it has then, so a lost datagram is made up
+// by the next ones.
+var DatagramRepeats = 3
+
+// datagramOverhead is what a datagram costs besides its moves: the EventList, the RawEvent
+// and the PlayerMovedList the moves go in.
+const datagramOverhead = 128
+
+type datagramKey struct {
+	codec types.PositionCodec
+	size  int
+}
+
+// datagramState is what the collector keeps for the players whose moves go in datagrams.
+type datagramState struct {
+	// moves are the players sent in datagrams, built has them built for every codec and size
+	// of datagram used this tick
+	moves        []types.Player
+	built        map[datagramKey][][]byte
+	builders     []*flatbuffers.Builder
+	usedBuilders int
+	users        int
+
+	// settled are the players that stopped being sent in datagrams, they go in the EventList
+	// of the players with datagrams like moves do for the others
+	settled moveSet
+
+	scratch *flatbuffers.Builder
+}
+
+func newDatagramState() datagramState {
+	return datagramState{
+		built:   map[datagramKey][][]byte{},
+		settled: newMoveSet(),
+		scratch: flatbuffers.NewBuilder(1024),
+	}
+}
+
+// SetDatagramSize makes the moves of the player go in datagrams of at most size bytes instead
+// of its EventList, see GetPlayerDatagrams. 0 puts them back in the EventList.
+func (es *EventCollector) SetDatagramSize(playerId int, size int) {
+	events, ok := es.playerEvents[playerId]
+	if !ok {
+		return
+	}
+
+	if events.datagramSize == 0 && size > 0 {
+		es.datagrams.users++
+	} else if events.datagramSize > 0 && size == 0 {
+		es.datagrams.users--
+	}
+
+	events.datagramSize = size
+}
+
+// HasDatagramPlayers tells if some player gets its moves in datagrams.
+func (es *EventCollector) HasDatagramPlayers() bool {
+	return es.datagrams.users > 0
+}
+
+// AddDatagramMoves adds the players sent in the datagrams of this tick, the ones that moved
+// in the last DatagramRepeats ticks with their current state.
+func (es *EventCollector) AddDatagramMoves(players []types.Player) {
+	es.datagrams.moves = append(es.datagrams.moves[:0], players...)
+}
+
+// AddSettledMoves adds the players that stopped being sent in datagrams this tick, with their
+// current state. They go in the EventList of the players with datagrams, so the last state of
+// a player arrives even when all its datagrams were lost.
+func (es *EventCollector) AddSettledMoves(bufferPool *BuilderPool, players []types.Player) {
+	if len(players) == 0 {
+		return
+	}
+
+	es.setMoves(&es.datagrams.settled, bufferPool, players)
+}
+
+// GetPlayerDatagrams returns the EventLists sent to the player in datagrams this tick, each
+// fits in its datagram size. They're shared by the players with the same codec and valid
+// until Reset.
+func (es *EventCollector) GetPlayerDatagrams(playerId int) [][]byte {
+	events, ok := es.playerEvents[playerId]
+	if !ok || events.datagramSize == 0 || len(es.datagrams.moves) == 0 {
+		return nil
+	}
+
+	codec, ok := es.playerCodecs[playerId]
+	if !ok {
+		codec = types.IntegerCodec
+	}
+
+	key := datagramKey{codec: codec, size: events.datagramSize}
+
+	datagrams, ok := es.datagrams.built[key]
+	if !ok {
+		datagrams = es.datagrams.build(codec, events.datagramSize, es.tick, es.serverTime)
+		es.datagrams.built[key] = datagrams
+	}
+
+	return datagrams
+}
+
+// build splits the moves in EventLists of one PlayerMovedList each.
+func (ds *datagramState) build(codec types.PositionCodec, size int, tick uint32, serverTime int64) [][]byte {
+	perDatagram := max(1, (size-datagramOverhead)/movedPlayerSize)
+
+	var datagrams [][]byte
+
+	for start := 0; start < len(ds.moves); start += perDatagram {
+		moves := ds.moves[start:min(start+perDatagram, len(ds.moves))]
+
+		// the scratch builder can be reused right away, the list is copied into the EventList
+		ds.scratch.Reset()
+		flatPlayerMovedList := utils.NewFlatPlayerMovedList(ds.scratch, moves, codec)
+
+		elb := EventListBuilder{builder: ds.builder(), tick: tick, serverTime: serverTime}
+		rawEvent := elb.addRawEvent(utils.NewEventHolder(flatgen.EventKindPlayerMovedList, flatPlayerMovedList))
+		elb.finish([]flatbuffers.UOffsetT{rawEvent})
+
+		datagrams = append(datagrams, elb.builder.FinishedBytes())
+	}
+
+	return datagrams
+}
+
+// builder returns a builder for a datagram, they're reused once the tick is over.
+func (ds *datagramState) builder() *flatbuffers.Builder {
+	if ds.usedBuilders == len(ds.builders) {
+		ds.builders = append(ds.builders, flatbuffers.NewBuilder(1024))
+	}
+
+	builder := ds.builders[ds.usedBuilders]
+	builder.Reset()
+	ds.usedBuilders++
+
+	return builder
+}
+
+func (ds *datagramState) reset() {
+	clear(ds.built)
+	clear(ds.moves)
+	ds.moves = ds.moves[:0]
+	ds.usedBuilders = 0
+
+	ds.settled.reset()
+}
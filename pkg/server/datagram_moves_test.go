@@ -0,0 +1,160 @@
+package server_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/verify"
+)
+
+func TestDatagramMoves(t *testing.T) {
+	const datagramSize = 1100
+
+	ec := server.NewEventCollector()
+	pool := server.NewBuilderPool(512, 64)
+	players := testPlayers(100)
+
+	ec.SetTick(7, 0)
+	fillTick(ec, pool, players)
+
+	// players 1 and 4 use the Integer codec, 3 uses FixedPoint
+	for _, i := range []int{1, 3, 4} {
+		ec.SetDatagramSize(players[i].Id, datagramSize)
+	}
+
+	if !ec.HasDatagramPlayers() {
+		t.Fatal("expected players that get their moves in datagrams")
+	}
+
+	ec.AddDatagramMoves(players)
+
+	eventList, _ := ec.GetPlayerEventList(players[1].Id)
+	if kinds := eventKinds(eventList); slices.Contains(kinds, flatgen.EventKindPlayerMovedList) || !slices.Contains(kinds, flatgen.EventKindPlayerQuit) {
+		t.Fatalf("expected the EventList without the moves, got %v", kinds)
+	}
+
+	eventList, _ = ec.GetPlayerEventList(players[2].Id)
+	if kinds := eventKinds(eventList); !slices.Contains(kinds, flatgen.EventKindPlayerMovedList) {
+		t.Fatalf("expected the moves in the EventList of a player without datagrams, got %v", kinds)
+	}
+
+	if datagrams := ec.GetPlayerDatagrams(players[2].Id); datagrams != nil {
+		t.Fatalf("expected no datagrams for a player without them, got %v", len(datagrams))
+	}
+
+	datagrams := ec.GetPlayerDatagrams(players[1].Id)
+	if len(datagrams) < 2 {
+		t.Fatalf("expected the moves split in several datagrams, got %v", len(datagrams))
+	}
+
+	moved := map[int32]bool{}
+	player := &flatgen.Player{}
+
+	for _, datagram := range datagrams {
+		if len(datagram) > datagramSize {
+			t.Fatalf("expected datagrams of at most %v bytes, got %v", datagramSize, len(datagram))
+		}
+
+		if err := verify.Root(datagram, verify.EventList); err != nil {
+			t.Fatal(err)
+		}
+
+		eventList := flatgen.GetRootAsEventList(datagram, 0)
+		if eventList.Tick() != 7 {
+			t.Fatalf("expected the datagram of tick 7, got %v", eventList.Tick())
+		}
+
+		for _, raw := range rawEvents(eventList) {
+			_, data, err := utils.ParseEventBytes(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			movedList := data.(*flatgen.PlayerMovedList)
+			for i := range movedList.PlayersLength() {
+				movedList.Players(player, i)
+				moved[player.Id()] = true
+			}
+		}
+	}
+
+	if len(moved) != len(players) {
+		t.Fatalf("expected the moves of %v players in the datagrams, got %v", len(players), len(moved))
+	}
+
+	// the datagrams are built once per codec
+	if other := ec.GetPlayerDatagrams(players[4].Id); &other[0][0] != &datagrams[0][0] {
+		t.Fatal("expected the players with the same codec to share the datagrams")
+	}
+
+	if other := ec.GetPlayerDatagrams(players[3].Id); &other[0][0] == &datagrams[0][0] {
+		t.Fatal("expected the players with another codec to get their own datagrams")
+	}
+
+	ec.Reset()
+
+	if datagrams := ec.GetPlayerDatagrams(players[1].Id); datagrams != nil {
+		t.Fatalf("expected no datagrams once the tick is over, got %v", len(datagrams))
+	}
+
+	for _, i := range []int{1, 3, 4} {
+		ec.RemovePlayer(players[i].Id)
+	}
+
+	if ec.HasDatagramPlayers() {
+		t.Fatal("expected no players with datagrams once they left")
+	}
+}
+
+func TestSettledMovesGoInTheEventList(t *testing.T) {
+	ec := server.NewEventCollector()
+	pool := server.NewBuilderPool(512, 64)
+	players := testPlayers(4)
+
+	fillTick(ec, pool, players)
+	ec.SetDatagramSize(players[1].Id, 1100)
+
+	// player 3 stopped being sent in datagrams
+	ec.AddSettledMoves(pool, players[3:])
+
+	eventList, _ := ec.GetPlayerEventList(players[1].Id)
+
+	settled := 0
+	player := &flatgen.Player{}
+
+	for _, raw := range rawEvents(eventList) {
+		kind, data, err := utils.ParseEventBytes(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if kind != flatgen.EventKindPlayerMovedList {
+			continue
+		}
+
+		movedList := data.(*flatgen.PlayerMovedList)
+		for i := range movedList.PlayersLength() {
+			movedList.Players(player, i)
+
+			if int(player.Id()) != players[3].Id {
+				t.Fatalf("expected only the settled player in the EventList, got %v", player.Id())
+			}
+
+			settled++
+		}
+	}
+
+	if settled != 1 {
+		t.Fatalf("expected the settled player once, got %v", settled)
+	}
+
+	// the others got it in their moves already
+	eventList, _ = ec.GetPlayerEventList(players[2].Id)
+	kinds := eventKinds(eventList)
+	if moveLists := len(kinds) - len(slices.DeleteFunc(slices.Clone(kinds), func(kind flatgen.EventKind) bool { return kind == flatgen.EventKindPlayerMovedList })); moveLists != 1 {
+		t.Fatalf("expected one PlayerMovedList for a player without datagrams, got %v", kinds)
+	}
+}
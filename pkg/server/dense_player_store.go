@@ -0,0 +1,71 @@
+package server
+
+import (
+	"iter"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+)
+
+// DensePlayerStore keeps the players in the same slots as the entities' components, a slice
+// the tick walks in order with the ids only going through a map to find a player's slot. A
+// player keeps its slot until it's deleted and freed slots are reused by the next players, so
+// the iteration order stays the same between ticks.
+//
+// It's not safe for concurrent use, everything has to happen on the tick's goroutine.
+type DensePlayerStore struct {
+	players *ecs.Components[PlayerWithSocket]
+}
+
+func NewDensePlayerStore() *DensePlayerStore {
+	return &DensePlayerStore{
+		players: ecs.NewComponents[PlayerWithSocket](),
+	}
+}
+
+func (ps *DensePlayerStore) Get(id int) (player PlayerWithSocket, ok bool) {
+	ref, ok := ps.players.Get(id)
+	if !ok {
+		return player, false
+	}
+
+	return *ref, true
+}
+
+// Ref returns the stored player so it can be changed in place. The pointer is only valid
+// until the next Set or Delete.
+func (ps *DensePlayerStore) Ref(id int) (*PlayerWithSocket, bool) {
+	return ps.players.Get(id)
+}
+
+func (ps *DensePlayerStore) Set(id int, player PlayerWithSocket) {
+	ps.players.Set(id, player)
+}
+
+// Delete frees the player's slot, the zero value left in it drops the player's conn and buffers.
+func (ps *DensePlayerStore) Delete(id int) {
+	ps.players.Delete(id)
+}
+
+func (ps *DensePlayerStore) Len() int {
+	return ps.players.Len()
+}
+
+// All yields the players in slot order. Players deleted during the iteration are skipped,
+// players added to a free slot behind it are not seen until the next one.
+func (ps *DensePlayerStore) All() iter.Seq2[int, PlayerWithSocket] {
+	return func(yield func(int, PlayerWithSocket) bool) {
+		for id, player := range ps.players.All() {
+			if !yield(id, *player) {
+				return
+			}
+		}
+	}
+}
+
+// MutablePlayerStore is a PlayerStore that lets the tick change its players in place instead
+// of copying them back with Set.
+type MutablePlayerStore interface {
+	PlayerStore
+	Ref(id int) (*PlayerWithSocket, bool)
+}
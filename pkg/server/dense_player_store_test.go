@@ -0,0 +1,97 @@
+package server_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+)
+
+func storeIds(store server.PlayerStore) []int {
+	ids := []int{}
+
+	for id := range store.All() {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestDensePlayerStoreKeepsItsOrder(t *testing.T) {
+	store := server.NewDensePlayerStore()
+
+	for _, id := range []int{5, 2, 9, 7} {
+		store.Set(id, types.PlayerWithSocket{Player: types.Player{Id: id}})
+	}
+
+	store.Set(2, types.PlayerWithSocket{Player: types.Player{Id: 2, X: 10}})
+
+	if ids := storeIds(store); !slices.Equal(ids, []int{5, 2, 9, 7}) {
+		t.Fatalf("expected the players in the order they joined, got %v", ids)
+	}
+
+	store.Delete(9)
+	store.Delete(9)
+
+	if _, ok := store.Get(9); ok {
+		t.Fatal("player 9 was deleted but it's still in the store")
+	}
+
+	// the new player takes the free slot, the others don't move
+	store.Set(11, types.PlayerWithSocket{Player: types.Player{Id: 11}})
+
+	if ids := storeIds(store); !slices.Equal(ids, []int{5, 2, 11, 7}) {
+		t.Fatalf("expected 11 in 9's slot, got %v", ids)
+	}
+
+	if store.Len() != 4 {
+		t.Fatalf("expected 4 players, got %v", store.Len())
+	}
+
+	if player, _ := store.Get(2); player.X != 10 {
+		t.Fatalf("expected the second Set to replace player 2, got %+v", player.Player)
+	}
+}
+
+func TestDensePlayerStoreChangesInPlace(t *testing.T) {
+	store := server.NewDensePlayerStore()
+	store.Set(1, types.PlayerWithSocket{Player: types.Player{Id: 1}})
+
+	ref, ok := store.Ref(1)
+	if !ok {
+		t.Fatal("player 1 is missing")
+	}
+
+	ref.X, ref.Y = 3, 4
+
+	if player, _ := store.Get(1); player.X != 3 || player.Y != 4 {
+		t.Fatalf("expected the change to be in the store, got %+v", player.Player)
+	}
+
+	if _, ok := store.Ref(2); ok {
+		t.Fatal("expected no ref for a missing player")
+	}
+}
+
+func TestDensePlayerStoreDeleteWhileIterating(t *testing.T) {
+	store := server.NewDensePlayerStore()
+
+	for id := 1; id <= 4; id++ {
+		store.Set(id, types.PlayerWithSocket{Player: types.Player{Id: id}})
+	}
+
+	seen := []int{}
+
+	for id := range store.All() {
+		seen = append(seen, id)
+
+		if id == 2 {
+			store.Delete(3)
+		}
+	}
+
+	if !slices.Equal(seen, []int{1, 2, 4}) {
+		t.Fatalf("expected the deleted player to be skipped, got %v", seen)
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+)
+
+// SpawnEntity adds an entity to game.Entities with an id no player or other entity has, its
+// components are set after. The players get it with the next events.
+func (game *GameServer) SpawnEntity(kind ecs.Kind) int {
+	id := game.IdGenerator.NewId()
+	game.Entities.Spawn(id, kind)
+
+	return id
+}
+
+// addEntityEvents sends everyone the entities that changed since the last tick, the spawned
+// and updated ones in the position encoding of every codec. The updates of a tick go in one
+// EntityUpdated per codec, they are latest-wins per entity.
+func (game *GameServer) addEntityEvents(bufferPool *BuilderPool) {
+	spawned, updated, despawned := game.Entities.Changes()
+	defer game.Entities.ClearChanges()
+
+	if len(despawned) > 0 {
+		// an update that wasn't sent yet would bring the entity back on the clients
+		for _, id := range despawned {
+			game.EventCollector.ForgetEntity(id)
+		}
+
+		entityDespawned := utils.NewFlatEntityDespawned(bufferPool.GetFreeBuilder(), despawned)
+		game.Send(ToAll(), utils.NewEventHolder(flatgen.EventKindEntityDespawned, entityDespawned))
+	}
+
+	if len(spawned) == 0 && len(updated) == 0 {
+		return
+	}
+
+	states := game.Entities.States(game.entityStates[:0], spawned)
+	states = game.Entities.States(states, updated)
+	game.entityStates = states
+
+	spawnedStates, updatedStates := states[:len(spawned)], states[len(spawned):]
+
+	if len(spawnedStates) > 0 {
+		for _, codec := range game.EventCollector.PositionCodecs() {
+			entitySpawned := utils.NewFlatEntitySpawned(bufferPool.GetFreeBuilder(), spawnedStates, codec)
+			game.EventCollector.AddEncodedEvent(codec, utils.NewEventHolder(flatgen.EventKindEntitySpawned, entitySpawned))
+		}
+	}
+
+	game.EventCollector.AddEntityUpdates(bufferPool, updatedStates)
+}
+
+// sendEntities tells a player that just joined about every entity there is.
+func (game *GameServer) sendEntities(player PlayerWithSocket) {
+	if game.Entities.Len() == 0 {
+		return
+	}
+
+	states := game.entityStates[:0]
+	for id := range game.Entities.All() {
+		states = append(states, game.Entities.State(id))
+	}
+	game.entityStates = states
+
+	entitySpawned := utils.NewFlatEntitySpawned(game.state.BufferPool.GetFreeBuilder(), states, player.Codec)
+	game.Send(ToPlayer(player.Id), utils.NewEventHolder(flatgen.EventKindEntitySpawned, entitySpawned))
+}
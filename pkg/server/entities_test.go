@@ -0,0 +1,182 @@
+package server_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// joinOnPipe connects a player over an in memory connection and confirms its hello, the
+// client's end is returned with the hello already read.
+func joinOnPipe(t *testing.T, game *server.GameServer, id int) transport.Conn {
+	t.Helper()
+
+	client, conn := transport.NewPipeConns("test")
+	t.Cleanup(func() { client.Close() })
+
+	game.EventQueue <- types.Event{PlayerId: id, Kind: flatgen.EventKindPlayerHello, Conn: conn, Data: types.PlayerConnected{}, Lifecycle: true}
+	game.RunTick(context.Background(), time.Millisecond)
+
+	if _, err := client.Read(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	confirm := utils.NewFlatPlayerHelloConfirm(flatbuffers.NewBuilder(64), id, flatgen.PositionEncodingInteger, flatgen.CompressionNone, 0, "")
+	game.EventQueue <- wireEvent(t, game, id, confirm.Table().Bytes)
+
+	return client
+}
+
+// receivedKinds reads the EventLists that are waiting on the client and returns the kinds of
+// their events, every event is given to found.
+func receivedKinds(t *testing.T, client transport.Conn, found func(kind flatgen.EventKind, data []byte)) []flatgen.EventKind {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	kinds := []flatgen.EventKind{}
+
+	for {
+		message, err := client.Read(ctx)
+		if err != nil {
+			return kinds
+		}
+
+		eventList := flatgen.GetRootAsEventList(message, 0)
+		kinds = append(kinds, eventKinds(eventList)...)
+
+		for _, raw := range rawEvents(eventList) {
+			if kind, _, err := utils.ParseEventBytes(raw); err == nil {
+				found(kind, raw)
+			}
+		}
+	}
+}
+
+func TestEntitiesReachThePlayers(t *testing.T) {
+	game := newTestGame()
+	ctx := context.Background()
+
+	first := joinOnPipe(t, game, 1)
+	game.RunTick(ctx, time.Millisecond)
+	receivedKinds(t, first, func(flatgen.EventKind, []byte) {})
+
+	id := game.SpawnEntity(flatgen.EntityKindProjectile)
+	game.Entities.Transforms.Set(id, ecs.Transform{X: 100, Y: 100})
+	game.Entities.Velocities.Set(id, ecs.Velocity{VX: 1000})
+	game.Entities.Colliders.Set(id, ecs.Collider{Size: 4})
+	game.Entities.Owners.Set(id, ecs.Ownership{Owner: 1})
+
+	// spawned and sent in this tick, moved at the end of it
+	game.RunTick(ctx, 100*time.Millisecond)
+
+	spawned := []int32{}
+	receivedKinds(t, first, func(kind flatgen.EventKind, data []byte) {
+		if kind == flatgen.EventKindEntitySpawned {
+			event := flatgen.GetRootAsEntitySpawned(data, 0)
+			entity := &flatgen.Entity{}
+			event.Entities(entity, 0)
+
+			if entity.Kind() != flatgen.EntityKindProjectile || entity.Owner() != 1 || entity.X() != 100 {
+				t.Errorf("expected the projectile as it was spawned, got %v %v %v", entity.Kind(), entity.Owner(), entity.X())
+			}
+
+			spawned = append(spawned, entity.Id())
+		}
+	})
+
+	if !slices.Equal(spawned, []int32{int32(id)}) {
+		t.Fatalf("expected entity %v to be spawned, got %v", id, spawned)
+	}
+
+	game.RunTick(ctx, time.Millisecond)
+
+	updatedX := int32(0)
+	receivedKinds(t, first, func(kind flatgen.EventKind, data []byte) {
+		if kind == flatgen.EventKindEntityUpdated {
+			event := flatgen.GetRootAsEntityUpdated(data, 0)
+			entity := &flatgen.Entity{}
+			event.Entities(entity, 0)
+			updatedX = entity.X()
+		}
+	})
+
+	if updatedX != 200 {
+		t.Fatalf("expected the projectile moved to x 200, got %v", updatedX)
+	}
+
+	// a player that joins later gets every entity
+	second := joinOnPipe(t, game, 2)
+	game.RunTick(ctx, time.Millisecond)
+
+	if kinds := receivedKinds(t, second, func(flatgen.EventKind, []byte) {}); !slices.Contains(kinds, flatgen.EventKindEntitySpawned) {
+		t.Fatalf("expected the entities for the new player, got %v", kinds)
+	}
+
+	// the projectile goes away with its owner
+	game.EventQueue <- types.Event{PlayerId: 1, Kind: flatgen.EventKindPlayerQuit, Data: types.PlayerDisconnected{}, Lifecycle: true}
+	game.RunTick(ctx, time.Millisecond)
+
+	if game.Entities.Len() != 0 {
+		t.Fatalf("expected the owner's entities to be despawned, %v left", game.Entities.Len())
+	}
+
+	if kinds := receivedKinds(t, second, func(flatgen.EventKind, []byte) {}); !slices.Contains(kinds, flatgen.EventKindEntityDespawned) {
+		t.Fatalf("expected the despawn, got %v", kinds)
+	}
+}
+
+func TestEntityUpdatesOfATickGoInOneEvent(t *testing.T) {
+	game := newTestGame()
+	ctx := context.Background()
+
+	client := joinOnPipe(t, game, 1)
+
+	ids := []int{}
+	for i := range 5 {
+		id := game.SpawnEntity(flatgen.EntityKindNpc)
+		game.Entities.Transforms.Set(id, ecs.Transform{X: float64(i)})
+		ids = append(ids, id)
+	}
+
+	game.RunTick(ctx, time.Millisecond)
+	receivedKinds(t, client, func(flatgen.EventKind, []byte) {})
+
+	for _, id := range ids {
+		game.Entities.Transforms.Set(id, ecs.Transform{X: 50})
+		game.Entities.Changed(id)
+	}
+
+	game.RunTick(ctx, time.Millisecond)
+
+	events, updated := 0, []int{}
+	receivedKinds(t, client, func(kind flatgen.EventKind, data []byte) {
+		if kind != flatgen.EventKindEntityUpdated {
+			return
+		}
+
+		events++
+
+		event, entity := flatgen.GetRootAsEntityUpdated(data, 0), &flatgen.Entity{}
+		for i := range event.EntitiesLength() {
+			event.Entities(entity, i)
+			updated = append(updated, int(entity.Id()))
+		}
+	})
+
+	slices.Sort(updated)
+	if events != 1 || !slices.Equal(updated, ids) {
+		t.Fatalf("expected one EntityUpdated with entities %v, got %d with %v", ids, events, updated)
+	}
+}
@@ -0,0 +1,702 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// MaxBytesPerTick is how many bytes of events a player is sent in one tick, the events that
+// don't fit are deferred to the next ticks. The first event always goes, so one bigger than
+// the budget can't block the rest. 0 means no limit.
+var MaxBytesPerTick = 16 * 1024
+
+// EventPriorities is how important latest-wins events of a kind are, kinds that are not listed
+// have priority 1. A deferred event piles up its priority every tick until it's sent.
+var EventPriorities = map[flatgen.EventKind]float64{
+	flatgen.EventKindIdleWarning: 4,
+	// an entity update is about one entity, it goes before the moves of far away players
+	flatgen.EventKindEntityUpdated: 2,
+}
+
+// PriorityDistance is how far from a player a move is half as important as its own moves.
+var PriorityDistance = 400.0
+
+const (
+	// eventOverhead is what an event costs in the EventList besides its bytes: the RawEvent
+	// table, its vtable, the length of the byte vector, padding and the offset to it.
+	eventOverhead = 32
+	// movedPlayerSize is the size of a flatgen.Player struct in a PlayerMovedList
+	movedPlayerSize = 32
+	// updatedEntitySize is the size of a flatgen.Entity struct in an EntityUpdated
+	updatedEntitySize = 32
+)
+
+func EventPriority(kind flatgen.EventKind) float64 {
+	priority, ok := EventPriorities[kind]
+	if !ok {
+		return 1
+	}
+
+	return priority
+}
+
+func eventSize(event types.EventHolder) int {
+	return len(event.Bytes()) + eventOverhead
+}
+
+type byteBudget struct {
+	used  int
+	limit int
+}
+
+func (bb *byteBudget) fits(size int) bool {
+	return bb.limit <= 0 || bb.used+size <= bb.limit
+}
+
+// fitsOne is fits for a single event, the first one of a tick is sent even if it's too big
+func (bb *byteBudget) fitsOne(size int) bool {
+	return bb.used == 0 || bb.fits(size)
+}
+
+func (bb *byteBudget) use(size int) {
+	bb.used += size
+}
+
+// detach copies the bytes of the event, deferred events outlive the builders they were built in.
+func detach(collected collectedEvent) collectedEvent {
+	collected.event = &types.FlatEventBytes{
+		EventKind: collected.event.Kind(),
+		Event:     bytes.Clone(collected.event.Bytes()),
+	}
+
+	return collected
+}
+
+// candidate is a latest-wins event competing for what's left of the budget
+type candidate struct {
+	key       latestKey
+	size      int
+	score     float64
+	collected collectedEvent
+}
+
+// slottedMove is a move with the slot of its player, see eventBudget.slots. score is what a
+// deferred move piled up, it's kept in moveScores at the end of the tick.
+type slottedMove struct {
+	slot   int32
+	score  float64
+	player types.Player
+}
+
+// moveSet is a list of players that moved, with their PlayerMovedList for every codec.
+type moveSet struct {
+	moves []slottedMove
+	ids   map[int]struct{}
+	lists map[types.PositionCodec]types.EventHolder
+	seq   uint64
+}
+
+func newMoveSet() moveSet {
+	return moveSet{
+		ids:   map[int]struct{}{},
+		lists: map[types.PositionCodec]types.EventHolder{},
+	}
+}
+
+func (ms *moveSet) reset() {
+	clear(ms.moves)
+	ms.moves = ms.moves[:0]
+	clear(ms.ids)
+	clear(ms.lists)
+}
+
+// scoredUpdate is an entity update with the priority it piled up while it was deferred.
+type scoredUpdate struct {
+	score float64
+	state ecs.State
+}
+
+// updateSet is a list of entities that changed, with their EntityUpdated for every codec.
+type updateSet struct {
+	states []ecs.State
+	ids    map[int]struct{}
+	lists  map[types.PositionCodec]types.EventHolder
+	seq    uint64
+}
+
+func newUpdateSet() updateSet {
+	return updateSet{
+		ids:   map[int]struct{}{},
+		lists: map[types.PositionCodec]types.EventHolder{},
+	}
+}
+
+func (us *updateSet) reset() {
+	us.states = us.states[:0]
+	clear(us.ids)
+	clear(us.lists)
+}
+
+// better tells if the update goes before the other one, the id of the entity breaks the ties so
+// the same updates are selected every time the frame of a player is built in a tick.
+func (su scoredUpdate) better(other scoredUpdate) bool {
+	return su.score > other.score || (su.score == other.score && su.state.Id < other.state.Id)
+}
+
+// rankedMove is what moves are selected by, it's kept small since there can be one for every
+// player in the game.
+type rankedMove struct {
+	score    float64
+	index    int32
+	deferred bool
+}
+
+func movePriority(events *playerEvents, player *types.Player, priority float64) float64 {
+	if !events.hasViewpoint {
+		return priority
+	}
+
+	dx, dy := player.X-events.x, player.Y-events.y
+
+	return priority * PriorityDistance / (PriorityDistance + math.Sqrt(dx*dx+dy*dy))
+}
+
+// eventBudget picks what of a tick fits in the budget of a player. What it selects with is
+// reused from player to player.
+type eventBudget struct {
+	// slots numbers the players that moved, so the move scores of a player can be kept in a slice
+	slots     map[int]int32
+	freeSlots []int32
+
+	ordered  []collectedEvent
+	reliable []collectedEvent
+	latest   map[latestKey]collectedEvent
+	// held are the players and entities whose join or spawn was deferred, their latest-wins
+	// events and moves wait for it so the client knows them first
+	held       map[int]struct{}
+	candidates []candidate
+	ranking    []rankedMove
+	selected   []types.Player
+	scratch    *flatbuffers.Builder
+
+	updates        []scoredUpdate
+	selectedStates []ecs.State
+	updateScratch  *flatbuffers.Builder
+}
+
+func newEventBudget() eventBudget {
+	return eventBudget{
+		slots:         map[int]int32{},
+		latest:        map[latestKey]collectedEvent{},
+		held:          map[int]struct{}{},
+		scratch:       flatbuffers.NewBuilder(1024),
+		updateScratch: flatbuffers.NewBuilder(1024),
+	}
+}
+
+// slot returns the slot of the player, slots are reused once players leave
+func (eb *eventBudget) slot(playerId int) int32 {
+	if slot, ok := eb.slots[playerId]; ok {
+		return slot
+	}
+
+	var slot int32
+	if n := len(eb.freeSlots); n > 0 {
+		slot, eb.freeSlots = eb.freeSlots[n-1], eb.freeSlots[:n-1]
+	} else {
+		slot = int32(len(eb.slots))
+	}
+
+	eb.slots[playerId] = slot
+
+	return slot
+}
+
+func (eb *eventBudget) hold(id int) {
+	eb.held[id] = struct{}{}
+}
+
+func (eb *eventBudget) isHeld(id int) bool {
+	_, ok := eb.held[id]
+
+	return ok
+}
+
+// introduces calls add with the players and entities the event makes known to the client.
+func introduces(event types.EventHolder, add func(id int)) {
+	switch event.Kind() {
+	case flatgen.EventKindEntitySpawned:
+		spawned, entity := flatgen.GetRootAsEntitySpawned(event.Bytes(), 0), &flatgen.Entity{}
+
+		for i := range spawned.EntitiesLength() {
+			spawned.Entities(entity, i)
+			add(int(entity.Id()))
+		}
+	case flatgen.EventKindPlayerJoined:
+		if player := flatgen.GetRootAsPlayerJoined(event.Bytes(), 0).Player(nil); player != nil {
+			add(int(player.Id()))
+		}
+	case flatgen.EventKindPlayerJoinedList:
+		joined, player := flatgen.GetRootAsPlayerJoinedList(event.Bytes(), 0), &flatgen.Player{}
+
+		for i := range joined.PlayersLength() {
+			joined.Players(player, i)
+			add(int(player.Id()))
+		}
+	}
+}
+
+// release frees the slot of the player, if it had one.
+func (eb *eventBudget) release(playerId int) (int32, bool) {
+	slot, ok := eb.slots[playerId]
+	if !ok {
+		return 0, false
+	}
+
+	delete(eb.slots, playerId)
+	eb.freeSlots = append(eb.freeSlots, slot)
+
+	return slot, true
+}
+
+func (eb *eventBudget) rankMove(events *playerEvents, player *types.Player, slot int32, priority float64, index int, deferred bool) {
+	score := events.moveScores[slot] + movePriority(events, player, priority)
+
+	eb.ranking = append(eb.ranking, rankedMove{score: score, index: int32(index), deferred: deferred})
+}
+
+// holdMove defers the move of a player the client doesn't know yet, with the score it would
+// have been ranked with.
+func (eb *eventBudget) holdMove(events *playerEvents, move *slottedMove, priority float64) {
+	pending := *move
+	pending.score = events.moveScores[move.slot] + movePriority(events, &move.player, priority)
+
+	events.pendingMoves = append(events.pendingMoves, pending)
+}
+
+// endTick queues what was deferred for the next tick and piles up its priority. It only
+// grows here, once per tick, so building the frame of a player doesn't change it.
+func (events *playerEvents) endTick() {
+	events.builder.builder.Reset()
+	events.builder.events = events.builder.events[:0]
+
+	events.queue.reset()
+	events.queue, events.pending = events.pending, events.queue
+
+	for key := range events.accumulated {
+		if _, ok := events.queue.latest[key]; !ok {
+			delete(events.accumulated, key)
+		}
+	}
+
+	for key := range events.queue.latest {
+		events.accumulated[key] += EventPriority(key.kind)
+	}
+
+	// only the deferred moves have a score, the ones of the last tick are cleared first
+	for _, move := range events.deferredMoves {
+		events.moveScores[move.slot] = 0
+	}
+
+	events.deferredMoves, events.pendingMoves = events.pendingMoves, events.deferredMoves[:0]
+
+	for _, move := range events.deferredMoves {
+		events.moveScores[move.slot] = move.score
+	}
+
+	events.deferredUpdates, events.pendingUpdates = events.pendingUpdates, events.deferredUpdates
+	clear(events.pendingUpdates)
+}
+
+// selectTop moves the k best ranked moves to the front of ranking, in no particular order.
+func selectTop(ranking []rankedMove, k int) {
+	lo, hi := 0, len(ranking)-1
+
+	for lo < hi {
+		pivot := ranking[lo+(hi-lo)/2].score
+		i, j := lo, hi
+
+		for i <= j {
+			for ranking[i].score > pivot {
+				i++
+			}
+
+			for ranking[j].score < pivot {
+				j--
+			}
+
+			if i <= j {
+				ranking[i], ranking[j] = ranking[j], ranking[i]
+				i++
+				j--
+			}
+		}
+
+		switch {
+		case k-1 <= j:
+			hi = j
+		case k-1 >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// selectTopUpdates is selectTop for the entity updates.
+func selectTopUpdates(updates []scoredUpdate, k int) {
+	lo, hi := 0, len(updates)-1
+
+	for lo < hi {
+		pivot := updates[lo+(hi-lo)/2]
+		i, j := lo, hi
+
+		for i <= j {
+			for updates[i].better(pivot) {
+				i++
+			}
+
+			for pivot.better(updates[j]) {
+				j--
+			}
+
+			if i <= j {
+				updates[i], updates[j] = updates[j], updates[i]
+				i++
+				j--
+			}
+		}
+
+		switch {
+		case k-1 <= j:
+			hi = j
+		case k-1 >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// selectEvents returns the events of the queues that fit in the budget of the player, in the
+// order they were added, with its moves and entity updates. What doesn't fit is deferred to
+// events.pending, events.pendingMoves and events.pendingUpdates, and so are the latest-wins
+// events, moves and updates of the players and entities whose join or spawn is deferred. The
+// list is valid until the next call.
+func (eb *eventBudget) selectEvents(events *playerEvents, queues []*eventQueue, codec types.PositionCodec, moves *moveSet, updates *updateSet) []collectedEvent {
+	// Everything meant for the player is merged back in the order it was added, a latest-wins
+	// event of the player replaces a general one of the same entity if it's newer.
+	eb.reliable = eb.reliable[:0]
+	clear(eb.latest)
+	clear(eb.held)
+
+	for _, queue := range queues {
+		if queue == nil {
+			continue
+		}
+
+		eb.reliable = append(eb.reliable, queue.reliable...)
+
+		for key, collected := range queue.latest {
+			if newest, ok := eb.latest[key]; !ok || newest.seq < collected.seq {
+				eb.latest[key] = collected
+			}
+		}
+	}
+
+	slices.SortFunc(eb.reliable, func(a, b collectedEvent) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	events.pending.reset()
+	events.pendingMoves = events.pendingMoves[:0]
+	clear(events.pendingUpdates)
+
+	eb.ordered = eb.ordered[:0]
+	budget := byteBudget{limit: MaxBytesPerTick}
+
+	// Reliable events go first, once one doesn't fit the ones after it wait for it. Immediate
+	// events go anyway and aren't counted.
+	deferring := false
+
+	for _, collected := range eb.reliable {
+		switch size := eventSize(collected.event); {
+		case EventChannels[collected.event.Kind()] == ChannelImmediate:
+		case deferring || !budget.fitsOne(size):
+			deferring = true
+			events.pending.reliable = append(events.pending.reliable, detach(collected))
+			introduces(collected.event, eb.hold)
+
+			continue
+		default:
+			budget.use(size)
+		}
+
+		eb.ordered = append(eb.ordered, collected)
+	}
+
+	eb.selectLatest(events, codec, moves, updates, &budget)
+
+	slices.SortStableFunc(eb.ordered, func(a, b collectedEvent) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	return eb.ordered
+}
+
+// selectLatest adds the latest-wins events, the entity updates and the moves that fit in the
+// budget to eb.ordered, the most important first. Latest-wins events are few and small so they
+// go before the updates, which go before the moves. What doesn't fit is deferred.
+func (eb *eventBudget) selectLatest(events *playerEvents, codec types.PositionCodec, moves *moveSet, updates *updateSet, budget *byteBudget) {
+	total := 0
+	for _, collected := range eb.latest {
+		total += eventSize(collected.event)
+	}
+
+	movesList, hasMovesList := moves.lists[codec]
+
+	if hasMovesList {
+		total += eventSize(movesList)
+	}
+
+	updatesList, hasUpdatesList := updates.lists[codec]
+
+	if hasUpdatesList {
+		total += eventSize(updatesList)
+	}
+
+	if missing := len(eb.slots) + len(eb.freeSlots) - len(events.moveScores); missing > 0 {
+		events.moveScores = append(events.moveScores, make([]float64, missing)...)
+	}
+
+	// Usually everything fits and the shared PlayerMovedList and EntityUpdated are sent as they are
+	allShared := (hasMovesList || len(moves.moves) == 0) && (hasUpdatesList || len(updates.states) == 0)
+
+	if len(events.deferredMoves) == 0 && len(events.deferredUpdates) == 0 && len(eb.held) == 0 && allShared && budget.fits(total) {
+		for _, collected := range eb.latest {
+			eb.ordered = append(eb.ordered, collected)
+		}
+
+		if hasUpdatesList {
+			eb.ordered = append(eb.ordered, collectedEvent{seq: updates.seq, event: updatesList})
+		}
+
+		if hasMovesList {
+			eb.ordered = append(eb.ordered, collectedEvent{seq: moves.seq, event: movesList})
+		}
+
+		budget.use(total)
+
+		return
+	}
+
+	eb.candidates = eb.candidates[:0]
+
+	for key, collected := range eb.latest {
+		if eb.isHeld(key.entity) {
+			events.pending.latest[key] = detach(collected)
+			continue
+		}
+
+		score := events.accumulated[key] + EventPriority(key.kind)
+
+		eb.candidates = append(eb.candidates, candidate{key: key, size: eventSize(collected.event), score: score, collected: collected})
+	}
+
+	slices.SortFunc(eb.candidates, func(a, b candidate) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.collected.seq, b.collected.seq)
+	})
+
+	for _, c := range eb.candidates {
+		if !budget.fitsOne(c.size) {
+			events.pending.latest[c.key] = detach(c.collected)
+			continue
+		}
+
+		budget.use(c.size)
+		eb.ordered = append(eb.ordered, c.collected)
+	}
+
+	eb.selectUpdates(events, codec, updates, budget)
+
+	eb.ranking = eb.ranking[:0]
+	priority := EventPriority(flatgen.EventKindPlayerMovedList)
+
+	for i := range moves.moves {
+		if eb.isHeld(moves.moves[i].player.Id) {
+			eb.holdMove(events, &moves.moves[i], priority)
+			continue
+		}
+
+		eb.rankMove(events, &moves.moves[i].player, moves.moves[i].slot, priority, i, false)
+	}
+
+	for i := range events.deferredMoves {
+		// a move of this tick replaces the deferred one
+		if _, moved := moves.ids[events.deferredMoves[i].player.Id]; moved {
+			continue
+		}
+
+		if eb.isHeld(events.deferredMoves[i].player.Id) {
+			eb.holdMove(events, &events.deferredMoves[i], priority)
+			continue
+		}
+
+		eb.rankMove(events, &events.deferredMoves[i].player, events.deferredMoves[i].slot, priority, i, true)
+	}
+
+	if len(eb.ranking) == 0 {
+		return
+	}
+
+	// Moves have the same size, so the ones that fit are the best k
+	k := len(eb.ranking)
+	if budget.limit > 0 {
+		// the PlayerMovedList the moves go in
+		listSize := eventOverhead + movedPlayerSize
+
+		k = min(k, max(0, budget.limit-budget.used-listSize)/movedPlayerSize)
+		if k == 0 && budget.used == 0 {
+			k = 1
+		}
+	}
+
+	selectTop(eb.ranking, k)
+
+	eb.selected = eb.selected[:0]
+
+	for i, r := range eb.ranking {
+		var move *slottedMove
+		if r.deferred {
+			move = &events.deferredMoves[r.index]
+		} else {
+			move = &moves.moves[r.index]
+		}
+
+		if i >= k {
+			pending := *move
+			pending.score = r.score
+			events.pendingMoves = append(events.pendingMoves, pending)
+
+			continue
+		}
+
+		eb.selected = append(eb.selected, move.player)
+	}
+
+	if k > 0 {
+		budget.use(eventOverhead + movedPlayerSize + k*movedPlayerSize)
+
+		// The scratch builder can be reused right away, the list is copied into the EventList.
+		// Deferred moves are older than everything of this tick but they still go after it.
+		eb.scratch.Reset()
+
+		flatPlayerMovedList := utils.NewFlatPlayerMovedList(eb.scratch, eb.selected, codec)
+		eb.ordered = append(eb.ordered, collectedEvent{
+			seq:   moves.seq,
+			event: utils.NewEventHolder(flatgen.EventKindPlayerMovedList, flatPlayerMovedList),
+		})
+	}
+}
+
+// selectUpdates adds the entity updates that fit in the budget to eb.ordered, in one
+// EntityUpdated, the ones that piled up the most priority first. An update of this tick
+// replaces the deferred one of its entity and keeps its priority. What doesn't fit is deferred.
+func (eb *eventBudget) selectUpdates(events *playerEvents, codec types.PositionCodec, updates *updateSet, budget *byteBudget) {
+	priority := EventPriority(flatgen.EventKindEntityUpdated)
+	held := 0
+
+	eb.updates = eb.updates[:0]
+
+	for _, state := range updates.states {
+		update := scoredUpdate{score: events.deferredUpdates[state.Id].score + priority, state: state}
+
+		if eb.isHeld(state.Id) {
+			events.pendingUpdates[state.Id] = update
+			held++
+
+			continue
+		}
+
+		eb.updates = append(eb.updates, update)
+	}
+
+	for id, update := range events.deferredUpdates {
+		if _, updated := updates.ids[id]; updated {
+			continue
+		}
+
+		update.score += priority
+
+		if eb.isHeld(id) {
+			events.pendingUpdates[id] = update
+			continue
+		}
+
+		eb.updates = append(eb.updates, update)
+	}
+
+	if len(eb.updates) == 0 {
+		return
+	}
+
+	// Updates have the same size, so the ones that fit are the best k
+	k := len(eb.updates)
+	if budget.limit > 0 {
+		// the EntityUpdated the updates go in
+		listSize := eventOverhead + updatedEntitySize
+
+		k = min(k, max(0, budget.limit-budget.used-listSize)/updatedEntitySize)
+		if k == 0 && budget.used == 0 {
+			k = 1
+		}
+	}
+
+	if k < len(eb.updates) {
+		selectTopUpdates(eb.updates, k)
+	}
+
+	for _, update := range eb.updates[k:] {
+		events.pendingUpdates[update.state.Id] = update
+	}
+
+	if k == 0 {
+		return
+	}
+
+	budget.use(eventOverhead + updatedEntitySize + k*updatedEntitySize)
+
+	// the shared EntityUpdated has exactly the updates of this tick
+	if list, ok := updates.lists[codec]; ok && held == 0 && k == len(updates.states) && k == len(eb.updates) {
+		eb.ordered = append(eb.ordered, collectedEvent{seq: updates.seq, event: list})
+		return
+	}
+
+	eb.selectedStates = eb.selectedStates[:0]
+	for _, update := range eb.updates[:k] {
+		eb.selectedStates = append(eb.selectedStates, update.state)
+	}
+
+	// Like the moves, the scratch builder can be reused right away, the list is copied into
+	// the EventList and the deferred updates go with the ones of this tick.
+	eb.updateScratch.Reset()
+
+	entityUpdated := utils.NewFlatEntityUpdated(eb.updateScratch, eb.selectedStates, codec)
+	eb.ordered = append(eb.ordered, collectedEvent{
+		seq:   updates.seq,
+		event: utils.NewEventHolder(flatgen.EventKindEntityUpdated, entityUpdated),
+	})
+}
@@ -0,0 +1,444 @@
+package server_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func withBudget(t *testing.T, bytes int) {
+	t.Helper()
+
+	maxBytesPerTick := server.MaxBytesPerTick
+	t.Cleanup(func() { server.MaxBytesPerTick = maxBytesPerTick })
+
+	server.MaxBytesPerTick = bytes
+}
+
+// receivedEvents reads what GetPlayerEventList returned, the ids of the quits and of the moved players.
+func receivedEvents(eventList *flatgen.EventList, _ int) (quits []int32, moves []int32) {
+	if eventList == nil {
+		return nil, nil
+	}
+
+	rawEvent := &flatgen.RawEvent{}
+	player := &flatgen.Player{}
+
+	for i := range eventList.EventsLength() {
+		eventList.Events(rawEvent, i)
+
+		switch flatgen.GetRootAsKindHolder(rawEvent.RawDataBytes(), 0).Kind() {
+		case flatgen.EventKindPlayerQuit:
+			quits = append(quits, flatgen.GetRootAsPlayerQuit(rawEvent.RawDataBytes(), 0).Id())
+		case flatgen.EventKindPlayerMovedList:
+			movedList := flatgen.GetRootAsPlayerMovedList(rawEvent.RawDataBytes(), 0)
+
+			for j := range movedList.PlayersLength() {
+				movedList.Players(player, j)
+				moves = append(moves, player.Id())
+			}
+		}
+	}
+
+	return quits, moves
+}
+
+func TestBudgetDefersReliableEventsInOrder(t *testing.T) {
+	withBudget(t, 150)
+
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+
+	for id := range 6 {
+		ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), id)))
+	}
+
+	received := []int32{}
+
+	for tick := 0; tick < 10 && len(received) < 6; tick++ {
+		quits, _ := receivedEvents(ec.GetPlayerEventList(1))
+		if len(quits) == 0 {
+			t.Fatalf("tick %d: expected at least one event to be sent", tick)
+		}
+
+		if tick == 0 && ec.Deferred(1) == 0 {
+			t.Fatalf("expected some quits to be deferred with a budget of %d bytes", server.MaxBytesPerTick)
+		}
+
+		received = append(received, quits...)
+		ec.Reset()
+	}
+
+	if len(received) != 6 {
+		t.Fatalf("expected the 6 quits to be delivered, got %v", received)
+	}
+
+	for i := range received {
+		if received[i] != int32(i) {
+			t.Fatalf("expected the quits in order, got %v", received)
+		}
+	}
+}
+
+func TestBudgetNeverDefersPings(t *testing.T) {
+	withBudget(t, 150)
+
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+
+	for id := range 6 {
+		ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), id)))
+	}
+
+	ec.AddEvent(1, utils.NewEventHolder(flatgen.EventKindPing, utils.NewFlatPing(flatbuffers.NewBuilder(32), 1)))
+
+	eventList, _ := ec.GetPlayerEventList(1)
+	if ec.Deferred(1) == 0 {
+		t.Fatalf("expected some quits to be deferred with a budget of %d bytes", server.MaxBytesPerTick)
+	}
+
+	if kinds := eventKinds(eventList); !slices.Contains(kinds, flatgen.EventKindPing) {
+		t.Errorf("expected the ping to be sent with the quits that fit, got %v", kinds)
+	}
+}
+
+func TestBudgetSendsClosestMovesFirst(t *testing.T) {
+	withBudget(t, 400)
+
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.SetPositionCodec(1, types.IntegerCodec)
+	ec.SetViewpoint(1, 0, 0)
+
+	players := []types.Player{}
+	for id := 100; id > 0; id-- {
+		players = append(players, types.Player{Id: id, X: float64(id * 50)})
+	}
+
+	ec.AddMoves(server.NewBuilderPool(4096, 1), players)
+
+	_, moves := receivedEvents(ec.GetPlayerEventList(1))
+	if len(moves) == 0 || len(moves) == len(players) {
+		t.Fatalf("expected only some of the moves to fit, got %d", len(moves))
+	}
+
+	for _, id := range moves {
+		if id > int32(len(moves)) {
+			t.Fatalf("expected the %d closest players, got %v", len(moves), moves)
+		}
+	}
+
+	if deferred := ec.Deferred(1); deferred != len(players)-len(moves) {
+		t.Errorf("expected %d deferred moves, got %d", len(players)-len(moves), deferred)
+	}
+
+	// Player 100 leaves, the others are owed to the player until they are sent
+	ec.RemovePlayer(100)
+
+	received := map[int32]bool{}
+	for _, id := range moves {
+		received[id] = true
+	}
+
+	for tick := 0; tick < 100 && ec.Deferred(1) > 0; tick++ {
+		ec.Reset()
+
+		_, moves := receivedEvents(ec.GetPlayerEventList(1))
+		for _, id := range moves {
+			if received[id] {
+				t.Fatalf("move of player %d was sent twice", id)
+			}
+
+			received[id] = true
+		}
+	}
+
+	if len(received) != len(players)-1 || received[100] {
+		t.Errorf("expected every move but the one of the player that left, got %d", len(received))
+	}
+}
+
+func TestBudgetDoesntStarveFarMoves(t *testing.T) {
+	withBudget(t, 400)
+
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.SetPositionCodec(1, types.IntegerCodec)
+	ec.SetViewpoint(1, 0, 0)
+
+	players := []types.Player{}
+	for id := 1; id <= 50; id++ {
+		players = append(players, types.Player{Id: id, X: float64(id * 100)})
+	}
+
+	received := map[int32]bool{}
+	bufferPool := server.NewBuilderPool(4096, 1)
+
+	// Everyone moves every tick, the far players still get their turn
+	for range 50 {
+		ec.AddMoves(bufferPool, players)
+
+		_, moves := receivedEvents(ec.GetPlayerEventList(1))
+		for _, id := range moves {
+			received[id] = true
+		}
+
+		ec.Reset()
+		bufferPool.Reset()
+	}
+
+	if len(received) != len(players) {
+		t.Errorf("expected the moves of all %d players to be sent, got %d", len(players), len(received))
+	}
+}
+
+func TestBudgetSharesMovesThatFit(t *testing.T) {
+	withBudget(t, 0)
+
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.AddPlayer(2)
+	ec.SetPositionCodec(1, types.IntegerCodec)
+	ec.SetPositionCodec(2, types.IntegerCodec)
+
+	ec.AddMoves(server.NewBuilderPool(4096, 1), []types.Player{{Id: 1}, {Id: 2}, {Id: 3}})
+
+	for id := 1; id <= 2; id++ {
+		eventList, count := ec.GetPlayerEventList(id)
+		if _, moves := receivedEvents(eventList, count); count != 1 || len(moves) != 3 {
+			t.Errorf("player %d: expected one list with 3 moves, got %d events and moves %v", id, count, moves)
+		}
+
+		if ec.Deferred(id) != 0 {
+			t.Errorf("player %d: nothing should be deferred without a budget", id)
+		}
+	}
+}
+
+func TestBudgetPriorityGrowsOncePerTick(t *testing.T) {
+	withBudget(t, 400)
+
+	players := []types.Player{}
+	for id := 1; id <= 50; id++ {
+		players = append(players, types.Player{Id: id, X: float64(id * 100)})
+	}
+
+	// the joins of the farthest players take a tick each, their moves are held until then
+	joins := [][]types.Player{players[40:44], players[44:47], players[47:]}
+
+	// a frame built again in the same tick has to leave the next ticks as they were
+	collect := func(builds int) [][]int32 {
+		ec := server.NewEventCollector()
+		ec.AddPlayer(1)
+		ec.SetPositionCodec(1, types.IntegerCodec)
+		ec.SetViewpoint(1, 0, 0)
+
+		for _, joined := range joins {
+			playerJoinedList := utils.NewFlatPlayerJoinedList(flatbuffers.NewBuilder(512), joined, types.IntegerCodec)
+			ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerJoinedList, playerJoinedList))
+		}
+
+		bufferPool := server.NewBuilderPool(4096, 1)
+		ticks := [][]int32{}
+
+		for range 20 {
+			ec.AddMoves(bufferPool, players)
+
+			var moves []int32
+			for range builds {
+				_, moves = receivedEvents(ec.GetPlayerEventList(1))
+			}
+
+			ticks = append(ticks, moves)
+
+			ec.Reset()
+			bufferPool.Reset()
+		}
+
+		return ticks
+	}
+
+	once, thrice := collect(1), collect(3)
+
+	for tick := range once {
+		if !slices.Equal(once[tick], thrice[tick]) {
+			t.Fatalf("tick %d: building the frame 3 times sent %v instead of %v", tick, thrice[tick], once[tick])
+		}
+	}
+
+	// a join goes in every tick, the moves of a player wait for its join
+	for tick, moves := range once {
+		for _, id := range moves {
+			if joinTick := slices.IndexFunc(joins, func(joined []types.Player) bool {
+				return slices.ContainsFunc(joined, func(player types.Player) bool { return player.Id == int(id) })
+			}); joinTick >= tick {
+				t.Fatalf("tick %d: the move of %d was sent before its join in tick %d", tick, id, joinTick)
+			}
+		}
+	}
+
+	// the held moves keep piling up their priority while they wait, so even the farthest goes
+	for _, moves := range once {
+		if slices.Contains(moves, 50) {
+			return
+		}
+	}
+
+	t.Fatalf("expected the held move of the farthest player to be sent in %d ticks", len(once))
+}
+
+func TestBudgetKeepsTheNewestEntityUpdate(t *testing.T) {
+	withBudget(t, 150)
+
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.SetPositionCodec(1, types.IntegerCodec)
+
+	bufferPool := server.NewBuilderPool(512, 8)
+
+	// the x of every entity update that was sent
+	received := func() map[int32][]int32 {
+		eventList, _ := ec.GetPlayerEventList(1)
+		defer bufferPool.Reset()
+		defer ec.Reset()
+
+		updates := map[int32][]int32{}
+		if eventList == nil {
+			return updates
+		}
+
+		entity := &flatgen.Entity{}
+
+		for _, raw := range rawEvents(eventList) {
+			if kind, _, err := utils.ParseEventBytes(raw); err == nil && kind == flatgen.EventKindEntityUpdated {
+				event := flatgen.GetRootAsEntityUpdated(raw, 0)
+
+				for i := range event.EntitiesLength() {
+					event.Entities(entity, i)
+					updates[entity.Id()] = append(updates[entity.Id()], entity.X())
+				}
+			}
+		}
+
+		return updates
+	}
+
+	ec.AddEntityUpdates(bufferPool, []ecs.State{{Id: 1, X: 10}, {Id: 2, X: 10}, {Id: 3, X: 10}})
+
+	first := received()
+	if len(first) == 0 || len(first) == 3 {
+		t.Fatalf("expected some of the updates to be deferred with a budget of %d bytes, got %v", server.MaxBytesPerTick, first)
+	}
+
+	if _, ok := first[3]; ok {
+		t.Fatalf("expected the last update to wait for the ones before it, got %v", first)
+	}
+
+	// a newer update replaces the deferred one of its entity, a despawned entity's is dropped
+	ec.AddEntityUpdates(bufferPool, []ecs.State{{Id: 1, X: 20}, {Id: 2, X: 20}})
+	ec.ForgetEntity(3)
+
+	later := map[int32][]int32{}
+	for range 5 {
+		for id, xs := range received() {
+			later[id] = append(later[id], xs...)
+		}
+	}
+
+	for _, id := range []int32{1, 2} {
+		if !slices.Equal(later[id], []int32{20}) {
+			t.Errorf("expected only the newest update of entity %d, got %v", id, later[id])
+		}
+	}
+
+	if xs, ok := later[3]; ok {
+		t.Errorf("expected no update of the despawned entity, got %v", xs)
+	}
+}
+
+func TestBudgetSendsNothingBeforeItsSpawnOrJoin(t *testing.T) {
+	withBudget(t, 400)
+
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.SetPositionCodec(1, types.IntegerCodec)
+
+	bufferPool := server.NewBuilderPool(512, 8)
+
+	// the spawn is too big for what the quit left, so it's deferred with the join after it
+	// while the small update and the moves would still fit
+	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), 100)))
+
+	spawned := []ecs.State{{Id: 10, X: 1}}
+	for id := 11; id < 30; id++ {
+		spawned = append(spawned, ecs.State{Id: id})
+	}
+
+	entitySpawned := utils.NewFlatEntitySpawned(flatbuffers.NewBuilder(1024), spawned, types.IntegerCodec)
+	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindEntitySpawned, entitySpawned))
+
+	playerJoined := utils.NewFlatPlayerJoined(flatbuffers.NewBuilder(128), types.Player{Id: 20}, types.IntegerCodec)
+	ec.AddEvent(1, utils.NewEventHolder(flatgen.EventKindPlayerJoined, playerJoined))
+
+	ec.AddEntityUpdates(bufferPool, []ecs.State{{Id: 10, X: 2}})
+
+	ec.AddMoves(bufferPool, []types.Player{{Id: 20, X: 5}, {Id: 21, X: 5}})
+
+	known := map[int32]bool{}
+	seen := map[int32]bool{}
+
+	for tick := 0; tick < 10 && !(seen[10] && seen[20] && seen[21]); tick++ {
+		eventList, _ := ec.GetPlayerEventList(1)
+		if tick == 0 && ec.Deferred(1) == 0 {
+			t.Fatalf("expected the spawn and the join to be deferred with a budget of %d bytes", server.MaxBytesPerTick)
+		}
+
+		update := func(id int32) {
+			if (id == 10 || id == 20) && !known[id] {
+				t.Fatalf("tick %d: %d was sent before its spawn or join", tick, id)
+			}
+
+			seen[id] = true
+		}
+
+		rawEvent, player, entity := &flatgen.RawEvent{}, &flatgen.Player{}, &flatgen.Entity{}
+
+		for i := range eventList.EventsLength() {
+			eventList.Events(rawEvent, i)
+			data := rawEvent.RawDataBytes()
+
+			switch flatgen.GetRootAsKindHolder(data, 0).Kind() {
+			case flatgen.EventKindEntitySpawned:
+				known[10] = true
+			case flatgen.EventKindPlayerJoined:
+				known[20] = true
+			case flatgen.EventKindEntityUpdated:
+				event := flatgen.GetRootAsEntityUpdated(data, 0)
+				for j := range event.EntitiesLength() {
+					event.Entities(entity, j)
+					update(entity.Id())
+				}
+			case flatgen.EventKindPlayerMovedList:
+				event := flatgen.GetRootAsPlayerMovedList(data, 0)
+				for j := range event.PlayersLength() {
+					event.Players(player, j)
+					update(player.Id())
+				}
+			}
+		}
+
+		ec.Reset()
+		bufferPool.Reset()
+	}
+
+	if !seen[10] || !seen[20] || !seen[21] {
+		t.Fatalf("expected the update and the moves to be sent after all, got %v", seen)
+	}
+}
@@ -2,9 +2,12 @@ package server
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
 	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
 	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
 
 	flatbuffers "github.com/google/flatbuffers/go"
 )
@@ -12,7 +15,11 @@ import (
 type EventListBuilder struct {
 	builder        *flatbuffers.Builder
 	events         []types.EventHolder
+	rawEvents      []flatbuffers.UOffsetT
 	builderStarted bool
+
+	tick       uint32
+	serverTime int64
 }
 
 func (elb *EventListBuilder) AddRawEvent(event types.EventHolder) error {
@@ -24,98 +31,523 @@ func (elb *EventListBuilder) AddRawEvent(event types.EventHolder) error {
 	return nil
 }
 
-func (elb *EventListBuilder) GetFlatEventList(generalEvents []types.EventHolder) (*flatgen.EventList, int) {
-	totalEventCount := len(elb.events) + len(generalEvents)
+func (elb *EventListBuilder) GetFlatEventList(generalEvents ...[]types.EventHolder) (*flatgen.EventList, int) {
+	totalEventCount := len(elb.events)
+	for i := range generalEvents {
+		totalEventCount += len(generalEvents[i])
+	}
+
 	if totalEventCount == 0 {
 		return nil, 0
 	}
 
 	elb.builderStarted = true
 
-	rawEventList := make([]flatbuffers.UOffsetT, totalEventCount)
+	rawEventList := make([]flatbuffers.UOffsetT, 0, totalEventCount)
 	for i := 0; i < len(elb.events); i++ {
-		rawDataOffset := elb.builder.CreateByteVector(elb.events[i].Bytes())
-
-		flatgen.RawEventStart(elb.builder)
-		flatgen.RawEventAddRawData(elb.builder, rawDataOffset)
-		rawEventList[i] = flatgen.RawEventEnd(elb.builder)
+		rawEventList = append(rawEventList, elb.addRawEvent(elb.events[i]))
 	}
 
-	for i := len(elb.events); i < totalEventCount; i++ {
-		j := i - len(elb.events)
-		rawDataOffset := elb.builder.CreateByteVector(generalEvents[j].Bytes())
-
-		flatgen.RawEventStart(elb.builder)
-		flatgen.RawEventAddRawData(elb.builder, rawDataOffset)
-		rawEventList[i] = flatgen.RawEventEnd(elb.builder)
+	for i := range generalEvents {
+		for j := range generalEvents[i] {
+			rawEventList = append(rawEventList, elb.addRawEvent(generalEvents[i][j]))
+		}
 	}
 
-	flatgen.EventListStartEventsVector(elb.builder, totalEventCount)
-	for i := range rawEventList {
-		elb.builder.PrependUOffsetT(rawEventList[i]) // This will set them the other way around but order is not a problem
+	return elb.finish(rawEventList), totalEventCount
+}
+
+// finish builds the EventList with the RawEvents and returns it.
+func (elb *EventListBuilder) finish(rawEvents []flatbuffers.UOffsetT) *flatgen.EventList {
+	// Vectors are built back to front, so the events are prepended in reverse to keep their order
+	flatgen.EventListStartEventsVector(elb.builder, len(rawEvents))
+	for i := len(rawEvents) - 1; i >= 0; i-- {
+		elb.builder.PrependUOffsetT(rawEvents[i])
 	}
 
-	eventList := elb.builder.EndVector(totalEventCount)
+	eventList := elb.builder.EndVector(len(rawEvents))
 
 	flatgen.EventListStart(elb.builder)
 	flatgen.EventListAddEvents(elb.builder, eventList)
+	flatgen.EventListAddTick(elb.builder, elb.tick)
+	flatgen.EventListAddServerTime(elb.builder, elb.serverTime)
 	elb.builder.Finish(flatgen.EventListEnd(elb.builder))
 
-	return flatgen.GetRootAsEventList(elb.builder.FinishedBytes(), 0), totalEventCount
+	return flatgen.GetRootAsEventList(elb.builder.FinishedBytes(), 0)
+}
+
+// buildFrame builds the EventList of the events, the ones in shared are referenced instead
+// of copied. shared can be nil.
+func (elb *EventListBuilder) buildFrame(events []collectedEvent, shared *broadcast) Frame {
+	var sharedBytes []byte
+	if shared != nil {
+		sharedBytes = shared.bytes()
+	}
+
+	elb.reserve(len(sharedBytes))
+	elb.rawEvents = elb.rawEvents[:0]
+
+	for _, collected := range events {
+		if shared != nil {
+			if offset, ok := shared.rawEvent(collected); ok {
+				elb.rawEvents = append(elb.rawEvents, offset)
+				continue
+			}
+		}
+
+		elb.rawEvents = append(elb.rawEvents, elb.addRawEvent(collected.event))
+	}
+
+	elb.finish(elb.rawEvents)
+
+	full := elb.builder.FinishedBytes()
+
+	return Frame{Head: full[:len(full)-len(sharedBytes)], Shared: sharedBytes, full: full}
+}
+
+// reserve resets the builder leaving n bytes free at the end of its buffer, for the broadcast
+// the EventList is sent with. The builder is reset on a buffer that stops before the free
+// bytes and then given the whole buffer back, so it counts them in its offsets without
+// writing them.
+func (elb *EventListBuilder) reserve(n int) {
+	if n == 0 {
+		elb.builder.Reset()
+		return
+	}
+
+	buf := elb.builder.Bytes
+	if len(buf) < n+256 {
+		buf = make([]byte, 2*(n+256))
+	}
+
+	front := len(buf) - n
+
+	elb.builder.Bytes = buf[:front:front]
+	elb.builder.Reset()
+	elb.builder.Bytes = buf
+}
+
+func (elb *EventListBuilder) addRawEvent(event types.EventHolder) flatbuffers.UOffsetT {
+	rawDataOffset := elb.builder.CreateByteVector(event.Bytes())
+
+	flatgen.RawEventStart(elb.builder)
+	flatgen.RawEventAddRawData(elb.builder, rawDataOffset)
+
+	return flatgen.RawEventEnd(elb.builder)
+}
+
+// Channel is how events of a kind are delivered to a player.
+type Channel int
+
+const (
+	// ChannelReliable events are all delivered, in the order they were added.
+	ChannelReliable Channel = iota
+	// ChannelLatestWins events are state updates, a newer one for the same entity replaces
+	// the one that wasn't sent yet.
+	ChannelLatestWins
+	// ChannelImmediate events are reliable and always sent in the tick they were added, the
+	// budget doesn't hold them back. They are small and timed, like pings, whose RTT would
+	// count the wait.
+	ChannelImmediate
+)
+
+// EventChannels is the channel of every event kind, kinds that are not listed are reliable.
+var EventChannels = map[flatgen.EventKind]Channel{
+	flatgen.EventKindPlayerMoved:     ChannelLatestWins,
+	flatgen.EventKindPlayerMovedList: ChannelLatestWins,
+	flatgen.EventKindIdleWarning:     ChannelLatestWins,
+	flatgen.EventKindPing:            ChannelImmediate,
+	// an entity's spawn and despawn always arrive, its updates only need the newest state
+	flatgen.EventKindEntitySpawned:   ChannelReliable,
+	flatgen.EventKindEntityUpdated:   ChannelLatestWins,
+	flatgen.EventKindEntityDespawned: ChannelReliable,
+}
+
+type collectedEvent struct {
+	// seq is the order events were added in, across every queue of the collector
+	seq   uint64
+	event types.EventHolder
+}
+
+type latestKey struct {
+	kind   flatgen.EventKind
+	entity int
+}
+
+type eventQueue struct {
+	reliable []collectedEvent
+	latest   map[latestKey]collectedEvent
 }
 
+func newEventQueue() *eventQueue {
+	return &eventQueue{latest: map[latestKey]collectedEvent{}}
+}
+
+func (eq *eventQueue) add(seq uint64, entity int, event types.EventHolder) {
+	collected := collectedEvent{seq: seq, event: event}
+
+	if EventChannels[event.Kind()] == ChannelLatestWins {
+		eq.latest[latestKey{kind: event.Kind(), entity: entity}] = collected
+	} else {
+		eq.reliable = append(eq.reliable, collected)
+	}
+}
+
+func (eq *eventQueue) reset() {
+	clear(eq.reliable)
+	eq.reliable = eq.reliable[:0]
+
+	clear(eq.latest)
+}
+
+type playerEvents struct {
+	builder EventListBuilder
+	queue   *eventQueue
+
+	// pending is what didn't fit in the budget of this tick, it becomes the queue on Reset.
+	// Moves are kept apart, they are sent in one PlayerMovedList built for the player.
+	pending       *eventQueue
+	pendingMoves  []slottedMove
+	deferredMoves []slottedMove
+	// the entity updates are kept apart too, by entity, with the priority they piled up
+	pendingUpdates  map[int]scoredUpdate
+	deferredUpdates map[int]scoredUpdate
+
+	// accumulated is the priority latest-wins events piled up while they were deferred,
+	// moveScores is the same for the moves, by the slot of the player that moved. They grow
+	// once per tick, on Reset, see endTick.
+	accumulated map[latestKey]float64
+	moveScores  []float64
+
+	x, y         float64
+	hasViewpoint bool
+
+	// datagramSize is the size of the datagrams the moves go in, 0 when they go in the EventList
+	datagramSize int
+}
+
+// EventCollector gathers the events of a tick for every player. Events are delivered in the
+// order they were added, whether they were added for one player, for everyone or for a codec.
+// Latest-wins events are coalesced, only the newest one per entity is delivered. What doesn't
+// fit in the MaxBytesPerTick budget of a player is deferred to the next ticks.
 type EventCollector struct {
-	playerEvents  map[int]EventListBuilder
-	generalEvents []types.EventHolder
+	playerEvents  map[int]*playerEvents
+	generalEvents *eventQueue
+
+	// encodedEvents are general events that contain positions, there is one version
+	// of them for every position codec used by the players.
+	encodedEvents map[types.PositionCodec]*eventQueue
+	playerCodecs  map[int]types.PositionCodec
+	codecUsers    map[types.PositionCodec]int
+
+	// moves are the players that moved this tick
+	moves moveSet
+	// updates are the entities that changed this tick
+	updates updateSet
+
+	budget     eventBudget
+	broadcasts broadcasts
+	datagrams  datagramState
+
+	seq        uint64
+	tick       uint32
+	serverTime int64
 }
 
 func NewEventCollector() *EventCollector {
 	return &EventCollector{
-		playerEvents: map[int]EventListBuilder{},
+		playerEvents:  map[int]*playerEvents{},
+		generalEvents: newEventQueue(),
+		encodedEvents: map[types.PositionCodec]*eventQueue{},
+		playerCodecs:  map[int]types.PositionCodec{},
+		codecUsers:    map[types.PositionCodec]int{},
+		moves:         newMoveSet(),
+		updates:       newUpdateSet(),
+		budget:        newEventBudget(),
+		broadcasts:    broadcasts{},
+		datagrams:     newDatagramState(),
 	}
 }
 
-func (es *EventCollector) AddEvent(playerId int, event types.EventHolder) {
-	if event.Kind() == flatgen.EventKindNilEvent {
+// SetTick sets the tick and server time (in microseconds) stamped on the event lists.
+func (es *EventCollector) SetTick(tick uint32, serverTime int64) {
+	es.tick = tick
+	es.serverTime = serverTime
+}
+
+func (es *EventCollector) SetPositionCodec(playerId int, codec types.PositionCodec) {
+	if _, ok := es.playerEvents[playerId]; !ok {
 		return
 	}
 
-	playerEventsBuilder, ok := es.playerEvents[playerId]
+	es.forgetPositionCodec(playerId)
+
+	es.playerCodecs[playerId] = codec
+	es.codecUsers[codec]++
+}
+
+// PositionCodecs returns every codec used by at least one player.
+func (es *EventCollector) PositionCodecs() []types.PositionCodec {
+	codecs := make([]types.PositionCodec, 0, len(es.codecUsers))
+
+	for codec := range es.codecUsers {
+		codecs = append(codecs, codec)
+	}
+
+	return codecs
+}
+
+func (es *EventCollector) forgetPositionCodec(playerId int) {
+	codec, ok := es.playerCodecs[playerId]
 	if !ok {
-		playerEventsBuilder = EventListBuilder{
-			builder: flatbuffers.NewBuilder(256),
-		}
+		return
 	}
 
-	playerEventsBuilder.AddRawEvent(event)
-	es.playerEvents[playerId] = playerEventsBuilder
+	delete(es.playerCodecs, playerId)
+
+	es.codecUsers[codec]--
+	if es.codecUsers[codec] <= 0 {
+		delete(es.codecUsers, codec)
+	}
+}
+
+func (es *EventCollector) nextSeq() uint64 {
+	es.seq++
+
+	return es.seq
+}
+
+// AddPlayer makes the collector keep the events of the player, until RemovePlayer. Events and
+// settings of players it doesn't know are dropped, so a late event can't bring back a player
+// that left.
+func (es *EventCollector) AddPlayer(playerId int) {
+	if _, ok := es.playerEvents[playerId]; ok {
+		return
+	}
+
+	es.playerEvents[playerId] = &playerEvents{
+		builder:         EventListBuilder{builder: flatbuffers.NewBuilder(256)},
+		queue:           newEventQueue(),
+		pending:         newEventQueue(),
+		pendingUpdates:  map[int]scoredUpdate{},
+		deferredUpdates: map[int]scoredUpdate{},
+		accumulated:     map[latestKey]float64{},
+	}
+}
+
+// SetViewpoint sets where the player is, moves closer to it are sent first.
+func (es *EventCollector) SetViewpoint(playerId int, x, y float64) {
+	events, ok := es.playerEvents[playerId]
+	if !ok {
+		return
+	}
+
+	events.x, events.y = x, y
+	events.hasViewpoint = true
+}
+
+func (es *EventCollector) AddEvent(playerId int, event types.EventHolder) {
+	es.AddEntityEvent(playerId, 0, event)
+}
+
+// AddEntityEvent adds an event about the given entity, latest-wins events of different
+// entities don't replace each other.
+func (es *EventCollector) AddEntityEvent(playerId int, entity int, event types.EventHolder) {
+	events, ok := es.playerEvents[playerId]
+	if !ok {
+		return
+	}
+
+	events.queue.add(es.nextSeq(), entity, event)
 }
 
 func (es *EventCollector) AddGeneralEvent(event types.EventHolder) {
-	es.generalEvents = append(es.generalEvents, event)
+	es.generalEvents.add(es.nextSeq(), 0, event)
+	es.broadcasts.invalidate()
+}
+
+// AddEncodedEvent adds a general event that is only sent to the players using the given codec.
+func (es *EventCollector) AddEncodedEvent(codec types.PositionCodec, event types.EventHolder) {
+	es.AddEncodedEntityEvent(codec, 0, event)
+}
+
+// AddEncodedEntityEvent is AddEntityEvent for every player using the given codec, the event
+// is about the given entity.
+func (es *EventCollector) AddEncodedEntityEvent(codec types.PositionCodec, entity int, event types.EventHolder) {
+	queue, ok := es.encodedEvents[codec]
+	if !ok {
+		queue = newEventQueue()
+		es.encodedEvents[codec] = queue
+	}
+
+	queue.add(es.nextSeq(), entity, event)
+	es.broadcasts.invalidate()
 }
 
+// AddEntityUpdates adds the entities that changed this tick. Every codec gets one EntityUpdated
+// shared by its players, a player whose budget can't fit all of it gets the most important
+// updates. Updates are latest-wins per entity, a deferred one is replaced by a newer one.
+func (es *EventCollector) AddEntityUpdates(bufferPool *BuilderPool, states []ecs.State) {
+	if len(states) == 0 {
+		return
+	}
+
+	us := &es.updates
+	us.states = append(us.states[:0], states...)
+	us.seq = es.nextSeq()
+
+	for _, state := range states {
+		us.ids[state.Id] = struct{}{}
+	}
+
+	for codec := range es.codecUsers {
+		entityUpdated := utils.NewFlatEntityUpdated(bufferPool.GetFreeBuilder(), states, codec)
+		us.lists[codec] = utils.NewEventHolder(flatgen.EventKindEntityUpdated, entityUpdated)
+	}
+
+	es.broadcasts.invalidate()
+}
+
+// ForgetEntity drops the deferred update of the entity, for an entity that's gone and whose
+// last state would bring it back.
+func (es *EventCollector) ForgetEntity(entity int) {
+	for _, events := range es.playerEvents {
+		delete(events.deferredUpdates, entity)
+		delete(events.pendingUpdates, entity)
+	}
+}
+
+// AddMoves adds the players that moved this tick. Every codec gets one PlayerMovedList shared
+// by its players, a player whose budget can't fit all of it gets the most important moves.
+func (es *EventCollector) AddMoves(bufferPool *BuilderPool, players []types.Player) {
+	if len(players) == 0 {
+		return
+	}
+
+	es.setMoves(&es.moves, bufferPool, players)
+	es.broadcasts.invalidate()
+}
+
+// setMoves fills the move set with the players and their PlayerMovedList for every codec.
+func (es *EventCollector) setMoves(ms *moveSet, bufferPool *BuilderPool, players []types.Player) {
+	ms.moves = ms.moves[:0]
+	ms.seq = es.nextSeq()
+
+	for _, player := range players {
+		ms.moves = append(ms.moves, slottedMove{slot: es.budget.slot(player.Id), player: player})
+		ms.ids[player.Id] = struct{}{}
+	}
+
+	for codec := range es.codecUsers {
+		flatPlayerMovedList := utils.NewFlatPlayerMovedList(bufferPool.GetFreeBuilder(), players, codec)
+		ms.lists[codec] = utils.NewEventHolder(flatgen.EventKindPlayerMovedList, flatPlayerMovedList)
+	}
+}
+
+// GetPlayerEventList builds the events of the tick for the player, the list is valid until
+// Reset. The priority of the deferred events only grows on Reset, so building the list again
+// in the same tick gives the same one.
 func (es *EventCollector) GetPlayerEventList(playerId int) (*flatgen.EventList, int) {
-	playerEventsBuilder, ok := es.playerEvents[playerId]
+	frame, count := es.GetPlayerFrame(playerId)
+	if count == 0 {
+		return nil, 0
+	}
+
+	return flatgen.GetRootAsEventList(frame.Bytes(), 0), count
+}
+
+// GetPlayerFrame is GetPlayerEventList without joining the EventList with the broadcast it
+// references, so the broadcast can be written to every player without copying it.
+func (es *EventCollector) GetPlayerFrame(playerId int) (Frame, int) {
+	events, ok := es.playerEvents[playerId]
 	if !ok {
-		playerEventsBuilder = EventListBuilder{builder: flatbuffers.NewBuilder(512)} // TODO: Is this ok?
+		return Frame{}, 0
 	}
 
-	return playerEventsBuilder.GetFlatEventList(es.generalEvents)
+	codec, ok := es.playerCodecs[playerId]
+	if !ok {
+		codec = types.IntegerCodec
+	}
+
+	moves := &es.moves
+
+	// the moves of the player go in datagrams, see GetPlayerDatagrams, the EventList only has
+	// the players that stopped moving
+	if events.datagramSize > 0 {
+		moves = &es.datagrams.settled
+	}
+
+	ordered := es.budget.selectEvents(events, []*eventQueue{events.queue, es.generalEvents, es.encodedEvents[codec]}, codec, moves, &es.updates)
+	if len(ordered) == 0 {
+		return Frame{}, 0
+	}
+
+	var shared *broadcast
+	if SharedBroadcast {
+		shared = es.broadcasts.get(codec, []*eventQueue{es.generalEvents, es.encodedEvents[codec]}, &es.moves, &es.updates)
+
+		// a player that doesn't get all of it this tick gets its events copied
+		if !shared.coveredBy(ordered) {
+			shared = nil
+		}
+	}
+
+	events.builder.tick = es.tick
+	events.builder.serverTime = es.serverTime
+
+	return events.builder.buildFrame(ordered, shared), len(ordered)
+}
+
+// Deferred is how many events of the player didn't fit in its budget this tick.
+func (es *EventCollector) Deferred(playerId int) int {
+	events, ok := es.playerEvents[playerId]
+	if !ok {
+		return 0
+	}
+
+	return len(events.pending.reliable) + len(events.pending.latest) + len(events.pendingMoves) + len(events.pendingUpdates)
 }
 
+// Reset ends the tick, the events that were deferred are queued for the next one.
 func (es *EventCollector) Reset() {
-	for id, eventListBuilder := range es.playerEvents {
-		eventListBuilder.builder.Reset()
-		eventListBuilder.events = eventListBuilder.events[:0]
+	for _, events := range es.playerEvents {
+		events.endTick()
+	}
 
-		es.playerEvents[id] = eventListBuilder
+	es.generalEvents.reset()
+
+	for _, queue := range es.encodedEvents {
+		queue.reset()
 	}
 
-	clear(es.generalEvents)
-	es.generalEvents = es.generalEvents[:0]
+	es.broadcasts.reset()
+	es.moves.reset()
+	es.updates.reset()
+	es.datagrams.reset()
 }
 
+// RemovePlayer forgets the player, including the moves of it that other players were owed.
 func (es *EventCollector) RemovePlayer(playerId int) {
+	if events, ok := es.playerEvents[playerId]; ok && events.datagramSize > 0 {
+		es.datagrams.users--
+	}
+
 	delete(es.playerEvents, playerId)
+	es.forgetPositionCodec(playerId)
+
+	slot, ok := es.budget.release(playerId)
+	if !ok {
+		return
+	}
+
+	isRemoved := func(move slottedMove) bool { return move.player.Id == playerId }
+
+	for _, events := range es.playerEvents {
+		events.deferredMoves = slices.DeleteFunc(events.deferredMoves, isRemoved)
+		events.pendingMoves = slices.DeleteFunc(events.pendingMoves, isRemoved)
+
+		if int(slot) < len(events.moveScores) {
+			events.moveScores[slot] = 0
+		}
+	}
 }
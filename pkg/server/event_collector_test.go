@@ -1,7 +1,7 @@
-package server
+package server_test
 
 import (
-	"fmt"
+	"bytes"
 	"testing"
 
 	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
@@ -15,11 +15,12 @@ import (
 
 func TestEventCollector(t *testing.T) {
 	ec := server.NewEventCollector()
+	ec.AddPlayer(2)
 
 	{
 		builder2 := flatbuffers.NewBuilder(256)
 
-		playerJoined := utils.NewFlatPlayerJoined(builder2, types.Player{Id: 2, X: 20, Y: 69})
+		playerJoined := utils.NewFlatPlayerJoined(builder2, types.Player{Id: 2, X: 20, Y: 69}, types.IntegerCodec)
 		playerJoinedEvent := utils.NewEventHolder(flatgen.EventKindPlayerJoined, playerJoined)
 
 		ec.AddEvent(2, playerJoinedEvent)
@@ -27,7 +28,7 @@ func TestEventCollector(t *testing.T) {
 	{
 		builder2 := flatbuffers.NewBuilder(256)
 
-		playerJoined := utils.NewFlatPlayerJoined(builder2, types.Player{Id: 2, X: 699, Y: 420})
+		playerJoined := utils.NewFlatPlayerJoined(builder2, types.Player{Id: 2, X: 699, Y: 420}, types.IntegerCodec)
 		playerJoinedEvent := utils.NewEventHolder(flatgen.EventKindPlayerJoined, playerJoined)
 
 		ec.AddEvent(2, playerJoinedEvent)
@@ -35,120 +36,132 @@ func TestEventCollector(t *testing.T) {
 
 	playerEvents, _ := ec.GetPlayerEventList(2)
 
-	{
-		rawEvent := &flatgen.RawEvent{}
-
-		fmt.Printf("playerEvents.EventsLength(): %v\n", playerEvents.EventsLength())
-
-		playerEvents.Events(rawEvent, 0)
-
-		kindHolder := flatgen.GetRootAsKindHolder(rawEvent.RawDataBytes(), 0)
-
-		fmt.Println(flatgen.EnumNamesEventKind[kindHolder.Kind()])
-
-		playerJoined2 := flatgen.GetRootAsPlayerJoined(rawEvent.RawDataBytes(), 0)
-		fmt.Println("Player id ", playerJoined2.Player(nil).Id())
-		fmt.Println("Player X ", playerJoined2.Player(nil).X())
-		fmt.Println("Player Y ", playerJoined2.Player(nil).Y())
+	if playerEvents.EventsLength() != 2 {
+		t.Fatalf("expected 2 events, got %v", playerEvents.EventsLength())
 	}
-	{
-		rawEvent := &flatgen.RawEvent{}
 
-		fmt.Printf("playerEvents.EventsLength(): %v\n", playerEvents.EventsLength())
+	expected := [][2]int32{{20, 69}, {699, 420}}
 
-		playerEvents.Events(rawEvent, 1)
+	for i, position := range expected {
+		rawEvent := &flatgen.RawEvent{}
+		playerEvents.Events(rawEvent, i)
 
 		kindHolder := flatgen.GetRootAsKindHolder(rawEvent.RawDataBytes(), 0)
-
-		fmt.Println(flatgen.EnumNamesEventKind[kindHolder.Kind()])
-
-		playerJoined2 := flatgen.GetRootAsPlayerJoined(rawEvent.RawDataBytes(), 0)
-		fmt.Println("Player id ", playerJoined2.Player(nil).Id())
-		fmt.Println("Player X ", playerJoined2.Player(nil).X())
-		fmt.Println("Player Y ", playerJoined2.Player(nil).Y())
+		if kindHolder.Kind() != flatgen.EventKindPlayerJoined {
+			t.Fatalf("event %v: expected PlayerJoined, got %v", i, kindHolder.Kind())
+		}
+
+		player := flatgen.GetRootAsPlayerJoined(rawEvent.RawDataBytes(), 0).Player(nil)
+		if player.Id() != 2 || player.X() != position[0] || player.Y() != position[1] {
+			t.Errorf("event %v: expected player 2 at %v, got %v at (%v, %v)", i, position, player.Id(), player.X(), player.Y())
+		}
 	}
 }
 
 func TestEventCollectorGeneral(t *testing.T) {
 	ec := server.NewEventCollector()
+	ec.AddPlayer(2)
 
-	playerMovedList := []*flatgen.PlayerMoved{
-		utils.NewFlatPlayerMoved(flatbuffers.NewBuilder(256), types.Player{Id: 69, X: 10, Y: 200, Speed: 420}),
-		utils.NewFlatPlayerMoved(flatbuffers.NewBuilder(256), types.Player{Id: 989, X: 999, Y: 992, Speed: 909}),
+	playerMovedList := []types.Player{
+		{Id: 69, X: 10, Y: 200, Speed: 420},
+		{Id: 989, X: 999, Y: 992, Speed: 909},
 	}
-	flatPlayerMovedList := utils.NewFlatPlayerMovedList(flatbuffers.NewBuilder(512), playerMovedList)
+	flatPlayerMovedList := utils.NewFlatPlayerMovedList(flatbuffers.NewBuilder(512), playerMovedList, types.IntegerCodec)
 
 	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerMoved, flatPlayerMovedList))
 	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerMoved, flatPlayerMovedList))
 
+	// moves are latest-wins, the second one replaces the first
 	playerEvents, _ := ec.GetPlayerEventList(2)
-	fmt.Printf("list.EventsLength(): %v\n", playerEvents.EventsLength())
+	if playerEvents.EventsLength() != 1 {
+		t.Fatalf("expected 1 event, got %v", playerEvents.EventsLength())
+	}
 
 	rawEvent := &flatgen.RawEvent{}
 	playerEvents.Events(rawEvent, 0)
 
 	kindHolder := flatgen.GetRootAsKindHolder(rawEvent.RawDataBytes(), 0)
+	if kindHolder.Kind() != flatgen.EventKindPlayerMovedList {
+		t.Fatalf("expected PlayerMovedList, got %v", kindHolder.Kind())
+	}
 
-	fmt.Println(flatgen.EnumNamesEventKind[kindHolder.Kind()])
-
-	playerMoved := flatgen.GetRootAsPlayerMovedList(rawEvent.RawDataBytes(), 0)
+	playerList := flatgen.GetRootAsPlayerMovedList(rawEvent.RawDataBytes(), 0)
+	if playerList.PlayersLength() != len(playerMovedList) {
+		t.Fatalf("expected %v players, got %v", len(playerMovedList), playerList.PlayersLength())
+	}
 
 	player := &flatgen.Player{}
-	playerMoved.Players(player, 0)
+	received := map[int32]flatgen.Player{}
 
-	fmt.Println("Player id ", player.Id())
-	fmt.Println("Player X ", player.X())
-	fmt.Println("Player Y ", player.Y())
-	fmt.Println("Player Speed ", player.Speed())
+	for i := range playerList.PlayersLength() {
+		playerList.Players(player, i)
+		received[player.Id()] = *player
+	}
 
-	playerMoved.Players(player, 1)
+	for _, expected := range playerMovedList {
+		got, ok := received[int32(expected.Id)]
+		if !ok {
+			t.Fatalf("player %v is missing", expected.Id)
+		}
 
-	fmt.Println("Player id ", player.Id())
-	fmt.Println("Player X ", player.X())
-	fmt.Println("Player Y ", player.Y())
-	fmt.Println("Player Speed ", player.Speed())
+		if got.X() != int32(expected.X) || got.Y() != int32(expected.Y) || got.Speed() != int32(expected.Speed) {
+			t.Errorf("player %v: expected %+v, got (%v, %v) speed %v", expected.Id, expected, got.X(), got.Y(), got.Speed())
+		}
+	}
 }
 
 func TestEventCollectorGeneralJoin(t *testing.T) {
 	ec := server.NewEventCollector()
+	ec.AddPlayer(2)
 
 	playerJoinedList := []types.Player{
 		{Id: 69, X: 10, Y: 200, Speed: 420},
 		{Id: 999, X: 999, Y: 999, Speed: 999},
 	}
-	flatPlayerJoinedList := utils.NewFlatPlayerJoinedList(flatbuffers.NewBuilder(512), playerJoinedList)
+	flatPlayerJoinedList := utils.NewFlatPlayerJoinedList(flatbuffers.NewBuilder(512), playerJoinedList, types.IntegerCodec)
 
 	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerJoinedList, flatPlayerJoinedList))
 	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerJoinedList, flatPlayerJoinedList))
 
 	ec.GetPlayerEventList(2)
 
+	// joins are reliable, both are sent and the list can be built again until the collector is reset
 	playerEvents, _ := ec.GetPlayerEventList(2)
-	fmt.Printf("list.EventsLength(): %v\n", playerEvents.EventsLength())
+	if playerEvents.EventsLength() != 2 {
+		t.Fatalf("expected 2 events, got %v", playerEvents.EventsLength())
+	}
 
 	rawEvent := &flatgen.RawEvent{}
 	playerEvents.Events(rawEvent, 0)
 
 	kindHolder := flatgen.GetRootAsKindHolder(rawEvent.RawDataBytes(), 0)
+	if kindHolder.Kind() != flatgen.EventKindPlayerJoinedList {
+		t.Fatalf("expected PlayerJoinedList, got %v", kindHolder.Kind())
+	}
 
-	fmt.Println(flatgen.EnumNamesEventKind[kindHolder.Kind()])
-
-	playerMoved := flatgen.GetRootAsPlayerJoinedList(rawEvent.RawDataBytes(), 0)
+	playerList := flatgen.GetRootAsPlayerJoinedList(rawEvent.RawDataBytes(), 0)
+	if playerList.PlayersLength() != len(playerJoinedList) {
+		t.Fatalf("expected %v players, got %v", len(playerJoinedList), playerList.PlayersLength())
+	}
 
 	player := &flatgen.Player{}
-	playerMoved.Players(player, 0)
+	received := map[int32]flatgen.Player{}
 
-	fmt.Println("Player id ", player.Id())
-	fmt.Println("Player X ", player.X())
-	fmt.Println("Player Y ", player.Y())
-	fmt.Println("Player Speed ", player.Speed())
+	for i := range playerList.PlayersLength() {
+		playerList.Players(player, i)
+		received[player.Id()] = *player
+	}
 
-	playerMoved.Players(player, 1)
+	for _, expected := range playerJoinedList {
+		got, ok := received[int32(expected.Id)]
+		if !ok {
+			t.Fatalf("player %v is missing", expected.Id)
+		}
 
-	fmt.Println("Player id ", player.Id())
-	fmt.Println("Player X ", player.X())
-	fmt.Println("Player Y ", player.Y())
-	fmt.Println("Player Speed ", player.Speed())
+		if got.X() != int32(expected.X) || got.Y() != int32(expected.Y) || got.Speed() != int32(expected.Speed) {
+			t.Errorf("player %v: expected %+v, got (%v, %v) speed %v", expected.Id, expected, got.X(), got.Y(), got.Speed())
+		}
+	}
 }
 
 func TestBunica(t *testing.T) {
@@ -159,10 +172,7 @@ func TestBunica(t *testing.T) {
 	// flatgen.BunicaEventAddId(builder, 1)
 	builder.Finish(flatgen.BunicaEventEnd(builder))
 	bunica := builder.FinishedBytes()
-	fmt.Println("Bunica", len(bunica))
 
-	fmt.Printf("bunicaEvent: %v\n", len(bunica))
-	//
 	builder3 := flatbuffers.NewBuilder(1024)
 
 	rawData := builder3.CreateByteVector(bunica)
@@ -188,7 +198,17 @@ func TestBunica(t *testing.T) {
 	builder3.Finish(flatgen.EventListEnd(builder3))
 
 	FINAL := builder3.FinishedBytes()
-	fmt.Printf("len(FINAL): %v\n", len(FINAL))
+
+	ev := &flatgen.RawEvent{}
+	flatgen.GetRootAsEventList(FINAL, 0).Events(ev, 0)
+
+	if !bytes.Equal(ev.RawDataBytes(), bunica) {
+		t.Fatal("the event isn't the same after going through the list")
+	}
+
+	if kind := flatgen.GetRootAsKindHolder(ev.RawDataBytes(), 0).Kind(); kind != flatgen.EventKindPlayerHello {
+		t.Fatalf("expected PlayerHello, got %v", kind)
+	}
 }
 
 func TestBunica2(t *testing.T) {
@@ -199,7 +219,6 @@ func TestBunica2(t *testing.T) {
 	// flatgen.BunicaEventAddId(builder, 1)
 	builder.Finish(flatgen.BunicaEventEnd(builder))
 	bunica := builder.FinishedBytes()
-	fmt.Println("Bunica", len(bunica))
 
 	builder2 := flatbuffers.NewBuilder(1024)
 
@@ -220,10 +239,206 @@ func TestBunica2(t *testing.T) {
 	builder2.Finish(flatgen.EventListEnd(builder2))
 
 	FINAL := builder2.FinishedBytes()
-	fmt.Printf("len(FINAL): %v\n", len(FINAL))
 
 	ev := &flatgen.RawEvent{}
 	flatgen.GetRootAsEventList(FINAL, 0).Events(ev, 0)
 
-	fmt.Printf("flatgen.GetRootAsKindHolder(ev.RawDataBytes(), 0).Kind().String(): %v\n", flatgen.GetRootAsKindHolder(ev.RawDataBytes(), 0).Kind().String())
+	if kind := flatgen.GetRootAsKindHolder(ev.RawDataBytes(), 0).Kind(); kind != flatgen.EventKindPlayerMoved {
+		t.Fatalf("expected PlayerMoved, got %v", kind)
+	}
+}
+
+func TestEventCollectorEncodedEvents(t *testing.T) {
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.AddPlayer(2)
+
+	fixedPoint := types.PositionCodec{Encoding: flatgen.PositionEncodingFixedPoint, Scale: 100}
+
+	ec.SetPositionCodec(1, types.IntegerCodec)
+	ec.SetPositionCodec(2, fixedPoint)
+
+	if len(ec.PositionCodecs()) != 2 {
+		t.Fatalf("expected 2 codecs in use, got %v", ec.PositionCodecs())
+	}
+
+	movedPlayers := []types.Player{{Id: 1, X: 10.25, Y: 20.5}}
+
+	for _, codec := range ec.PositionCodecs() {
+		flatPlayerMovedList := utils.NewFlatPlayerMovedList(flatbuffers.NewBuilder(256), movedPlayers, codec)
+		ec.AddEncodedEvent(codec, utils.NewEventHolder(flatgen.EventKindPlayerMovedList, flatPlayerMovedList))
+	}
+
+	expectedX := map[int]int32{1: 10, 2: 1025}
+
+	for playerId, x := range expectedX {
+		eventList, count := ec.GetPlayerEventList(playerId)
+		if count != 1 {
+			t.Fatalf("player %d: expected 1 event, got %d", playerId, count)
+		}
+
+		rawEvent := &flatgen.RawEvent{}
+		eventList.Events(rawEvent, 0)
+
+		player := &flatgen.Player{}
+		flatgen.GetRootAsPlayerMovedList(rawEvent.RawDataBytes(), 0).Players(player, 0)
+
+		if player.X() != x {
+			t.Errorf("player %d: expected x %d, got %d", playerId, x, player.X())
+		}
+	}
+
+	ec.RemovePlayer(2)
+
+	if len(ec.PositionCodecs()) != 1 {
+		t.Errorf("expected the fixed point codec to be forgotten, got %v", ec.PositionCodecs())
+	}
+}
+
+// eventKinds returns the kinds of the events in the list, in the order they are in it.
+func eventKinds(eventList *flatgen.EventList) []flatgen.EventKind {
+	kinds := []flatgen.EventKind{}
+	rawEvent := &flatgen.RawEvent{}
+
+	for i := range eventList.EventsLength() {
+		eventList.Events(rawEvent, i)
+		kinds = append(kinds, flatgen.GetRootAsKindHolder(rawEvent.RawDataBytes(), 0).Kind())
+	}
+
+	return kinds
+}
+
+func TestEventCollectorReliableOrder(t *testing.T) {
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+
+	joined := utils.NewFlatPlayerJoinedList(flatbuffers.NewBuilder(256), []types.Player{{Id: 1}}, types.IntegerCodec)
+
+	// Personal, general and encoded events are interleaved, they have to come out in the order they went in
+	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), 3)))
+	ec.AddEvent(1, utils.NewEventHolder(flatgen.EventKindPlayerHelloConfirm, utils.NewFlatPlayerHelloConfirm(flatbuffers.NewBuilder(64), 1, flatgen.PositionEncodingInteger, flatgen.CompressionNone, 0, "")))
+	ec.AddEncodedEvent(types.IntegerCodec, utils.NewEventHolder(flatgen.EventKindPlayerJoinedList, joined))
+	ec.AddEvent(1, utils.NewEventHolder(flatgen.EventKindTimeSyncReply, utils.NewFlatTimeSyncReply(flatbuffers.NewBuilder(64), 1, 2, 3)))
+	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), 4)))
+
+	expected := []flatgen.EventKind{
+		flatgen.EventKindPlayerQuit,
+		flatgen.EventKindPlayerHelloConfirm,
+		flatgen.EventKindPlayerJoinedList,
+		flatgen.EventKindTimeSyncReply,
+		flatgen.EventKindPlayerQuit,
+	}
+
+	eventList, count := ec.GetPlayerEventList(1)
+	if count != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), count)
+	}
+
+	kinds := eventKinds(eventList)
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Fatalf("expected the events in order %v, got %v", expected, kinds)
+		}
+	}
+
+	rawEvent := &flatgen.RawEvent{}
+	eventList.Events(rawEvent, 4)
+
+	if id := flatgen.GetRootAsPlayerQuit(rawEvent.RawDataBytes(), 0).Id(); id != 4 {
+		t.Errorf("expected the last quit to be of player 4, got %d", id)
+	}
+}
+
+func TestEventCollectorLatestWins(t *testing.T) {
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.AddPlayer(2)
+
+	moved := func(x float64) types.EventHolder {
+		flatMovedList := utils.NewFlatPlayerMovedList(flatbuffers.NewBuilder(256), []types.Player{{Id: 1, X: x}}, types.IntegerCodec)
+
+		return utils.NewEventHolder(flatgen.EventKindPlayerMovedList, flatMovedList)
+	}
+
+	warning := func(secondsLeft float32) types.EventHolder {
+		idleWarning := utils.NewFlatIdleWarning(flatbuffers.NewBuilder(64), flatgen.IdleActionSpectate, secondsLeft)
+
+		return utils.NewEventHolder(flatgen.EventKindIdleWarning, idleWarning)
+	}
+
+	ec.AddEncodedEvent(types.IntegerCodec, moved(10))
+	ec.AddEvent(1, warning(1))
+	ec.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), 3)))
+	ec.AddEncodedEvent(types.IntegerCodec, moved(20))
+	ec.AddEvent(1, warning(2))
+
+	// Warnings about different entities don't replace each other
+	ec.AddEntityEvent(2, 1, warning(3))
+	ec.AddEntityEvent(2, 2, warning(4))
+
+	eventList, count := ec.GetPlayerEventList(1)
+	if count != 3 {
+		t.Fatalf("expected the quit, the newest move and the newest warning, got %v", eventKinds(eventList))
+	}
+
+	// The coalesced events take the place of the newest one
+	expected := []flatgen.EventKind{flatgen.EventKindPlayerQuit, flatgen.EventKindPlayerMovedList, flatgen.EventKindIdleWarning}
+
+	kinds := eventKinds(eventList)
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Fatalf("expected the events in order %v, got %v", expected, kinds)
+		}
+	}
+
+	rawEvent := &flatgen.RawEvent{}
+	player := &flatgen.Player{}
+
+	eventList.Events(rawEvent, 1)
+	flatgen.GetRootAsPlayerMovedList(rawEvent.RawDataBytes(), 0).Players(player, 0)
+
+	if player.X() != 20 {
+		t.Errorf("expected the newest position 20, got %d", player.X())
+	}
+
+	eventList.Events(rawEvent, 2)
+
+	if secondsLeft := flatgen.GetRootAsIdleWarning(rawEvent.RawDataBytes(), 0).SecondsLeft(); secondsLeft != 2 {
+		t.Errorf("expected the newest warning 2, got %v", secondsLeft)
+	}
+
+	if _, count := ec.GetPlayerEventList(2); count != 4 {
+		t.Errorf("expected 2 warnings and the general events for player 2, got %d", count)
+	}
+
+	ec.Reset()
+
+	if _, count := ec.GetPlayerEventList(1); count != 0 {
+		t.Errorf("expected no events after a reset, got %d", count)
+	}
+}
+
+func TestEventCollectorDropsEventsOfUnknownPlayers(t *testing.T) {
+	ec := server.NewEventCollector()
+	ec.AddPlayer(1)
+	ec.RemovePlayer(1)
+
+	// What's sent to a player that left, late in the tick, shouldn't bring it back
+	ec.AddEvent(1, utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), 3)))
+	ec.SetPositionCodec(1, types.PositionCodec{Encoding: flatgen.PositionEncodingFixedPoint, Scale: 100})
+	ec.SetViewpoint(1, 10, 10)
+
+	if codecs := ec.PositionCodecs(); len(codecs) != 0 {
+		t.Errorf("expected no codecs in use, got %v", codecs)
+	}
+
+	if _, count := ec.GetPlayerEventList(1); count != 0 {
+		t.Errorf("expected no events for a player that left, got %d", count)
+	}
+
+	ec.AddPlayer(1)
+
+	if _, count := ec.GetPlayerEventList(1); count != 0 {
+		t.Errorf("the events sent while the player was unknown should be dropped, got %d", count)
+	}
 }
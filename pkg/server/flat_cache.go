@@ -4,25 +4,36 @@ import (
 	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
 	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
 	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
 
 	flatbuffers "github.com/google/flatbuffers/go"
 )
 
+// joinKey is needed because the same player is encoded differently for clients with
+// different position codecs, and one cached buffer can't hold both at once.
+type joinKey struct {
+	id    int
+	codec types.PositionCodec
+}
+
 type FlatCache struct {
-	playerJoined map[int]*flatgen.PlayerJoined
+	playerJoined map[joinKey]*flatgen.PlayerJoined
+	worldMap     *flatgen.WorldMap
 }
 
 func NewFlatCache() *FlatCache {
-	return &FlatCache{playerJoined: map[int]*flatgen.PlayerJoined{}}
+	return &FlatCache{playerJoined: map[joinKey]*flatgen.PlayerJoined{}}
 }
 
-func (fc *FlatCache) GetMutatedPlayerJoined(id int, player types.Player) *flatgen.PlayerJoined {
-	playerJoined, ok := fc.playerJoined[id]
+func (fc *FlatCache) GetMutatedPlayerJoined(id int, player types.Player, codec types.PositionCodec) *flatgen.PlayerJoined {
+	key := joinKey{id: id, codec: codec}
+
+	playerJoined, ok := fc.playerJoined[key]
 	if !ok {
 		builder := flatbuffers.NewBuilder(512)
 
-		playerJoined = utils.NewFlatPlayerJoined(builder, player)
-		fc.AddJoin(id, playerJoined)
+		playerJoined = utils.NewFlatPlayerJoined(builder, player, codec)
+		fc.playerJoined[key] = playerJoined
 
 		return playerJoined
 	}
@@ -30,21 +41,36 @@ func (fc *FlatCache) GetMutatedPlayerJoined(id int, player types.Player) *flatge
 	flatPlayer := &flatgen.Player{}
 	playerJoined.Player(flatPlayer)
 
-	flatPlayer.MutateX(int32(player.X))
-	flatPlayer.MutateY(int32(player.Y))
+	x, y := codec.Encode(player.X, player.Y)
+
+	flatPlayer.MutateX(x)
+	flatPlayer.MutateY(y)
 	flatPlayer.MutateSpeed(int32(player.Speed))
-	flatPlayer.MutateMovingDown(player.MovingDown)
-	flatPlayer.MutateMovingLeft(player.MovingLeft)
-	flatPlayer.MutateMovingRight(player.MovingRight)
-	flatPlayer.MutateMovingUp(player.MovingUp)
+	flatPlayer.MutateInputX(float32(player.InputX))
+	flatPlayer.MutateInputY(float32(player.InputY))
+	flatPlayer.MutateVx(float32(player.VX))
+	flatPlayer.MutateVy(float32(player.VY))
 
 	return playerJoined
 }
 
 func (fc FlatCache) AddJoin(id int, joinEvent *flatgen.PlayerJoined) {
-	fc.playerJoined[id] = joinEvent
+	fc.playerJoined[joinKey{id: id, codec: types.IntegerCodec}] = joinEvent
 }
 
 func (fc FlatCache) RemoveJoin(id int) {
-	delete(fc.playerJoined, id)
+	for key := range fc.playerJoined {
+		if key.id == id {
+			delete(fc.playerJoined, key)
+		}
+	}
+}
+
+// SetWorldMap serializes the map once, every player that joins gets the same bytes.
+func (fc *FlatCache) SetWorldMap(worldMap *world.Map) {
+	fc.worldMap = utils.NewFlatWorldMap(flatbuffers.NewBuilder(1024), worldMap)
+}
+
+func (fc *FlatCache) GetWorldMap() *flatgen.WorldMap {
+	return fc.worldMap
 }
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/compression"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/dispatch"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/physics"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/profile"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+)
+
+// TickState is what the event handlers share during a tick, it's reset at the end of it.
+type TickState struct {
+	Ctx        context.Context
+	Start      time.Time
+	BufferPool *BuilderPool
+
+	PlayerMovedList  []Player
+	PlayerJoinedList []Player
+
+	// movedIndex is where each player is in PlayerMovedList
+	movedIndex map[int]int
+}
+
+// AddMoved adds the player to PlayerMovedList. Movement is latest-wins, so a player that
+// moves again in the same tick replaces its previous state instead of being sent twice.
+func (ts *TickState) AddMoved(player Player) {
+	if ts.movedIndex == nil {
+		ts.movedIndex = map[int]int{}
+	}
+
+	if i, ok := ts.movedIndex[player.Id]; ok {
+		ts.PlayerMovedList[i] = player
+		return
+	}
+
+	ts.movedIndex[player.Id] = len(ts.PlayerMovedList)
+	ts.PlayerMovedList = append(ts.PlayerMovedList, player)
+}
+
+func (ts *TickState) Reset() {
+	clear(ts.PlayerMovedList)
+	clear(ts.movedIndex)
+
+	ts.PlayerMovedList = ts.PlayerMovedList[:0]
+	ts.PlayerJoinedList = ts.PlayerJoinedList[:0]
+
+	ts.BufferPool.Reset()
+}
+
+// CurrentTick is the state of the tick being processed, for handlers added by other modules.
+func (game *GameServer) CurrentTick() *TickState {
+	return game.state
+}
+
+// RegisterHandlers adds the handlers of the core game events to game.Events.
+func (game *GameServer) RegisterHandlers() {
+	dispatch.OnLifecycle(game.Events, flatgen.EventKindPlayerHello, game.OnPlayerConnected)
+	dispatch.OnLifecycle(game.Events, flatgen.EventKindPlayerQuit, game.OnPlayerDisconnected)
+
+	dispatch.On(game.Events, flatgen.EventKindPlayerHelloConfirm, game.OnPlayerHelloConfirm)
+	dispatch.On(game.Events, flatgen.EventKindPlayerMoved, game.OnPlayerMoved)
+	dispatch.On(game.Events, flatgen.EventKindTimeSync, game.OnTimeSync)
+	dispatch.On(game.Events, flatgen.EventKindPong, game.OnPong)
+	dispatch.On(game.Events, flatgen.EventKindPlayerFire, game.OnPlayerFire)
+
+	game.Events.Fallback(func(event Event) {
+		game.log.Debugf("no handler for event '%s' from player '%v'", flatgen.EnumNamesEventKind[event.Kind], event.PlayerId)
+	})
+}
+
+func (game *GameServer) OnPlayerConnected(event Event, _ PlayerConnected) {
+	newPlayer := PlayerWithSocket{
+		Conn: event.Conn,
+		Player: Player{
+			Id: event.PlayerId,
+		},
+		LastInput: game.state.Start,
+		LastWrite: game.state.Start,
+	}
+
+	game.spawnPlayer(&newPlayer.Player)
+
+	game.Players.Set(newPlayer.Id, newPlayer)
+	game.EventCollector.AddPlayer(newPlayer.Id)
+
+	// game.log.Infof("Player connected: '%v'", event.PlayerId)
+
+	// a client that isn't remembered yet keeps the key and joins with it
+	profileKey := ""
+	if store, ok := game.Players.(ProfileStore); ok {
+		profileKey = store.NewProfileKey()
+	}
+
+	eventData := utils.NewFlatPlayerHello(game.state.BufferPool.GetFreeBuilder(), newPlayer.Player, PositionEncodings, FixedPointScale,
+		Compressions, compression.DictionaryVersion, profileKey).Table().Bytes
+
+	err := writeTo(game.state.Ctx, newPlayer, eventData)
+	if err != nil {
+		game.log.Errorf("err: %s\n", err.Error())
+	}
+}
+
+// spawnPlayer puts the player in the world where the Spawner picks, alive and with full health.
+// It's how a player joins and how it comes back after it dies.
+func (game *GameServer) spawnPlayer(player *Player) {
+	game.Spawner.Spawn(game.World, game.Players, player)
+
+	player.Health, player.State = MaxHealth, Alive
+	player.VX, player.VY = 0, 0
+}
+
+func (game *GameServer) OnPlayerHelloConfirm(event Event, helloResponse *flatgen.PlayerHelloConfirm) {
+	if helloResponse.Id() != int32(event.PlayerId) {
+		game.log.Debugf("player ID doesn't match expected:'%d', given:'%d'", event.PlayerId, helloResponse.Id())
+		closeConn(event.Conn)
+
+		return
+	}
+
+	newPlayer, ok := game.Players.Get(event.PlayerId)
+	if !ok {
+		return
+	}
+
+	codec, err := game.NewPositionCodec(helloResponse.PositionEncoding())
+	if err != nil {
+		game.log.Errorf("player '%v': %v", event.PlayerId, err)
+		closeConn(event.Conn)
+
+		return
+	}
+
+	newPlayer.Codec = codec
+	newPlayer.Compression = game.NewCompression(helloResponse.Compression(), int(helloResponse.DictionaryVersion()))
+
+	if store, ok := game.Players.(ProfileStore); ok {
+		if key := string(helloResponse.Profile()); store.IssuedProfileKey(key) {
+			// the profile is read off the tick, the player joins once it's back
+			game.Players.Set(newPlayer.Id, newPlayer)
+			go game.loadProfile(store, newPlayer.Id, key)
+
+			return
+		}
+	}
+
+	game.joinPlayer(newPlayer)
+}
+
+// loadProfile reads the profile of a returning player and makes the next tick put the player
+// back where it left and join it.
+func (game *GameServer) loadProfile(store ProfileStore, playerId int, key string) {
+	saved, found := store.LoadProfile(key)
+
+	game.tickCalls <- func() {
+		player, ok := game.Players.Get(playerId)
+		if !ok {
+			// it left while the profile was read
+			return
+		}
+
+		player.Profile = key
+		if found {
+			game.restoreProfile(saved, &player)
+		}
+
+		game.joinPlayer(player)
+	}
+}
+
+// joinPlayer makes the confirmed player join, it gets the map and the players already in it
+// with their health.
+func (game *GameServer) joinPlayer(newPlayer PlayerWithSocket) {
+	game.Players.Set(newPlayer.Id, newPlayer)
+	game.EventCollector.SetPositionCodec(newPlayer.Id, newPlayer.Codec)
+
+	if conn, ok := newPlayer.Conn.(transport.DatagramConn); ok {
+		game.EventCollector.SetDatagramSize(newPlayer.Id, conn.MaxDatagramSize())
+	}
+
+	game.Send(ToPlayer(newPlayer.Id), utils.NewEventHolder(flatgen.EventKindWorldMap, game.FlatCache.GetWorldMap()))
+
+	game.state.PlayerJoinedList = append(game.state.PlayerJoinedList, newPlayer.Player)
+
+	for _, otherPlayer := range game.Players.All() {
+		otherPlayerJoined := game.FlatCache.GetMutatedPlayerJoined(otherPlayer.Id, otherPlayer.Player, newPlayer.Codec)
+
+		flatOtherPlayerJoinedEvent := utils.NewEventHolder(flatgen.EventKindPlayerJoined, otherPlayerJoined)
+		if otherPlayer.Id != newPlayer.Id && !otherPlayer.Spectator {
+			game.Send(ToPlayer(newPlayer.Id), flatOtherPlayerJoinedEvent)
+			game.sendHealth(newPlayer.Id, otherPlayer)
+		}
+	}
+
+	game.sendEntities(newPlayer)
+}
+
+// restoreProfile puts a returning player back where it left, in its team. When that place is
+// blocked now, after a map change, the player is spawned again.
+func (game *GameServer) restoreProfile(saved profile.Profile, player *PlayerWithSocket) {
+	player.Team = saved.Team
+
+	if game.World.Blocked(saved.X, saved.Y, PlayerSize) {
+		game.spawnPlayer(&player.Player)
+		return
+	}
+
+	player.X, player.Y = saved.X, saved.Y
+}
+
+func (game *GameServer) OnPlayerDisconnected(event Event, _ PlayerDisconnected) {
+	playerQuit := utils.NewFlatPlayerQuit(game.state.BufferPool.GetFreeBuilder(), event.PlayerId)
+	playerQuitEvent := utils.NewEventHolder(flatgen.EventKindPlayerQuit, playerQuit)
+
+	game.Players.Delete(event.PlayerId)
+	game.EventCollector.RemovePlayer(event.PlayerId)
+	game.FlatCache.RemoveJoin(event.PlayerId)
+	game.Entities.DespawnOwnedBy(event.PlayerId)
+	delete(game.history, event.PlayerId)
+
+	game.Send(ToAll(), playerQuitEvent)
+}
+
+func (game *GameServer) OnPlayerMoved(event Event, playerMoved *flatgen.PlayerMoved) {
+	newPlayerInfo := playerMoved.Player(nil)
+	if newPlayerInfo == nil {
+		game.log.Errorf("player '%v' sent a PlayerMoved without a player", event.PlayerId)
+
+		return
+	}
+
+	if newPlayerInfo.Id() != int32(event.PlayerId) {
+		game.log.Errorf("player '%v' tried to cheat, expected id '%v' but got '%v'", event.PlayerId, event.PlayerId, newPlayerInfo.Id())
+		closeConn(event.Conn)
+
+		return
+	}
+
+	player, ok := game.Players.Get(event.PlayerId)
+	if !ok {
+		return
+	}
+
+	player.LastInput = game.state.Start
+	player.IdleWarned = false
+
+	// the dead wait for their respawn where they died
+	if player.State == Dead {
+		game.Players.Set(player.Id, player)
+		return
+	}
+
+	player.InputX, player.InputY = physics.NormalizeInput(float64(newPlayerInfo.InputX()), float64(newPlayerInfo.InputY()))
+
+	// A spectator that moves joins the world again
+	if player.Spectator {
+		player.Spectator = false
+		game.state.PlayerJoinedList = append(game.state.PlayerJoinedList, player.Player)
+	}
+
+	game.Players.Set(player.Id, player)
+	game.state.AddMoved(player.Player)
+}
+
+func (game *GameServer) OnTimeSync(event Event, timeSync *flatgen.TimeSync) {
+	timeSyncReply := utils.NewFlatTimeSyncReply(game.state.BufferPool.GetFreeBuilder(), timeSync.ClientTime(), game.ServerTime(), game.tick)
+	game.Send(ToPlayer(event.PlayerId), utils.NewEventHolder(flatgen.EventKindTimeSyncReply, timeSyncReply))
+}
+
+func (game *GameServer) OnPong(event Event, pong *flatgen.Pong) {
+	player, ok := game.Players.Get(event.PlayerId)
+	if !ok {
+		return
+	}
+
+	player.Latency.PongReceived(pong.Seq(), event.ReceivedAt)
+	game.Players.Set(player.Id, player)
+}
+
+// closeConn drops a connection without the close handshake, which would block the tick.
+func closeConn(conn transport.Conn) {
+	if conn != nil {
+		conn.Close()
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import "time"
+
+// historyLength is how many positions a PositionHistory keeps, a second at 30 ticks per second
+// is more than MaxRewind.
+const historyLength = 32
+
+// PositionHistory is where a player was in the last ticks, so a shot can be checked against
+// the world the shooter saw instead of the one the server has now.
+type PositionHistory struct {
+	samples [historyLength]positionSample
+	// next is the slot the next sample goes in, count how many slots are used
+	next, count int
+}
+
+type positionSample struct {
+	at   time.Time
+	x, y float64
+}
+
+// Record adds where the player is at the given time, which must not be before the last one.
+func (h *PositionHistory) Record(at time.Time, x, y float64) {
+	h.samples[h.next] = positionSample{at: at, x: x, y: y}
+	h.next = (h.next + 1) % historyLength
+	h.count = min(h.count+1, historyLength)
+}
+
+// At returns where the player was at the given time, between two samples the position is
+// interpolated. Times before the oldest sample get the oldest one, after the newest the newest.
+func (h *PositionHistory) At(at time.Time) (float64, float64, bool) {
+	if h.count == 0 {
+		return 0, 0, false
+	}
+
+	newer := h.sample(0)
+	if !at.Before(newer.at) {
+		return newer.x, newer.y, true
+	}
+
+	for i := 1; i < h.count; i++ {
+		older := h.sample(i)
+
+		if !at.Before(older.at) {
+			fraction := float64(at.Sub(older.at)) / float64(newer.at.Sub(older.at))
+
+			return older.x + (newer.x-older.x)*fraction, older.y + (newer.y-older.y)*fraction, true
+		}
+
+		newer = older
+	}
+
+	return newer.x, newer.y, true
+}
+
+func (h *PositionHistory) Reset() {
+	h.next, h.count = 0, 0
+}
+
+// sample is the i-th newest sample, 0 being the last one recorded.
+func (h *PositionHistory) sample(i int) positionSample {
+	return h.samples[(h.next-1-i+2*historyLength)%historyLength]
+}
@@ -0,0 +1,49 @@
+package server_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+)
+
+func TestPositionHistory(t *testing.T) {
+	history := server.PositionHistory{}
+	start := time.Now()
+
+	if _, _, ok := history.At(start); ok {
+		t.Fatal("expected an empty history to have no position")
+	}
+
+	for i := range 40 {
+		history.Record(start.Add(time.Duration(i)*100*time.Millisecond), float64(i*10), 5)
+	}
+
+	testCases := []struct {
+		name     string
+		at       time.Duration
+		expected float64
+	}{
+		{"on a sample", 3500 * time.Millisecond, 350},
+		{"between samples", 3525 * time.Millisecond, 352.5},
+		{"after the newest", time.Hour, 390},
+		// only the last 32 samples are kept
+		{"before the oldest", 0, 80},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			x, y, ok := history.At(start.Add(tc.at))
+
+			if !ok || x != tc.expected || y != 5 {
+				t.Fatalf("expected (%v, 5), got (%v, %v) %v", tc.expected, x, y, ok)
+			}
+		})
+	}
+
+	history.Reset()
+
+	if _, _, ok := history.At(start); ok {
+		t.Fatal("expected a reset history to have no position")
+	}
+}
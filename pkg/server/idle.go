@@ -0,0 +1,110 @@
+package server
+
+import (
+	"time"
+
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// A player that doesn't send any input for IdleWarnAfter gets an IdleWarning, after
+// IdleTimeout IdleAction is taken. A connection we couldn't write to for StaleWriteAfter
+// is considered half-open and closed. A zero duration disables that check.
+var IdleWarnAfter = 60 * time.Second
+var IdleTimeout = 90 * time.Second
+var IdleAction = flatgen.IdleActionSpectate
+var StaleWriteAfter = 10 * time.Second
+
+// WriteTimeout is how long a write to a player can block the tick, about a tick at the default
+// ServerFPS. A write that times out fails and closes the connection, a peer that stopped
+// reading would block every tick after it and part of the message may already be written.
+// Zero disables it.
+var WriteTimeout = time.Second / 30
+
+type IdleStatus int
+
+const (
+	IdleStatusActive IdleStatus = iota
+	IdleStatusWarn
+	IdleStatusExpired
+	IdleStatusStale
+)
+
+// CheckIdle tells what should happen to a player at time now. Spectators are already idle,
+// only a stale connection matters for them.
+func CheckIdle(player PlayerWithSocket, now time.Time) IdleStatus {
+	if StaleWriteAfter > 0 && !player.LastWrite.IsZero() && now.Sub(player.LastWrite) >= StaleWriteAfter {
+		return IdleStatusStale
+	}
+
+	if player.Spectator || player.LastInput.IsZero() {
+		return IdleStatusActive
+	}
+
+	idleFor := now.Sub(player.LastInput)
+
+	switch {
+	case IdleTimeout > 0 && idleFor >= IdleTimeout:
+		return IdleStatusExpired
+	case IdleWarnAfter > 0 && idleFor >= IdleWarnAfter && !player.IdleWarned:
+		return IdleStatusWarn
+	default:
+		return IdleStatusActive
+	}
+}
+
+// HandleIdlePlayers warns, moves to spectators or disconnects the players that stopped
+// playing. Disconnected players are removed by the PlayerQuit their read loop queues.
+func (game *GameServer) HandleIdlePlayers(bufferPool *BuilderPool, now time.Time) {
+	var idleEvent EventHolder
+
+	for id, player := range game.Players.All() {
+		switch CheckIdle(player, now) {
+		case IdleStatusStale:
+			game.log.Infof("no successful write to player '%v' since %v, disconnecting", id, player.LastWrite)
+			closeConn(player.Conn)
+		case IdleStatusWarn:
+			secondsLeft := (IdleTimeout - now.Sub(player.LastInput)).Seconds()
+
+			warning := utils.NewFlatIdleWarning(bufferPool.GetFreeBuilder(), IdleAction, float32(max(secondsLeft, 0)))
+			game.Send(ToPlayer(id), utils.NewEventHolder(flatgen.EventKindIdleWarning, warning))
+
+			player.IdleWarned = true
+			game.Players.Set(id, player)
+		case IdleStatusExpired:
+			if IdleAction == flatgen.IdleActionKick {
+				// The warning already told them, the close handshake would block the tick
+				game.log.Infof("player '%v' was idle for %v, disconnecting", id, now.Sub(player.LastInput))
+				closeConn(player.Conn)
+
+				continue
+			}
+
+			if idleEvent == nil {
+				idleEvent = utils.NewEventHolder(flatgen.EventKindIdleWarning, utils.NewFlatIdleWarning(bufferPool.GetFreeBuilder(), IdleAction, 0))
+			}
+
+			game.Send(ToPlayer(id), idleEvent)
+			game.Spectate(bufferPool.GetFreeBuilder(), player)
+		}
+	}
+}
+
+// Spectate takes the player out of the world, the other players see them quit. The next
+// input they send brings them back.
+func (game *GameServer) Spectate(builder *flatbuffers.Builder, player PlayerWithSocket) {
+	player.Spectator = true
+	player.IdleWarned = false
+	player.InputX, player.InputY = 0, 0
+	player.VX, player.VY = 0, 0
+
+	game.Players.Set(player.Id, player)
+
+	playerQuit := utils.NewFlatPlayerQuit(builder, player.Id)
+	playerQuitEvent := utils.NewEventHolder(flatgen.EventKindPlayerQuit, playerQuit)
+
+	game.Send(ToAllExcept(player.Id), playerQuitEvent)
+}
@@ -0,0 +1,106 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func TestCheckIdle(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name   string
+		player types.PlayerWithSocket
+		status server.IdleStatus
+	}{
+		{
+			name:   "active",
+			player: types.PlayerWithSocket{LastInput: now.Add(-time.Second), LastWrite: now},
+			status: server.IdleStatusActive,
+		},
+		{
+			name:   "warn",
+			player: types.PlayerWithSocket{LastInput: now.Add(-server.IdleWarnAfter), LastWrite: now},
+			status: server.IdleStatusWarn,
+		},
+		{
+			name:   "already warned",
+			player: types.PlayerWithSocket{LastInput: now.Add(-server.IdleWarnAfter), LastWrite: now, IdleWarned: true},
+			status: server.IdleStatusActive,
+		},
+		{
+			name:   "expired",
+			player: types.PlayerWithSocket{LastInput: now.Add(-server.IdleTimeout), LastWrite: now, IdleWarned: true},
+			status: server.IdleStatusExpired,
+		},
+		{
+			name:   "spectator",
+			player: types.PlayerWithSocket{LastInput: now.Add(-2 * server.IdleTimeout), LastWrite: now, Spectator: true},
+			status: server.IdleStatusActive,
+		},
+		{
+			name:   "stale connection",
+			player: types.PlayerWithSocket{LastInput: now, LastWrite: now.Add(-server.StaleWriteAfter)},
+			status: server.IdleStatusStale,
+		},
+	}
+
+	for _, tc := range testCases {
+		if status := server.CheckIdle(tc.player, now); status != tc.status {
+			t.Errorf("%s: expected status %v, got %v", tc.name, tc.status, status)
+		}
+	}
+}
+
+func TestBlockedWriteDoesNotStallTheTick(t *testing.T) {
+	pipeBuffer := transport.PipeBuffer
+	t.Cleanup(func() { transport.PipeBuffer = pipeBuffer })
+
+	transport.PipeBuffer = 4
+
+	game := newTestGame()
+	ctx := context.Background()
+
+	// the client reads the hello and then never again
+	client := joinOnPipe(t, game, 1)
+	game.RunTick(ctx, time.Millisecond)
+
+	lastTick := time.Now()
+
+	for tick := range 10 {
+		lastTick = time.Now()
+		game.Send(server.ToAll(), utils.NewEventHolder(flatgen.EventKindPlayerQuit, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), 100+tick)))
+
+		done := make(chan struct{})
+		go func() {
+			game.RunTick(ctx, time.Millisecond)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * server.WriteTimeout):
+			t.Fatalf("tick %d is blocked on the write to a player that doesn't read", tick)
+		}
+	}
+
+	if _, err := client.Read(ctx); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected the player that doesn't read to be disconnected, got %v", err)
+	}
+
+	// the writes after the buffer filled up failed
+	if player, _ := game.Players.Get(1); !player.LastWrite.Before(lastTick) {
+		t.Errorf("expected the failed writes to leave LastWrite before %v, got %v", lastTick, player.LastWrite)
+	}
+}
@@ -0,0 +1,312 @@
+package server_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/verify"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// fakeClient is a player connected over a transport, it keeps what it got from the server.
+type fakeClient struct {
+	conn transport.Conn
+	id   int
+
+	mu     sync.Mutex
+	joined bool
+	// joins is how many times the client was in a PlayerJoinedList
+	joins int
+	moved map[int32]bool
+	quits map[int32]bool
+}
+
+// connect dials the server and confirms its hello, then reads events until the connection
+// is closed.
+func connect(ctx context.Context, t *testing.T, dial func(context.Context) (transport.Conn, error), joined *atomic.Int64) *fakeClient {
+	t.Helper()
+
+	conn, err := dial(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeClient{conn: conn, moved: map[int32]bool{}, quits: map[int32]bool{}}
+
+	go func() {
+		data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		_, hello, err := utils.ParseEventBytes(data)
+		if err != nil {
+			return
+		}
+
+		client.id = int(hello.(*flatgen.PlayerHello).Id())
+
+		confirm := utils.NewFlatPlayerHelloConfirm(flatbuffers.NewBuilder(64), client.id, flatgen.PositionEncodingInteger, flatgen.CompressionNone, 0, "")
+		if err := conn.Write(ctx, confirm.Table().Bytes); err != nil {
+			return
+		}
+
+		for {
+			data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+
+			if err := verify.Root(data, verify.EventList); err != nil {
+				t.Errorf("client %v got a bad event list: %v", client.id, err)
+				return
+			}
+
+			client.receive(ctx, flatgen.GetRootAsEventList(data, 0), joined)
+		}
+	}()
+
+	return client
+}
+
+func (fc *fakeClient) receive(ctx context.Context, eventList *flatgen.EventList, joined *atomic.Int64) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	rawEvent := &flatgen.RawEvent{}
+	player := &flatgen.Player{}
+
+	for i := range eventList.EventsLength() {
+		eventList.Events(rawEvent, i)
+
+		kind, data, err := utils.ParseEventBytes(rawEvent.RawDataBytes())
+		if err != nil {
+			continue
+		}
+
+		switch kind {
+		case flatgen.EventKindPlayerJoinedList:
+			joinedList := data.(*flatgen.PlayerJoinedList)
+
+			for j := range joinedList.PlayersLength() {
+				joinedList.Players(player, j)
+
+				if int(player.Id()) != fc.id {
+					continue
+				}
+
+				fc.joins++
+
+				if !fc.joined {
+					fc.joined = true
+					joined.Add(1)
+				}
+			}
+		case flatgen.EventKindPlayerMovedList:
+			movedList := data.(*flatgen.PlayerMovedList)
+
+			for j := range movedList.PlayersLength() {
+				movedList.Players(player, j)
+				fc.moved[player.Id()] = true
+			}
+		case flatgen.EventKindPlayerQuit:
+			fc.quits[data.(*flatgen.PlayerQuit).Id()] = true
+		case flatgen.EventKindPing:
+			// A slow run shouldn't get the client kicked for missing pongs. The write can wait
+			// for the server to read, which waits for the ticks, so it doesn't hold the reads.
+			pong := utils.NewFlatPong(flatbuffers.NewBuilder(32), data.(*flatgen.Ping).Seq())
+			go fc.conn.Write(ctx, pong.Table().Bytes)
+		}
+	}
+}
+
+func (fc *fakeClient) count(seen func(fc *fakeClient) int) int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	return seen(fc)
+}
+
+// tickUntil runs ticks of the game until done returns true.
+func tickUntil(ctx context.Context, t *testing.T, game *server.GameServer, what string, done func() bool) {
+	t.Helper()
+
+	for !done() {
+		if ctx.Err() != nil {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+
+		game.RunTick(ctx, time.Second/time.Duration(server.ServerFPS))
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGameOverPipe(t *testing.T) {
+	pipe := transport.NewPipe()
+
+	testGameOver(t, 200, pipe, pipe.Dial)
+}
+
+func TestGameOverTCP(t *testing.T) {
+	tcp, err := transport.ListenTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testGameOver(t, 200, tcp, func(ctx context.Context) (transport.Conn, error) {
+		return transport.DialTCP(ctx, tcp.Addr().String())
+	})
+}
+
+func TestGameOverUDP(t *testing.T) {
+	udp, err := transport.ListenUDP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testGameOver(t, 200, udp, func(ctx context.Context) (transport.Conn, error) {
+		return transport.DialUDP(ctx, udp.Addr().String())
+	})
+}
+
+func TestGameOverWebTransport(t *testing.T) {
+	tlsConfig, hash, err := transport.SelfSignedTLS("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webTransport, err := transport.ListenWebTransport("127.0.0.1:0", "/webtransport", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url := "https://" + webTransport.Addr().String() + "/webtransport"
+
+	// every QUIC connection does its own encryption and pacing, fewer clients keep it quick
+	testGameOver(t, 50, webTransport, func(ctx context.Context) (transport.Conn, error) {
+		return transport.DialWebTransport(ctx, url, transport.VerifyCertificateHash(hash))
+	})
+}
+
+// testGameOver plays a game of clientCount clients over the transport: they join, move and
+// half of them quit.
+func testGameOver(t *testing.T, clientCount int, gameTransport transport.Transport, dial func(context.Context) (transport.Conn, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	game := newTestGame()
+	game.SetLogEnabled(false)
+
+	go gameTransport.Serve(ctx, game.HandleConn)
+
+	joined := &atomic.Int64{}
+	clients := make([]*fakeClient, clientCount)
+
+	for i := range clients {
+		clients[i] = connect(ctx, t, dial, joined)
+	}
+
+	tickUntil(ctx, t, game, "the clients to join", func() bool {
+		return joined.Load() == int64(clientCount)
+	})
+
+	for _, client := range clients {
+		if joins := client.count(func(fc *fakeClient) int { return fc.joins }); joins != 1 {
+			t.Fatalf("client %v joined %v times", client.id, joins)
+		}
+	}
+
+	for _, client := range clients {
+		moved := utils.NewFlatPlayerMoved(flatbuffers.NewBuilder(128), types.Player{Id: client.id, InputX: 1}, types.IntegerCodec)
+
+		if err := client.conn.Write(ctx, moved.Table().Bytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tickUntil(ctx, t, game, "every client to see every move", func() bool {
+		for _, client := range clients {
+			if client.count(func(fc *fakeClient) int { return len(fc.moved) }) != clientCount {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	leaving, staying := clients[:clientCount/2], clients[clientCount/2:]
+	for _, client := range leaving {
+		client.conn.Close()
+	}
+
+	tickUntil(ctx, t, game, "the quits", func() bool {
+		for _, client := range staying {
+			if client.count(func(fc *fakeClient) int { return len(fc.quits) }) != len(leaving) {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	for _, client := range leaving {
+		if _, ok := game.Players.Get(client.id); ok {
+			t.Fatalf("player %v left but it's still in the game", client.id)
+		}
+	}
+
+	for _, client := range staying {
+		if _, ok := game.Players.Get(client.id); !ok {
+			t.Fatalf("player %v is missing", client.id)
+		}
+	}
+}
+
+func TestRTTIsMeasuredWhenThePongArrives(t *testing.T) {
+	pingInterval := server.PingInterval
+	t.Cleanup(func() { server.PingInterval = pingInterval })
+
+	server.PingInterval = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipe := transport.NewPipe()
+	game := newTestGame()
+	game.SetLogEnabled(false)
+
+	go pipe.Serve(ctx, game.HandleConn)
+
+	joined := &atomic.Int64{}
+	client := connect(ctx, t, pipe.Dial, joined)
+
+	tickUntil(ctx, t, game, "the client to join", func() bool {
+		return joined.Load() == 1
+	})
+
+	// The ticks are far apart, the pongs wait in the queue for most of the time between them
+	for range 5 {
+		game.RunTick(ctx, time.Second/time.Duration(server.ServerFPS))
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	game.RunTick(ctx, time.Second/time.Duration(server.ServerFPS))
+
+	player, ok := game.Players.Get(client.id)
+	if !ok || player.Latency.LastPong.IsZero() {
+		t.Fatalf("expected the client to answer the pings")
+	}
+
+	if player.Latency.RTT >= 50*time.Millisecond {
+		t.Errorf("the RTT shouldn't count the time the pong waited for a tick, got %v", player.Latency.RTT)
+	}
+}
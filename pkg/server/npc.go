@@ -0,0 +1,114 @@
+package server
+
+import (
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/npc"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+// NPCCount NPCs are spawned when the server starts, their behaviors are taken in turn from
+// NPCBehaviors. Following and fleeing NPCs see the players NPCSight away, a patrolling one walks
+// around a square of side PatrolSize starting where it spawned.
+var NPCCount = 0
+var NPCBehaviors = []npc.Kind{npc.Wander, npc.FollowNearest, npc.Flee, npc.Patrol}
+var NPCSight = float64(300)
+var PatrolSize = float64(200)
+
+// NPC is a character the server plays. It's an entity of the Npc kind that moves like a player,
+// its moves are sent with the players' ones.
+type NPC struct {
+	Player
+	Behavior npc.Behavior
+}
+
+// SpawnNPC adds an NPC where the Spawner would put a player.
+func (game *GameServer) SpawnNPC(behavior npc.Behavior) int {
+	id := game.SpawnEntity(flatgen.EntityKindNpc)
+
+	character := NPC{Player: Player{Id: id}, Behavior: behavior}
+	game.Spawner.Spawn(game.World, game.Players, &character.Player)
+
+	game.npcs.Set(id, character)
+	game.Entities.Transforms.Set(id, ecs.Transform{X: character.X, Y: character.Y})
+	game.Entities.Colliders.Set(id, ecs.Collider{Size: PlayerSize})
+
+	return id
+}
+
+// NPC returns the NPC so it can be changed in place, the pointer is only valid until the next
+// NPC is spawned.
+func (game *GameServer) NPC(id int) (*NPC, bool) {
+	return game.npcs.Get(id)
+}
+
+func (game *GameServer) spawnNPCs() {
+	if len(NPCBehaviors) == 0 {
+		return
+	}
+
+	for i := range NPCCount {
+		kind := NPCBehaviors[i%len(NPCBehaviors)]
+		id := game.SpawnNPC(npc.Behavior{Kind: kind, Sight: NPCSight})
+
+		if kind == npc.Patrol {
+			character, _ := game.NPC(id)
+			character.Behavior.Waypoints = patrolSquare(game.World, character.X, character.Y)
+		}
+	}
+}
+
+// patrolSquare is a square of side PatrolSize with a corner at (x, y), kept inside the map.
+func patrolSquare(worldMap *world.Map, x, y float64) []npc.Point {
+	right, down := min(x+PatrolSize, worldMap.Width-PlayerSize), min(y+PatrolSize, worldMap.Height-PlayerSize)
+
+	return []npc.Point{{X: x, Y: y}, {X: right, Y: y}, {X: right, Y: down}, {X: x, Y: down}}
+}
+
+// moveNPCs moves the NPCs with game.Movement and the input their behavior picks, like the
+// players. The ones that moved are sent with the players' moves of the next tick.
+func (game *GameServer) moveNPCs(dt float64) {
+	if game.npcs.Len() == 0 {
+		return
+	}
+
+	targets := game.npcTargets[:0]
+	for _, player := range game.Players.All() {
+		if !player.Spectator && player.State == Alive {
+			targets = append(targets, npc.Point{X: player.X, Y: player.Y})
+		}
+	}
+	game.npcTargets = targets
+
+	for id, character := range game.npcs.All() {
+		before := character.Player
+
+		character.InputX, character.InputY = character.Behavior.Input(character.X, character.Y, targets, game.npcRand, dt)
+		game.Movement.Step(&character.Player, game.World, PlayerSize, dt)
+
+		if character.Player == before {
+			continue
+		}
+
+		if transform, ok := game.Entities.Transforms.Get(id); ok {
+			transform.X, transform.Y = character.X, character.Y
+		}
+
+		game.state.AddMoved(character.Player)
+	}
+}
+
+// mover is the player or NPC with the id as it's sent in the moves, spectators and the dead
+// aren't.
+func (game *GameServer) mover(id int) (Player, bool) {
+	if player, ok := game.Players.Get(id); ok {
+		return player.Player, !player.Spectator && player.State == Alive
+	}
+
+	if character, ok := game.npcs.Get(id); ok {
+		return character.Player, true
+	}
+
+	return Player{}, false
+}
@@ -0,0 +1,75 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/npc"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+)
+
+func TestNPCsMoveWithThePlayers(t *testing.T) {
+	game := newTestGame()
+	ctx := context.Background()
+
+	client := joinOnPipe(t, game, 1)
+	game.RunTick(ctx, time.Millisecond)
+
+	player, _ := game.Players.Get(1)
+	player.X, player.Y = 400, 400
+	game.Players.Set(1, player)
+
+	id := game.SpawnNPC(npc.Behavior{Kind: npc.FollowNearest})
+	character, ok := game.NPC(id)
+	if !ok {
+		t.Fatalf("NPC %v is missing", id)
+	}
+
+	character.X, character.Y = 600, 400
+
+	for range 3 {
+		game.RunTick(ctx, 50*time.Millisecond)
+	}
+
+	spawned, moved := false, false
+	receivedKinds(t, client, func(kind flatgen.EventKind, data []byte) {
+		switch kind {
+		case flatgen.EventKindEntitySpawned:
+			event := flatgen.GetRootAsEntitySpawned(data, 0)
+			entity := &flatgen.Entity{}
+
+			for i := range event.EntitiesLength() {
+				event.Entities(entity, i)
+				spawned = spawned || (entity.Id() == int32(id) && entity.Kind() == flatgen.EntityKindNpc)
+			}
+		case flatgen.EventKindPlayerMovedList:
+			event := flatgen.GetRootAsPlayerMovedList(data, 0)
+			moving := &flatgen.Player{}
+
+			for i := range event.PlayersLength() {
+				event.Players(moving, i)
+				moved = moved || moving.Id() == int32(id)
+			}
+		}
+	})
+
+	if !spawned || !moved {
+		t.Fatalf("expected the NPC spawned and in the moves, got spawned %v moved %v", spawned, moved)
+	}
+
+	character, _ = game.NPC(id)
+	if character.X >= 600 || character.InputX >= 0 {
+		t.Fatalf("expected the NPC to walk to the player, it's at %v with input %v", character.X, character.InputX)
+	}
+
+	if state := game.Entities.State(id); state.X != character.X {
+		t.Fatalf("expected the entity to be where the NPC is, got %v and %v", state.X, character.X)
+	}
+
+	game.Entities.Despawn(id)
+
+	if _, ok := game.NPC(id); ok {
+		t.Fatal("expected the despawned NPC to be gone")
+	}
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/log"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/profile"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+)
+
+// ProfilesPath is the file the profiles of the players are saved in, see
+// PersistentPlayerStore. It's empty by default, players are only remembered when it's set.
+var ProfilesPath = ""
+
+// ProfileFlushInterval is how often the places of the players are noted and written, a crash
+// loses at most this much.
+var ProfileFlushInterval = 2 * time.Second
+
+// ProfileStore is a PlayerStore that remembers players between their connections, by the
+// profile their client sends in PlayerHelloConfirm. Only keys the store made are accepted, a
+// client can't take the place of another by guessing its key.
+type ProfileStore interface {
+	PlayerStore
+	// NewProfileKey returns a key for a player that has no profile yet, it's offered in PlayerHello.
+	NewProfileKey() string
+	// IssuedProfileKey tells if the key was made by NewProfileKey.
+	IssuedProfileKey(key string) bool
+	// LoadProfile returns the profile saved for the key, ok is false when there is none. It can
+	// read the disk, so it's called off the tick.
+	LoadProfile(key string) (profile.Profile, bool)
+	// CaptureProfiles notes where the players with a profile are now, to be saved. It reads
+	// the players, so it's called on the tick.
+	CaptureProfiles()
+}
+
+// PersistentPlayerStore keeps the players in another PlayerStore and saves the profile of the
+// ones that have one. CaptureProfiles and Delete only note the profiles, a goroutine writes
+// them every ProfileFlushInterval in one transaction, so the tick never waits on the disk.
+// Players can be changed in place through Ref when the other store is a MutablePlayerStore.
+type PersistentPlayerStore struct {
+	PlayerStore
+
+	profiles *profile.Store
+	log      log.MeloLog
+
+	mu      sync.Mutex
+	pending map[string]profile.Profile
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func NewPersistentPlayerStore(players PlayerStore, profiles *profile.Store, log log.MeloLog) *PersistentPlayerStore {
+	ps := &PersistentPlayerStore{
+		PlayerStore: players,
+		profiles:    profiles,
+		log:         log,
+		pending:     map[string]profile.Profile{},
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	go ps.flushLoop()
+
+	return ps
+}
+
+// Ref returns the player in the other store when it's a MutablePlayerStore, ok is false when
+// it isn't or the player isn't there.
+func (ps *PersistentPlayerStore) Ref(id int) (*PlayerWithSocket, bool) {
+	mutable, ok := ps.PlayerStore.(MutablePlayerStore)
+	if !ok {
+		return nil, false
+	}
+
+	return mutable.Ref(id)
+}
+
+// Delete saves where the player left.
+func (ps *PersistentPlayerStore) Delete(id int) {
+	if player, ok := ps.PlayerStore.Get(id); ok {
+		ps.mu.Lock()
+		ps.note(player, time.Now())
+		ps.mu.Unlock()
+	}
+
+	ps.PlayerStore.Delete(id)
+}
+
+func (ps *PersistentPlayerStore) CaptureProfiles() {
+	now := time.Now()
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, player := range ps.PlayerStore.All() {
+		ps.note(player, now)
+	}
+}
+
+// note keeps the profile of the player for the next flush, ps.mu has to be held.
+func (ps *PersistentPlayerStore) note(player PlayerWithSocket, now time.Time) {
+	// spectators aren't in the world, their profile keeps the place they left it from
+	if player.Profile == "" || player.Spectator {
+		return
+	}
+
+	ps.pending[player.Profile] = profile.Profile{
+		Key:      player.Profile,
+		X:        player.X,
+		Y:        player.Y,
+		Team:     player.Team,
+		LastSeen: now,
+	}
+}
+
+func (ps *PersistentPlayerStore) NewProfileKey() string {
+	return ps.profiles.NewKey()
+}
+
+func (ps *PersistentPlayerStore) IssuedProfileKey(key string) bool {
+	return ps.profiles.Issued(key)
+}
+
+func (ps *PersistentPlayerStore) LoadProfile(key string) (profile.Profile, bool) {
+	if !profile.ValidKey(key) {
+		return profile.Profile{}, false
+	}
+
+	// what isn't written yet is newer than the file
+	ps.mu.Lock()
+	pending, ok := ps.pending[key]
+	ps.mu.Unlock()
+
+	if ok {
+		return pending, true
+	}
+
+	saved, ok, err := ps.profiles.Load(key)
+	if err != nil {
+		ps.log.Errorf("err: %s\n", err.Error())
+	}
+
+	return saved, ok
+}
+
+func (ps *PersistentPlayerStore) flushLoop() {
+	defer close(ps.done)
+
+	ticker := time.NewTicker(ProfileFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ps.Flush()
+		case <-ps.stop:
+			ps.Flush()
+			return
+		}
+	}
+}
+
+// Flush writes the profiles that changed since the last flush. The ones that can't be written
+// are kept for the next one, unless they changed since.
+func (ps *PersistentPlayerStore) Flush() error {
+	ps.mu.Lock()
+	batch := ps.pending
+	ps.pending = make(map[string]profile.Profile, len(batch))
+	ps.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := ps.profiles.Save(slices.Collect(maps.Values(batch)))
+	if err != nil {
+		ps.log.Errorf("err: %s\n", err.Error())
+
+		ps.mu.Lock()
+		for key, unsaved := range batch {
+			if _, changed := ps.pending[key]; !changed {
+				ps.pending[key] = unsaved
+			}
+		}
+		ps.mu.Unlock()
+	}
+
+	return err
+}
+
+// Close writes what's left and closes the profiles. The players stay in the store, but their
+// profiles aren't saved anymore.
+func (ps *PersistentPlayerStore) Close() error {
+	ps.closeOnce.Do(func() {
+		close(ps.stop)
+		<-ps.done
+
+		ps.closeErr = ps.profiles.Close()
+	})
+
+	return ps.closeErr
+}
+
+// openProfiles makes the game remember its players in ProfilesPath, on top of its PlayerStore.
+func (game *GameServer) openProfiles() error {
+	profiles, err := profile.Open(ProfilesPath)
+	if err != nil {
+		return err
+	}
+
+	game.Players = NewPersistentPlayerStore(game.Players, profiles, game.log)
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/log"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/profile"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func openPersistentStore(t *testing.T, path string) *server.PersistentPlayerStore {
+	t.Helper()
+
+	profiles, err := profile.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server.NewPersistentPlayerStore(server.NewPlayerStore(), profiles, log.New(io.Discard))
+}
+
+// joinWithProfile connects the player and confirms its hello with the profile. A player with a
+// key of the store joins once its profile was read, a few ticks later.
+func joinWithProfile(t *testing.T, game *server.GameServer, id int, key string) {
+	t.Helper()
+
+	game.EventQueue <- types.Event{PlayerId: id, Kind: flatgen.EventKindPlayerHello, Data: types.PlayerConnected{}, Lifecycle: true}
+	game.RunTick(context.Background(), time.Millisecond)
+
+	confirm := utils.NewFlatPlayerHelloConfirm(flatbuffers.NewBuilder(64), id, flatgen.PositionEncodingInteger, flatgen.CompressionNone, 0, key)
+	game.EventQueue <- wireEvent(t, game, id, confirm.Table().Bytes)
+	game.RunTick(context.Background(), time.Millisecond)
+
+	if store, ok := game.Players.(server.ProfileStore); !ok || !store.IssuedProfileKey(key) {
+		return
+	}
+
+	for range 100 {
+		if player, _ := game.Players.Get(id); player.Profile == key {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+		game.RunTick(context.Background(), time.Millisecond)
+	}
+
+	t.Fatalf("player %d didn't join with its profile", id)
+}
+
+func TestPersistentStoreWritesInBatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.db")
+	store := openPersistentStore(t, path)
+
+	store.Set(1, types.PlayerWithSocket{Player: types.Player{Id: 1, X: 10, Y: 20, Team: 2}, Profile: "alice"})
+	store.Set(2, types.PlayerWithSocket{Player: types.Player{Id: 2, X: 30, Y: 40}})
+
+	// the places are only noted when the tick captures them
+	if _, ok := store.LoadProfile("alice"); ok {
+		t.Fatal("expected alice to be unknown before the capture")
+	}
+
+	store.CaptureProfiles()
+
+	// not written yet, but already known
+	if alice, ok := store.LoadProfile("alice"); !ok || alice.X != 10 || alice.Y != 20 || alice.Team != 2 {
+		t.Fatalf("expected alice before the flush, got %+v %v", alice, ok)
+	}
+
+	store.Set(1, types.PlayerWithSocket{Player: types.Player{Id: 1, X: 50, Y: 60, Team: 2}, Profile: "alice"})
+	store.Delete(1)
+
+	if _, ok := store.Get(1); ok {
+		t.Fatal("expected alice to be deleted from the players")
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store = openPersistentStore(t, path)
+	defer store.Close()
+
+	if alice, ok := store.LoadProfile("alice"); !ok || alice.X != 50 || alice.Y != 60 {
+		t.Fatalf("expected where alice left to be saved, got %+v %v", alice, ok)
+	}
+}
+
+func TestPersistentStoreKeepsSpectatorsPlace(t *testing.T) {
+	store := openPersistentStore(t, filepath.Join(t.TempDir(), "profiles.db"))
+	defer store.Close()
+
+	store.Set(1, types.PlayerWithSocket{Player: types.Player{Id: 1, X: 10, Y: 20}, Profile: "alice"})
+	store.CaptureProfiles()
+	store.Set(1, types.PlayerWithSocket{Player: types.Player{Id: 1, X: 0, Y: 0}, Profile: "alice", Spectator: true})
+	store.CaptureProfiles()
+
+	if alice, _ := store.LoadProfile("alice"); alice.X != 10 || alice.Y != 20 {
+		t.Fatalf("expected the place alice started spectating from, got %+v", alice)
+	}
+}
+
+func TestPersistentStoreMovesPlayersInPlace(t *testing.T) {
+	profileFlushInterval := server.ProfileFlushInterval
+	t.Cleanup(func() { server.ProfileFlushInterval = profileFlushInterval })
+
+	// the ticks a millisecond apart capture the profiles
+	server.ProfileFlushInterval = time.Millisecond
+
+	profiles, err := profile.Open(filepath.Join(t.TempDir(), "profiles.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := server.NewPersistentPlayerStore(server.NewDensePlayerStore(), profiles, log.New(io.Discard))
+	defer store.Close()
+
+	base := openPersistentStore(t, filepath.Join(t.TempDir(), "base.db"))
+	defer base.Close()
+
+	base.Set(1, types.PlayerWithSocket{Player: types.Player{Id: 1}})
+	if _, ok := base.Ref(1); ok {
+		t.Fatal("expected no Ref over a store that isn't mutable")
+	}
+
+	game := newTestGame()
+	game.SetLogEnabled(false)
+	game.Players = store
+
+	key := store.NewProfileKey()
+	joinWithProfile(t, game, 1, key)
+
+	ref, ok := store.Ref(1)
+	if !ok {
+		t.Fatal("expected a Ref to the player in the dense store")
+	}
+
+	startX := ref.X
+	ref.InputX = 1
+
+	for range 5 {
+		time.Sleep(2 * time.Millisecond)
+		game.RunTick(context.Background(), 50*time.Millisecond)
+	}
+
+	player, _ := store.Get(1)
+	if player.X <= startX {
+		t.Fatalf("expected the player to move right from %v, got %v", startX, player.X)
+	}
+
+	if saved, ok := store.LoadProfile(key); !ok || saved.X != player.X {
+		t.Fatalf("expected the place the player moved to in its profile, got %+v %v", saved, ok)
+	}
+}
+
+func TestReturningPlayerIsRestored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.db")
+
+	game := newTestGame()
+	game.SetLogEnabled(false)
+	store := openPersistentStore(t, path)
+	game.Players = store
+
+	aliceKey, bobKey := store.NewProfileKey(), store.NewProfileKey()
+
+	joinWithProfile(t, game, 1, aliceKey)
+
+	alice, ok := game.Players.Get(1)
+	if !ok || alice.Profile != aliceKey {
+		t.Fatalf("expected alice to join with the profile, got %+v", alice)
+	}
+
+	alice.X, alice.Y, alice.Team = 100, 120, 2
+	game.Players.Set(1, alice)
+
+	game.EventQueue <- types.Event{PlayerId: 1, Kind: flatgen.EventKindPlayerQuit, Data: types.PlayerDisconnected{}, Lifecycle: true}
+	game.RunTick(context.Background(), time.Millisecond)
+
+	if err := game.Players.(*server.PersistentPlayerStore).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the server starts again
+	game = newTestGame()
+	game.SetLogEnabled(false)
+	store = openPersistentStore(t, path)
+	defer store.Close()
+	game.Players = store
+
+	joinWithProfile(t, game, 1, aliceKey)
+
+	alice, _ = game.Players.Get(1)
+	if alice.Team != 2 || alice.X < 100 || alice.X > 101 || alice.Y < 120 || alice.Y > 121 {
+		t.Fatalf("expected alice back at the place left, got %+v", alice.Player)
+	}
+
+	// the map changed and the place is outside of it now
+	store.Set(2, types.PlayerWithSocket{Player: types.Player{Id: 2, X: -50, Y: -50}, Profile: bobKey})
+	store.Delete(2)
+
+	joinWithProfile(t, game, 3, bobKey)
+
+	bob, _ := game.Players.Get(3)
+	if game.World.Blocked(bob.X, bob.Y, server.PlayerSize) {
+		t.Fatalf("expected bob to be spawned again, got %+v", bob.Player)
+	}
+
+	joinWithProfile(t, game, 4, "")
+
+	if anonymous, _ := game.Players.Get(4); anonymous.Profile != "" {
+		t.Fatalf("expected a player without a profile, got %+v", anonymous)
+	}
+
+	// a key the server didn't give out doesn't get anyone's place
+	store.Set(5, types.PlayerWithSocket{Player: types.Player{Id: 5, X: 300, Y: 300, Team: 3}, Profile: "carol"})
+	store.Delete(5)
+
+	joinWithProfile(t, game, 6, "carol")
+
+	if guesser, _ := game.Players.Get(6); guesser.Profile != "" || guesser.Team == 3 {
+		t.Fatalf("expected a made up key to be ignored, got %+v", guesser)
+	}
+}
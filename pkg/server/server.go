@@ -1,19 +1,30 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"os"
 	"runtime"
 	"slices"
+	"sync/atomic"
 	"time"
 
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/compression"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/dispatch"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
 	"github.com/laurentiuNiculae/multiplayer-game/pkg/log"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/npc"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/physics"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
 	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
 	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
 	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
 
 	"github.com/coder/websocket"
 	flatbuffers "github.com/google/flatbuffers/go"
@@ -25,93 +36,148 @@ var WorldHeight = float64(600 * 2)
 var Port = "6969"
 var Address = "127.0.0.1:" + Port
 var HttpAddress = "http://127.0.0.1:" + Port
+var MapPath = "maps/default.json"
+var PlayerSize = float64(8)
+
+// PositionEncodings are the encodings offered to clients in PlayerHello, a client that
+// picks anything else is disconnected.
+var PositionEncodings = []flatgen.PositionEncoding{
+	flatgen.PositionEncodingInteger,
+	flatgen.PositionEncodingFixedPoint,
+}
+var FixedPointScale = 100
+
+// Every player gets a Ping each PingInterval, a player that misses MaxMissedPongs pongs in
+// a row is considered gone and gets disconnected.
+var PingInterval = 1 * time.Second
+var MaxMissedPongs = 5
+
+// TCPAddress and UDPAddress are where the TCP and UDP transports listen, next to the game's
+// Transport. An empty address turns the transport off.
+var TCPAddress = "127.0.0.1:6970"
+var UDPAddress = "127.0.0.1:6971"
 
+// IdGenerator is used by every connection, so it's safe for concurrent use.
 type IdGenerator struct {
-	idCounter int
+	idCounter atomic.Int64
 }
 
 func (igen *IdGenerator) NewId() int {
-	igen.idCounter++
-
-	return igen.idCounter
+	return int(igen.idCounter.Add(1))
 }
 
 type GameServer struct {
 	Players        PlayerStore
 	EventQueue     chan Event
+	tickCalls      chan func() // run by the next tick, for anything that reads the players from another goroutine
 	IdGenerator    IdGenerator
+	Transport      transport.Transport // websockets on /websocket when it's nil
 	EventCollector *EventCollector
 	StatCollector  *StatCollector
 	FlatCache      *FlatCache
+	Events         *dispatch.Registry
+	World          *world.Map
+	Spawner        *Spawner
+	Movement       physics.Model
+	Entities       *ecs.World // everything in the world that isn't a player, see SpawnEntity
+	startTime      time.Time
+	tick           uint32
+	pingSeq        uint32
+	lastPing       time.Time
+	lastCapture    time.Time // when the profiles were last noted, see ProfileStore
+	state          *TickState
 	mux            *http.ServeMux
-	log            log.MeloLog
+
+	// recentMoves is the last tick each player moved in, for the datagrams
+	recentMoves   map[int]uint32
+	datagramMoves []Player
+	settledMoves  []Player
+	webTransport  *WebTransportInfo
+
+	entityStates []ecs.State
+	npcs         *ecs.Components[NPC]
+	npcTargets   []npc.Point
+	npcRand      *rand.Rand
+	projectiles  *ecs.Components[Projectile]
+	history      map[int]*PositionHistory // where the players were, for the hits of the projectiles
+
+	compressor         *compression.Compressor
+	compressionBuilder *flatbuffers.Builder
+
+	log log.MeloLog
 }
 
 func NewGame() GameServer {
+	worldMap := world.NewEmptyMap(WorldWidth, WorldHeight)
+
+	flatCache := NewFlatCache()
+	flatCache.SetWorldMap(worldMap)
+
+	events := dispatch.NewRegistry()
+	utils.RegisterClientDecoders(events)
+
+	entities, npcs, projectiles := ecs.NewWorld(ecs.Move), ecs.NewComponents[NPC](), ecs.NewComponents[Projectile]()
+	entities.Track(npcs)
+	entities.Track(projectiles)
+
 	return GameServer{
-		Players:        NewPlayerStore(),
+		Players:        NewDensePlayerStore(),
 		EventQueue:     make(chan Event, 2000),
+		tickCalls:      make(chan func(), 16),
 		IdGenerator:    IdGenerator{},
 		EventCollector: NewEventCollector(),
 		StatCollector:  NewStatCollector(ServerFPS),
-		FlatCache:      NewFlatCache(),
-		mux:            http.NewServeMux(),
-		log:            log.New(os.Stdout),
+		FlatCache:      flatCache,
+		Events:         events,
+		World:          worldMap,
+		Spawner:        NewSpawner(&ZoneSpawn{}, time.Now().UnixNano()),
+		Movement:       physics.DefaultModel,
+		Entities:       entities,
+		startTime:      time.Now(),
+		npcs:           npcs,
+		npcRand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		projectiles:    projectiles,
+		history:        map[int]*PositionHistory{},
+		state: &TickState{
+			BufferPool:       NewBuilderPool(512, 4),
+			PlayerMovedList:  []Player{},
+			PlayerJoinedList: []Player{},
+		},
+		mux: http.NewServeMux(),
+		log: log.New(os.Stdout),
 	}
 }
 
 func (game *GameServer) Start(ctx context.Context) {
-	game.mux.Handle("/", http.FileServer(http.Dir(".")))
-	game.mux.HandleFunc("/websocket", func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
-
-		wcon, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "err: %s\n", err.Error())
-		}
-
-		playerId := game.IdGenerator.NewId()
-
-		defer func() {
-			builder := flatbuffers.NewBuilder(128)
-
-			game.EventQueue <- Event{
-				PlayerId: playerId,
-				Kind:     flatgen.EventKindPlayerQuit,
-				Conn:     wcon,
-				Data:     flatgen.GetRootAsPlayerQuit(utils.NewFlatPlayerQuit(builder, playerId).Table().Bytes, 0),
-			}
-
-			// game.log.Infof("Player '%v' diconnected", playerId)
-		}()
+	game.LoadMap(MapPath)
+	game.RegisterHandlers()
+	game.spawnNPCs()
 
-		game.EventQueue <- Event{
-			PlayerId: playerId,
-			Kind:     flatgen.EventKindPlayerHello,
-			Conn:     wcon,
-			Data:     PlayerHello{Kind: flatgen.EventKindPlayerHello, Id: playerId},
+	if ProfilesPath != "" {
+		if err := game.openProfiles(); err != nil {
+			game.log.Errorf("err: %s\n", err.Error())
 		}
+	}
 
-		for {
-			_, dataBytes, err := wcon.Read(ctx)
-			if err != nil {
-				return
-			}
+	game.mux.Handle("/", http.FileServer(http.Dir(".")))
+	game.mux.HandleFunc("/admin/players", game.AdminPlayers)
+	game.mux.HandleFunc("/webtransport/info", game.ServeWebTransportInfo)
+
+	if game.Transport == nil {
+		game.Transport = transport.NewWebsocket(game.mux, "/websocket", &websocket.AcceptOptions{
+			InsecureSkipVerify: true,
+			CompressionMode:    WebsocketCompression,
+		})
+	}
 
-			kind, data, err := utils.ParseEventBytes(dataBytes)
+	for _, gameTransport := range game.transports() {
+		go func() {
+			err := gameTransport.Serve(ctx, game.HandleConn)
 			if err != nil {
-				game.log.Errorf("err: %v\n", err)
-				continue
-			}
-
-			game.EventQueue <- Event{
-				PlayerId: playerId,
-				Kind:     kind,
-				Data:     data,
-				Conn:     wcon,
+				game.log.Errorf("err: %s\n", err.Error())
 			}
-		}
-	})
+		}()
+	}
 
 	go func() {
 		utils.WaitServerIsReady(HttpAddress)
@@ -128,6 +194,142 @@ func (game *GameServer) Start(ctx context.Context) {
 	}()
 
 	<-ctx.Done()
+
+	// the profiles that changed since the last flush are written before we exit
+	if closer, ok := game.Players.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			game.log.Errorf("err: %s\n", err.Error())
+		}
+	}
+}
+
+// transports are the game's Transport and the TCP, UDP and WebTransport transports that are
+// turned on, every player goes through the same handshake whatever the transport.
+func (game *GameServer) transports() []transport.Transport {
+	transports := []transport.Transport{game.Transport}
+
+	if TCPAddress != "" {
+		tcp, err := transport.ListenTCP(TCPAddress)
+		if err != nil {
+			game.log.Errorf("err: %s\n", err.Error())
+		} else {
+			transports = append(transports, tcp)
+		}
+	}
+
+	if UDPAddress != "" {
+		udp, err := transport.ListenUDP(UDPAddress)
+		if err != nil {
+			game.log.Errorf("err: %s\n", err.Error())
+		} else {
+			transports = append(transports, udp)
+		}
+	}
+
+	if WebTransportAddress != "" {
+		webTransport, err := game.listenWebTransport()
+		if err != nil {
+			game.log.Errorf("err: %s\n", err.Error())
+		} else {
+			transports = append(transports, webTransport)
+		}
+	}
+
+	return transports
+}
+
+// HandleConn runs a player's connection, it queues the lifecycle events of the player and every
+// event read from it until the connection is lost.
+func (game *GameServer) HandleConn(conn transport.Conn) {
+	ctx := context.Background()
+
+	playerId := game.IdGenerator.NewId()
+
+	defer func() {
+		game.EventQueue <- Event{
+			PlayerId:  playerId,
+			Kind:      flatgen.EventKindPlayerQuit,
+			Conn:      conn,
+			Data:      PlayerDisconnected{},
+			Lifecycle: true,
+		}
+
+		// game.log.Infof("Player '%v' diconnected", playerId)
+	}()
+
+	game.EventQueue <- Event{
+		PlayerId:  playerId,
+		Kind:      flatgen.EventKindPlayerHello,
+		Conn:      conn,
+		Data:      PlayerConnected{},
+		Lifecycle: true,
+	}
+
+	for {
+		dataBytes, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		receivedAt := time.Now()
+
+		// the event is handled by a later tick, the message is only valid until the next Read
+		kind, data, err := game.Events.Parse(bytes.Clone(dataBytes))
+		if err != nil {
+			game.log.Errorf("err: %v\n", err)
+			continue
+		}
+
+		game.EventQueue <- Event{
+			PlayerId:   playerId,
+			Kind:       kind,
+			Data:       data,
+			Conn:       conn,
+			ReceivedAt: receivedAt,
+		}
+	}
+}
+
+// LoadMap replaces the current world with the map found at path. If the map can't be loaded
+// the server keeps the world it already has.
+func (game *GameServer) LoadMap(path string) {
+	worldMap, err := world.Load(path)
+	if err != nil {
+		game.log.Errorf("can't load map '%s', using '%s' instead: %v", path, game.World.Name, err)
+	} else {
+		game.World = worldMap
+		game.log.Infof("Loaded map '%s' (%vx%v)", worldMap.Name, worldMap.Width, worldMap.Height)
+	}
+
+	game.FlatCache.SetWorldMap(game.World)
+}
+
+// NewPositionCodec returns the codec for the encoding a client asked for. An encoding that is
+// not one we offer is an error, the client wouldn't be able to read the positions.
+func (game *GameServer) NewPositionCodec(encoding flatgen.PositionEncoding) (PositionCodec, error) {
+	if !slices.Contains(PositionEncodings, encoding) {
+		return IntegerCodec, fmt.Errorf("position encoding %v is not offered", encoding)
+	}
+
+	// Only the fields the encoding uses are set, so players with the same encoding end
+	// up with equal codecs and share the same encoded events.
+	switch encoding {
+	case flatgen.PositionEncodingFixedPoint:
+		return PositionCodec{Encoding: encoding, Scale: float64(max(FixedPointScale, 1))}, nil
+	default:
+		return IntegerCodec, nil
+	}
+}
+
+// SetLogEnabled turns the logs of the game on or off.
+func (game *GameServer) SetLogEnabled(enabled bool) {
+	game.log.SetEnabled(enabled)
+}
+
+// ServerTime is the monotonic time since the server started, in microseconds. It's the
+// clock clients sync to with TimeSync.
+func (game *GameServer) ServerTime() int64 {
+	return time.Since(game.startTime).Microseconds()
 }
 
 func (game *GameServer) Tick() {
@@ -136,185 +338,197 @@ func (game *GameServer) Tick() {
 	ticker := time.NewTicker(1 * time.Second / time.Duration(ServerFPS))
 	previousTime, delta := time.Now(), time.Duration(0)
 
-	bufferPool := NewBuilderPool(512, 4)
-
-	playerMovedList := []*flatgen.PlayerMoved{}
-	playerJoinedList := []Player{}
-
 	<-ticker.C
 
 	for range ticker.C {
-		startTick := time.Now()
-		ctx := context.Background()
+		delta, previousTime = time.Since(previousTime), time.Now()
 
-		game.StatCollector.Tick().AddEventsReceived(len(game.EventQueue))
+		game.RunTick(context.Background(), delta)
+	}
+}
 
-		for range len(game.EventQueue) {
-			event := <-game.EventQueue
+// runTickCalls runs the calls queued for the tick until there are none left, the ones waiting
+// for room in the queue included.
+func (game *GameServer) runTickCalls() {
+	for {
+		select {
+		case call := <-game.tickCalls:
+			call()
+		default:
+			return
+		}
+	}
+}
 
-			switch event.Kind {
-			case flatgen.EventKindPlayerHello:
-				playerHello := event.Data.(PlayerHello)
+// RunTick processes the queued events, sends every player their events and moves the world
+// forward by delta.
+func (game *GameServer) RunTick(ctx context.Context, delta time.Duration) {
+	startTick := time.Now()
+	bufferPool := game.state.BufferPool
 
-				if playerHello.Id != event.PlayerId {
-					event.Conn.CloseNow()
-					game.log.Errorf("player '%s' tried to cheat", event.PlayerId)
-				}
+	game.state.Ctx = ctx
+	game.state.Start = startTick
 
-				newPlayer := PlayerWithSocket{
-					Conn: event.Conn,
-					Player: Player{
-						Id:    playerHello.Id,
-						Speed: rand.Float64()*100 + 200,
-						X:     rand.Float64()*float64(WorldWidth)/4 + float64(WorldWidth)/2,
-						Y:     rand.Float64()*float64(WorldHeight)/4 + +float64(WorldHeight)/2,
-					},
-				}
+	game.tick++
+	game.EventCollector.SetTick(game.tick, game.ServerTime())
 
-				game.Players.Set(newPlayer.Id, newPlayer)
+	game.StatCollector.Tick().AddEventsReceived(len(game.EventQueue))
 
-				// game.log.Infof("Player connected: '%v'", playerHello.Id)
+	game.recordHistory(startTick)
 
-				eventData := utils.NewFlatPlayerHello(bufferPool.GetFreeBuilder(), newPlayer.Player).Table().Bytes
+	for range len(game.EventQueue) {
+		event := <-game.EventQueue
 
-				err := newPlayer.Conn.Write(ctx, websocket.MessageBinary, eventData)
-				if err != nil {
-					game.log.Errorf("err: %s\n", err.Error())
-				}
-			case flatgen.EventKindPlayerHelloConfirm:
-				helloResponse := event.Data.(*flatgen.PlayerHelloConfirm)
+		err := game.Events.Dispatch(event)
+		if err != nil {
+			game.log.Errorf("player '%v': %v", event.PlayerId, err)
+		}
+	}
 
-				if helloResponse.Id() != int32(event.PlayerId) {
-					game.log.Debugf("player ID doesn't match expected:'%d', given:'%d'", event.PlayerId, helloResponse.Id())
-					event.Conn.CloseNow()
-				}
+	game.runTickCalls()
 
-				newPlayer, _ := game.Players.Get(event.PlayerId)
+	game.respawnPlayers(startTick)
 
-				playerJoinedList = append(playerJoinedList, newPlayer.Player)
+	// the pings go out with the frames of this tick, that's when their RTT starts
+	pinged := time.Since(game.lastPing) >= PingInterval
+	if pinged {
+		game.PingPlayers(bufferPool.GetFreeBuilder())
+	}
 
-				playerJoinedList = append(playerJoinedList, newPlayer.Player)
+	game.HandleIdlePlayers(bufferPool, startTick)
 
-				for _, otherPlayer := range game.Players.All() {
-					otherPlayerJoined := game.FlatCache.GetMutatedPlayerJoined(otherPlayer.Id, otherPlayer.Player)
+	// the spawns and joins go before the moves, so the clients know who moved
+	game.addEntityEvents(bufferPool)
 
-					flatOtherPlayerJoinedEvent := utils.NewEventHolder(flatgen.EventKindPlayerJoined, otherPlayerJoined)
-					if otherPlayer.Id != newPlayer.Id {
-						game.EventCollector.AddEvent(newPlayer.Id, flatOtherPlayerJoinedEvent)
-					}
-				}
-			case flatgen.EventKindPlayerQuit:
-				playerQuit := event.Data.(*flatgen.PlayerQuit)
+	for _, codec := range game.EventCollector.PositionCodecs() {
+		if len(game.state.PlayerJoinedList) > 0 {
+			flatPlayerJoinedList := utils.NewFlatPlayerJoinedList(bufferPool.GetFreeBuilder(), game.state.PlayerJoinedList, codec)
+			game.EventCollector.AddEncodedEvent(codec, utils.NewEventHolder(flatgen.EventKindPlayerJoinedList, flatPlayerJoinedList))
+		}
+	}
 
-				if playerQuit.Id() != int32(event.PlayerId) {
-					event.Conn.CloseNow()
-					game.log.Errorf("player '%s' tried to cheat", event.PlayerId)
-				}
+	// TODO: move this into a EventCollector
+	// calculate all players that moved event and send it.
+	game.EventCollector.AddMoves(bufferPool, game.state.PlayerMovedList)
+	game.addDatagramMoves(bufferPool)
 
-				playerQuitEvent := utils.NewEventHolder(flatgen.EventKindPlayerQuit, playerQuit)
+	// collect events here then send them.
+	for id, player := range game.Players.All() {
+		game.EventCollector.SetViewpoint(id, player.X, player.Y)
 
-				game.Players.Delete(event.PlayerId)
-				game.EventCollector.RemovePlayer(event.PlayerId)
-				game.FlatCache.RemoveJoin(event.PlayerId)
+		frame, count := game.EventCollector.GetPlayerFrame(id)
+		game.StatCollector.Tick().AddDeferredEvents(game.EventCollector.Deferred(id))
 
-				for _, player := range game.Players.All() {
-					game.EventCollector.AddEvent(player.Id, playerQuitEvent)
-				}
-			case flatgen.EventKindPlayerMoved:
-				playerMoved := event.Data.(*flatgen.PlayerMoved)
-				newPlayerInfo := playerMoved.Player(nil)
+		if count > 0 {
+			size, err := game.writeFrame(ctx, player, frame)
+
+			game.StatCollector.Tick().AddEventsSent(1)
+			game.StatCollector.Tick().AddMessageSize(size)
 
-				if newPlayerInfo.Id() != int32(event.PlayerId) {
-					event.Conn.Close(websocket.StatusNormalClosure, "Cheating!")
-					game.log.Errorf("player '%v' tried to cheat, expected id '%v' but got '%v'", event.PlayerId, event.PlayerId, newPlayerInfo.Id())
+			if err != nil {
+				game.log.Error(err.Error())
+			} else {
+				player.LastWrite = time.Now()
+				if pinged {
+					player.Latency.PingWritten(player.LastWrite)
 				}
+				game.Players.Set(id, player)
+			}
+		}
 
-				player, _ := game.Players.Get(int(newPlayerInfo.Id())) // TODO _
-				player.MovingLeft = newPlayerInfo.MovingLeft()
-				player.MovingRight = newPlayerInfo.MovingRight()
-				player.MovingUp = newPlayerInfo.MovingUp()
-				player.MovingDown = newPlayerInfo.MovingDown()
+		game.writeDatagrams(ctx, player)
 
-				playerMoved.Player(nil).MutateX(int32(player.X))
-				playerMoved.Player(nil).MutateY(int32(player.Y))
+		game.StatCollector.Tick().AddActivePlayers(1)
+		game.StatCollector.Tick().AddLatency(player.Latency)
+	}
 
-				game.Players.Set(int(newPlayerInfo.Id()), player)
-				playerMovedList = append(playerMovedList, playerMoved)
-			}
-		}
+	// TODO: Something to manage state, buffers and stuff like that.
+	game.EventCollector.Reset()
+	game.state.Reset()
+
+	game.movePlayers(delta)
+	game.moveNPCs(delta.Seconds())
+	game.Entities.Run(game.World, delta.Seconds())
+	game.moveProjectiles(delta)
+	game.captureProfiles(startTick)
+
+	game.StatCollector.Tick().AddTime(time.Since(startTick).Seconds())
+	game.StatCollector.FinishTick()
+
+	if stats := game.StatCollector.AvgStatsIfReady(); stats != nil {
+		game.log.Debugf("Tick: %06f  Avg-Events: %.3f AvgDataSentPerPlayer: %.3f KB AvgRTT: %.1f ms MaxRTT: %.1f ms Loss: %.1f%% Deferred: %.1f Compression: %.2f",
+			stats.AvgTickProcessingTime,
+			stats.AvgEventsRecvPerTick,
+			stats.AvgDataSentPerPlayer/1024,
+			stats.AvgRTT*1000,
+			stats.MaxRTT*1000,
+			stats.AvgPacketLoss*100,
+			stats.AvgDeferredEvents,
+			stats.CompressionRatio,
+		)
+		PrintMemUsage(game.log)
+		game.StatCollector.ResetFrame()
+	}
+}
 
-		// TODO: move this into a EventCollector
-		// calculate all players that moved event and send it.
-		if len(playerMovedList) > 0 {
-			flatPlayerMovedList := utils.NewFlatPlayerMovedList(bufferPool.GetFreeBuilder(), playerMovedList)
-			game.EventCollector.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerMovedList, flatPlayerMovedList))
-		}
+// movePlayers steps every player that isn't spectating or dead, in place when the store allows it.
+func (game *GameServer) movePlayers(delta time.Duration) {
+	store, mutable := game.Players.(MutablePlayerStore)
 
-		if len(playerJoinedList) > 0 {
-			flatPlayerJoinedList := utils.NewFlatPlayerJoinedList(bufferPool.GetFreeBuilder(), playerJoinedList)
-			game.EventCollector.AddGeneralEvent(utils.NewEventHolder(flatgen.EventKindPlayerJoinedList, flatPlayerJoinedList))
+	for id, player := range game.Players.All() {
+		if player.Spectator || player.State == Dead {
+			continue
 		}
 
-		// collect events here then send them.
-		for id, player := range game.Players.All() {
-			eventList, _ := game.EventCollector.GetPlayerEventList(id)
+		if mutable {
+			// a store that wraps another is only mutable when the other one is
+			if ref, ok := store.Ref(id); ok {
+				// TODO: UpdateGameState()
+				game.Movement.Step(&ref.Player, game.World, PlayerSize, delta.Seconds())
 
-			if eventList != nil {
-				game.StatCollector.Tick().AddEventsSent(1)
-				game.StatCollector.Tick().AddMessageSize(len(eventList.Table().Bytes))
-
-				err := writeTo(ctx, player, eventList.Table().Bytes)
-				if err != nil {
-					game.log.Error(err.Error())
-				}
+				continue
 			}
-
-			game.StatCollector.Tick().AddActivePlayers(1)
 		}
 
-		// TODO: Something to manage state, buffers and stuff like that.
-		game.EventCollector.Reset()
-		clear(playerMovedList)
+		game.Movement.Step(&player.Player, game.World, PlayerSize, delta.Seconds())
 
-		playerMovedList = playerMovedList[:0]
-		playerJoinedList = playerJoinedList[:0]
+		game.Players.Set(id, player)
+	}
+}
 
-		bufferPool.Reset()
+// captureProfiles notes where the players are every ProfileFlushInterval, for their profiles.
+// The players that leave are noted when they do.
+func (game *GameServer) captureProfiles(now time.Time) {
+	store, ok := game.Players.(ProfileStore)
+	if !ok || now.Sub(game.lastCapture) < ProfileFlushInterval {
+		return
+	}
 
-		delta, previousTime = time.Since(previousTime), time.Now()
+	game.lastCapture = now
+	store.CaptureProfiles()
+}
 
-		for i, player := range game.Players.All() {
-			// TODO: UpdateGameState()
-			movedDelta := delta.Seconds() * player.Speed
+// PingPlayers queues the next Ping for every player, its RTT is measured from when the frame
+// carrying it is written. Players that stopped answering are disconnected instead, the read
+// loop then queues their PlayerQuit.
+func (game *GameServer) PingPlayers(builder *flatbuffers.Builder) {
+	game.pingSeq++
+	game.lastPing = time.Now()
 
-			if player.MovingLeft && player.X-movedDelta >= 0 {
-				player.X = player.X - movedDelta
-			}
-			if player.MovingRight && player.X+movedDelta < WorldWidth {
-				player.X = player.X + movedDelta
-			}
-			if player.MovingUp && player.Y-movedDelta >= 0 {
-				player.Y = player.Y - movedDelta
-			}
-			if player.MovingDown && player.Y+movedDelta < WorldHeight {
-				player.Y = player.Y + movedDelta
-			}
+	pingEvent := utils.NewEventHolder(flatgen.EventKindPing, utils.NewFlatPing(builder, game.pingSeq))
 
-			game.Players.Set(i, player)
-		}
+	for id, player := range game.Players.All() {
+		if player.Latency.MissedInARow >= MaxMissedPongs {
+			game.log.Infof("player '%v' missed %v pongs in a row, disconnecting", id, player.Latency.MissedInARow)
+			closeConn(player.Conn)
 
-		game.StatCollector.Tick().AddTime(time.Since(startTick).Seconds())
-		game.StatCollector.FinishTick()
-
-		if stats := game.StatCollector.AvgStatsIfReady(); stats != nil {
-			game.log.Debugf("Tick: %06f  Avg-Events: %.3f AvgDataSentPerPlayer: %.3f KB",
-				stats.AvgTickProcessingTime,
-				stats.AvgEventsRecvPerTick,
-				stats.AvgDataSentPerPlayer/1024,
-			)
-			PrintMemUsage(game.log)
-			game.StatCollector.ResetFrame()
+			continue
 		}
+
+		player.Latency.PingQueued(game.pingSeq)
+		game.Players.Set(id, player)
+
+		game.Send(ToPlayer(id), pingEvent)
 	}
 }
 
@@ -325,27 +539,56 @@ func writeTo(ctx context.Context, player PlayerWithSocket, b []byte) (err error)
 		}
 	}()
 
-	return player.Conn.Write(ctx, websocket.MessageBinary, b)
+	ctx, cancel := writeContext(ctx)
+	defer cancel()
+
+	return closeOnTimeout(player, player.Conn.Write(ctx, b))
 }
 
-func (game *GameServer) NotifyAll(msg []byte) {
-	for _, player := range game.Players.All() {
-		err := player.Conn.Write(context.Background(), websocket.MessageBinary, msg)
-		if err != nil {
-			continue
-		}
+// writeContext limits a write to a player to WriteTimeout.
+func writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if WriteTimeout <= 0 {
+		return ctx, func() {}
 	}
+
+	return context.WithTimeout(ctx, WriteTimeout)
 }
 
-func (game *GameServer) NotifyAllElse(msg []byte, except ...int) {
-	for _, player := range game.Players.All() {
-		if !slices.Contains(except, player.Id) {
-			err := player.Conn.Write(context.Background(), websocket.MessageBinary, msg)
-			if err != nil {
-				continue
-			}
-		}
+// closeOnTimeout closes the player's connection when the write timed out, the read loop then
+// queues its PlayerQuit. LastWrite isn't updated for a failed write.
+func closeOnTimeout(player PlayerWithSocket, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		closeConn(player.Conn)
+
+		return fmt.Errorf("write to player '%v' timed out after %v, disconnecting", player.Id, WriteTimeout)
+	}
+
+	return err
+}
+
+// writeFrame sends the frame to the player and returns how many bytes it took. It's written in
+// its parts unless it has to be compressed.
+func (game *GameServer) writeFrame(ctx context.Context, player PlayerWithSocket, frame Frame) (int, error) {
+	if compresses(player, frame.Len()) {
+		data := game.Compress(player, frame.Bytes())
+		return len(data), writeTo(ctx, player, data)
 	}
+
+	return frame.Len(), streamTo(ctx, player, frame)
+}
+
+// streamTo writes the frame as one message, without joining its parts first.
+func streamTo(ctx context.Context, player PlayerWithSocket, frame Frame) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("lost connection with player '%v'", player.Id)
+		}
+	}()
+
+	ctx, cancel := writeContext(ctx)
+	defer cancel()
+
+	return closeOnTimeout(player, player.Conn.Write(ctx, frame.Head, frame.Shared))
 }
 
 func PrintMemUsage(log log.MeloLog) {
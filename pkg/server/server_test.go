@@ -0,0 +1,185 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func newTestGame() *server.GameServer {
+	game := server.NewGame()
+	game.RegisterHandlers()
+
+	return &game
+}
+
+func wireEvent(t testing.TB, game *server.GameServer, playerId int, data []byte) types.Event {
+	t.Helper()
+
+	kind, eventData, err := game.Events.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return types.Event{PlayerId: playerId, Kind: kind, Data: eventData}
+}
+
+func TestRunTickSurvivesMalformedEvents(t *testing.T) {
+	game := newTestGame()
+	ctx := context.Background()
+
+	game.EventQueue <- types.Event{PlayerId: 1, Kind: flatgen.EventKindPlayerHello, Data: types.PlayerConnected{}, Lifecycle: true}
+	game.RunTick(ctx, time.Millisecond)
+
+	if _, ok := game.Players.Get(1); !ok {
+		t.Fatalf("player 1 should have connected")
+	}
+
+	builder := flatbuffers.NewBuilder(64)
+	flatgen.PlayerMovedStart(builder)
+	flatgen.PlayerMovedAddKind(builder, flatgen.EventKindPlayerMoved)
+	builder.Finish(flatgen.PlayerMovedEnd(builder))
+	movedWithoutPlayer := builder.FinishedBytes()
+
+	if _, _, err := game.Events.Parse(movedWithoutPlayer); err == nil {
+		t.Errorf("a PlayerMoved without a player shouldn't pass verification")
+	}
+
+	// The root table points to a vtable way outside of the buffer
+	garbage := []byte{4, 0, 0, 0, 0xff, 0xff, 0xff, 0x7f}
+
+	events := []types.Event{
+		// Lifecycle kinds sent over the wire never reach the lifecycle handlers
+		wireEvent(t, game, 1, utils.NewFlatPlayerHello(flatbuffers.NewBuilder(64), types.Player{Id: 1}, nil, 0, nil, 0, "").Table().Bytes),
+		wireEvent(t, game, 1, utils.NewFlatPlayerQuit(flatbuffers.NewBuilder(64), 1).Table().Bytes),
+		// A lifecycle event with the wrong data
+		{PlayerId: 1, Kind: flatgen.EventKindPlayerQuit, Data: "bye", Lifecycle: true},
+		// Verification rejects these in the read loop, the handlers still have to cope with them
+		{PlayerId: 1, Kind: flatgen.EventKindPlayerMoved, Data: flatgen.GetRootAsPlayerMoved(movedWithoutPlayer, 0)},
+		wireEvent(t, game, 1, utils.NewFlatPlayerMoved(flatbuffers.NewBuilder(64), types.Player{Id: 2, InputX: 1}, types.IntegerCodec).Table().Bytes),
+		wireEvent(t, game, 99, utils.NewFlatPlayerHelloConfirm(flatbuffers.NewBuilder(64), 99, flatgen.PositionEncodingInteger, flatgen.CompressionNone, 0, "").Table().Bytes),
+		{PlayerId: 1, Kind: flatgen.EventKindPlayerMoved, Data: flatgen.GetRootAsPlayerMoved(garbage, 0)},
+		{PlayerId: 1, Kind: flatgen.EventKindPlayerMoved, Data: nil},
+		{PlayerId: 1, Kind: flatgen.EventKindPong, Data: garbage},
+		{PlayerId: 1, Kind: flatgen.EventKind(200), Data: garbage},
+	}
+
+	for _, event := range events {
+		game.EventQueue <- event
+	}
+
+	game.RunTick(ctx, time.Millisecond)
+
+	if len(game.EventQueue) != 0 {
+		t.Errorf("expected every event to be processed, %v left", len(game.EventQueue))
+	}
+
+	player, ok := game.Players.Get(1)
+	if !ok {
+		t.Fatalf("a PlayerQuit sent by the client shouldn't disconnect the player")
+	}
+
+	if player.InputX != 0 || player.InputY != 0 {
+		t.Errorf("malformed moves shouldn't change the input, got (%v, %v)", player.InputX, player.InputY)
+	}
+
+	if _, ok := game.Players.Get(99); ok {
+		t.Errorf("a hello confirm shouldn't create a player")
+	}
+
+	game.EventQueue <- types.Event{PlayerId: 1, Kind: flatgen.EventKindPlayerQuit, Data: types.PlayerDisconnected{}, Lifecycle: true}
+	game.RunTick(ctx, time.Millisecond)
+
+	if _, ok := game.Players.Get(1); ok {
+		t.Errorf("player 1 should have disconnected")
+	}
+}
+
+func TestServerOnlyDecodesClientEvents(t *testing.T) {
+	game := newTestGame()
+
+	for _, kind := range []flatgen.EventKind{flatgen.EventKindPlayerHelloConfirm, flatgen.EventKindPlayerMoved,
+		flatgen.EventKindTimeSync, flatgen.EventKindPong, flatgen.EventKindPlayerFire} {
+		if !game.Events.HasDecoder(kind) {
+			t.Errorf("the server should decode '%s'", flatgen.EnumNamesEventKind[kind])
+		}
+	}
+
+	for _, kind := range []flatgen.EventKind{flatgen.EventKindPlayerHello, flatgen.EventKindPlayerQuit,
+		flatgen.EventKindWorldMap, flatgen.EventKindPlayerMovedList, flatgen.EventKindEntitySpawned, flatgen.EventKindPing} {
+		if game.Events.HasDecoder(kind) {
+			t.Errorf("only clients get '%s', the server shouldn't decode it", flatgen.EnumNamesEventKind[kind])
+		}
+	}
+
+	worldMap := utils.NewFlatWorldMap(flatbuffers.NewBuilder(256), game.World).Table().Bytes
+	if event := wireEvent(t, game, 1, worldMap); event.Kind != flatgen.EventKindWorldMap {
+		t.Errorf("expected a WorldMap, got '%s'", flatgen.EnumNamesEventKind[event.Kind])
+	} else if _, ok := event.Data.([]byte); !ok {
+		t.Errorf("a WorldMap from a client should stay raw bytes, got %T", event.Data)
+	}
+}
+
+func TestTickStateCoalescesMoves(t *testing.T) {
+	state := server.TickState{BufferPool: server.NewBuilderPool(64, 1)}
+
+	state.AddMoved(types.Player{Id: 1, X: 1})
+	state.AddMoved(types.Player{Id: 2, X: 2})
+	state.AddMoved(types.Player{Id: 1, X: 3})
+
+	if len(state.PlayerMovedList) != 2 {
+		t.Fatalf("expected one move per player, got %v", state.PlayerMovedList)
+	}
+
+	if state.PlayerMovedList[0].X != 3 {
+		t.Errorf("expected the newest move of player 1, got %v", state.PlayerMovedList[0])
+	}
+
+	state.Reset()
+	state.AddMoved(types.Player{Id: 2, X: 4})
+
+	if len(state.PlayerMovedList) != 1 || state.PlayerMovedList[0].X != 4 {
+		t.Errorf("expected the moves to be forgotten after a reset, got %v", state.PlayerMovedList)
+	}
+}
+
+func TestUnofferedPositionEncodingsAreRejected(t *testing.T) {
+	game := newTestGame()
+
+	for _, encoding := range server.PositionEncodings {
+		if _, err := game.NewPositionCodec(encoding); err != nil {
+			t.Errorf("%v: %v", encoding, err)
+		}
+	}
+
+	// 1 and 3 were Float32 and Quantized16
+	for _, encoding := range []flatgen.PositionEncoding{1, 3, 200} {
+		if _, err := game.NewPositionCodec(encoding); err == nil {
+			t.Errorf("expected position encoding %v to be rejected", encoding)
+		}
+	}
+
+	client, conn := transport.NewPipeConns("test")
+	game.EventQueue <- types.Event{PlayerId: 1, Kind: flatgen.EventKindPlayerHello, Conn: conn, Data: types.PlayerConnected{}, Lifecycle: true}
+	game.RunTick(context.Background(), time.Millisecond)
+
+	confirm := utils.NewFlatPlayerHelloConfirm(flatbuffers.NewBuilder(64), 1, 1, flatgen.CompressionNone, 0, "")
+	event := wireEvent(t, game, 1, confirm.Table().Bytes)
+	event.Conn = conn
+	game.EventQueue <- event
+	game.RunTick(context.Background(), time.Millisecond)
+
+	if _, err := client.Read(context.Background()); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected the client that picked an encoding we don't offer to be disconnected, got %v", err)
+	}
+}
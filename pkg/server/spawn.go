@@ -0,0 +1,191 @@
+package server
+
+import (
+	"math"
+	"math/rand"
+
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+var MinPlayerSpeed = float64(200)
+var MaxPlayerSpeed = float64(300)
+
+// spawnAttempts is how many random points we try inside a zone before giving up on
+// finding a spot that is not inside a wall.
+const spawnAttempts = 16
+
+type SpawnContext struct {
+	World   *world.Map
+	Players PlayerStore
+	Rand    *rand.Rand
+}
+
+type SpawnStrategy interface {
+	SpawnPoint(sc SpawnContext, player *Player) (x, y float64)
+}
+
+// Spawner places new players in the world and gives them a speed. All the randomness
+// comes from its own seeded source so the same seed always produces the same players.
+type Spawner struct {
+	Strategy SpawnStrategy
+	MinSpeed float64
+	MaxSpeed float64
+
+	rand *rand.Rand
+}
+
+func NewSpawner(strategy SpawnStrategy, seed int64) *Spawner {
+	return &Spawner{
+		Strategy: strategy,
+		MinSpeed: MinPlayerSpeed,
+		MaxSpeed: MaxPlayerSpeed,
+		rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *Spawner) Spawn(worldMap *world.Map, players PlayerStore, player *Player) {
+	player.Speed = s.MinSpeed + s.rand.Float64()*(s.MaxSpeed-s.MinSpeed)
+	player.X, player.Y = s.Strategy.SpawnPoint(SpawnContext{World: worldMap, Players: players, Rand: s.rand}, player)
+}
+
+type Point struct {
+	X, Y float64
+}
+
+// FixedSpawn hands out the given points in order, wrapping around when it runs out.
+type FixedSpawn struct {
+	Points []Point
+
+	next int
+}
+
+func (fs *FixedSpawn) SpawnPoint(sc SpawnContext, player *Player) (float64, float64) {
+	if len(fs.Points) == 0 {
+		return sc.World.Width / 2, sc.World.Height / 2
+	}
+
+	point := fs.Points[fs.next%len(fs.Points)]
+	fs.next++
+
+	return point.X, point.Y
+}
+
+// ZoneSpawn picks a random point inside one of the map spawn zones. If Zones is empty
+// every spawn zone of the map can be used.
+type ZoneSpawn struct {
+	Zones []string
+}
+
+func (zs *ZoneSpawn) SpawnPoint(sc SpawnContext, player *Player) (float64, float64) {
+	areas := spawnAreas(sc.World, zs.Zones)
+
+	return randomPointIn(sc, areas[sc.Rand.Intn(len(areas))])
+}
+
+// FarthestSpawn samples some points from the spawn zones and keeps the one that is the
+// farthest away from every other player.
+type FarthestSpawn struct {
+	Zones      []string
+	Candidates int
+}
+
+func (fs *FarthestSpawn) SpawnPoint(sc SpawnContext, player *Player) (float64, float64) {
+	areas := spawnAreas(sc.World, fs.Zones)
+	candidates := max(fs.Candidates, 1)
+
+	bestX, bestY, bestDistance := 0.0, 0.0, -1.0
+
+	for range candidates {
+		x, y := randomPointIn(sc, areas[sc.Rand.Intn(len(areas))])
+		closest := math.Inf(1)
+
+		for id, other := range sc.Players.All() {
+			if id == player.Id {
+				continue
+			}
+
+			closest = min(closest, math.Hypot(other.X-x, other.Y-y))
+		}
+
+		if closest > bestDistance {
+			bestX, bestY, bestDistance = x, y, closest
+		}
+	}
+
+	return bestX, bestY
+}
+
+// TeamSpawn puts every team in its own spawn zone, TeamZones[i] is the zone of team i+1.
+// Players without a team are put in the team with the fewest players.
+type TeamSpawn struct {
+	TeamZones []string
+}
+
+func (ts *TeamSpawn) SpawnPoint(sc SpawnContext, player *Player) (float64, float64) {
+	if len(ts.TeamZones) == 0 {
+		return randomPointIn(sc, sc.World.Bounds())
+	}
+
+	if player.Team <= 0 || player.Team > len(ts.TeamZones) {
+		player.Team = ts.smallestTeam(sc.Players, player.Id)
+	}
+
+	return randomPointIn(sc, spawnAreas(sc.World, ts.TeamZones[player.Team-1:player.Team])[0])
+}
+
+func (ts *TeamSpawn) smallestTeam(players PlayerStore, except int) int {
+	teamSizes := make([]int, len(ts.TeamZones))
+
+	for id, other := range players.All() {
+		if id != except && other.Team > 0 && other.Team <= len(teamSizes) {
+			teamSizes[other.Team-1]++
+		}
+	}
+
+	smallest := 0
+	for team := range teamSizes {
+		if teamSizes[team] < teamSizes[smallest] {
+			smallest = team
+		}
+	}
+
+	return smallest + 1
+}
+
+// spawnAreas returns the areas of the named zones, falling back to all the map spawn zones
+// and then to the whole map, so the result is never empty.
+func spawnAreas(worldMap *world.Map, names []string) []world.Rect {
+	areas := []world.Rect{}
+
+	if len(names) == 0 {
+		for _, zone := range worldMap.SpawnZones {
+			areas = append(areas, zone.Area)
+		}
+	}
+
+	for _, name := range names {
+		if zone, ok := worldMap.SpawnZone(name); ok {
+			areas = append(areas, zone.Area)
+		}
+	}
+
+	if len(areas) == 0 {
+		areas = append(areas, worldMap.Bounds())
+	}
+
+	return areas
+}
+
+func randomPointIn(sc SpawnContext, area world.Rect) (x, y float64) {
+	for range spawnAttempts {
+		x = area.X + sc.Rand.Float64()*max(area.Width-PlayerSize, 0)
+		y = area.Y + sc.Rand.Float64()*max(area.Height-PlayerSize, 0)
+
+		if !sc.World.Blocked(x, y, PlayerSize) {
+			return x, y
+		}
+	}
+
+	return x, y
+}
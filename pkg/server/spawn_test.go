@@ -0,0 +1,108 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+func testMap() *world.Map {
+	return &world.Map{
+		Name:   "test",
+		Width:  1000,
+		Height: 1000,
+		SpawnZones: []world.Region{
+			{Name: "red", Area: world.Rect{X: 0, Y: 0, Width: 100, Height: 100}},
+			{Name: "blue", Area: world.Rect{X: 900, Y: 900, Width: 100, Height: 100}},
+		},
+	}
+}
+
+func TestSpawnerIsDeterministic(t *testing.T) {
+	worldMap := testMap()
+
+	spawnerA := server.NewSpawner(&server.ZoneSpawn{}, 42)
+	spawnerB := server.NewSpawner(&server.ZoneSpawn{}, 42)
+
+	for id := range 10 {
+		playerA, playerB := types.Player{Id: id}, types.Player{Id: id}
+
+		spawnerA.Spawn(worldMap, server.NewPlayerStore(), &playerA)
+		spawnerB.Spawn(worldMap, server.NewPlayerStore(), &playerB)
+
+		if playerA != playerB {
+			t.Fatalf("same seed gave different players: %+v %+v", playerA, playerB)
+		}
+
+		if playerA.Speed < server.MinPlayerSpeed || playerA.Speed > server.MaxPlayerSpeed {
+			t.Errorf("speed %v out of range", playerA.Speed)
+		}
+	}
+}
+
+func TestFixedSpawn(t *testing.T) {
+	spawner := server.NewSpawner(&server.FixedSpawn{Points: []server.Point{{X: 1, Y: 2}, {X: 3, Y: 4}}}, 0)
+
+	expected := []server.Point{{X: 1, Y: 2}, {X: 3, Y: 4}, {X: 1, Y: 2}}
+
+	for i := range expected {
+		player := types.Player{Id: i}
+		spawner.Spawn(testMap(), server.NewPlayerStore(), &player)
+
+		if player.X != expected[i].X || player.Y != expected[i].Y {
+			t.Errorf("spawn %d: expected %v got (%v, %v)", i, expected[i], player.X, player.Y)
+		}
+	}
+}
+
+func TestZoneSpawn(t *testing.T) {
+	worldMap := testMap()
+	spawner := server.NewSpawner(&server.ZoneSpawn{Zones: []string{"blue"}}, 7)
+
+	for id := range 20 {
+		player := types.Player{Id: id}
+		spawner.Spawn(worldMap, server.NewPlayerStore(), &player)
+
+		if player.X < 900 || player.Y < 900 {
+			t.Errorf("player spawned outside the blue zone: (%v, %v)", player.X, player.Y)
+		}
+	}
+}
+
+func TestFarthestSpawn(t *testing.T) {
+	worldMap := testMap()
+	players := server.NewPlayerStore()
+	players.Set(1, types.PlayerWithSocket{Player: types.Player{Id: 1, X: 50, Y: 50}})
+
+	spawner := server.NewSpawner(&server.FarthestSpawn{Candidates: 16}, 3)
+
+	player := types.Player{Id: 2}
+	spawner.Spawn(worldMap, players, &player)
+
+	if player.X < 900 || player.Y < 900 {
+		t.Errorf("expected the player to spawn in the empty zone, got (%v, %v)", player.X, player.Y)
+	}
+}
+
+func TestTeamSpawn(t *testing.T) {
+	worldMap := testMap()
+	players := server.NewPlayerStore()
+	spawner := server.NewSpawner(&server.TeamSpawn{TeamZones: []string{"red", "blue"}}, 5)
+
+	for id := range 4 {
+		player := types.Player{Id: id}
+		spawner.Spawn(worldMap, players, &player)
+		players.Set(id, types.PlayerWithSocket{Player: player})
+
+		expectedTeam := id%2 + 1
+		if player.Team != expectedTeam {
+			t.Errorf("player %d: expected team %d, got %d", id, expectedTeam, player.Team)
+		}
+
+		if player.Team == 2 && (player.X < 900 || player.Y < 900) {
+			t.Errorf("player %d of team blue spawned at (%v, %v)", id, player.X, player.Y)
+		}
+	}
+}
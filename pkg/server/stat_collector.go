@@ -1,5 +1,9 @@
 package server
 
+import (
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+)
+
 type StatCollector struct {
 	tickBuilder *TickStatBuilder
 
@@ -40,6 +44,13 @@ func (sc *StatCollector) AvgStatsIfReady() *AvgStats {
 			avgStats.AvgTickProcessingTime += sc.tickStatList[i].ProcessingTime
 			avgStats.AvgMessageSize += sc.tickStatList[i].AvgMessageSize
 			avgStats.MaxMessageSize = max(avgStats.MaxMessageSize, sc.tickStatList[i].MaxMessageSize)
+			avgStats.AvgRTT += sc.tickStatList[i].AvgRTT
+			avgStats.AvgJitter += sc.tickStatList[i].AvgJitter
+			avgStats.AvgPacketLoss += sc.tickStatList[i].AvgPacketLoss
+			avgStats.MaxRTT = max(avgStats.MaxRTT, sc.tickStatList[i].MaxRTT)
+			avgStats.AvgDeferredEvents += sc.tickStatList[i].DeferredEvents
+			avgStats.UncompressedData += sc.tickStatList[i].UncompressedData
+			avgStats.CompressedData += sc.tickStatList[i].CompressedData
 		}
 
 		n := float64(len(sc.tickStatList))
@@ -50,6 +61,14 @@ func (sc *StatCollector) AvgStatsIfReady() *AvgStats {
 		avgStats.AvgEventsRecvPerTick /= n
 		avgStats.AvgTickProcessingTime /= n
 		avgStats.AvgMessageSize /= n
+		avgStats.AvgRTT /= n
+		avgStats.AvgJitter /= n
+		avgStats.AvgPacketLoss /= n
+		avgStats.AvgDeferredEvents /= n
+
+		if avgStats.UncompressedData > 0 {
+			avgStats.CompressionRatio = avgStats.CompressedData / avgStats.UncompressedData
+		}
 
 		return avgStats
 	}
@@ -71,6 +90,17 @@ type TickStatBuilder struct {
 	activePlayers       int
 
 	maxMessageSize int
+
+	latencySamples int
+	totalRTT       float64
+	totalJitter    float64
+	totalLoss      float64
+	maxRTT         float64
+
+	deferredEvents int
+
+	uncompressedData int
+	compressedData   int
 }
 
 func (tsb *TickStatBuilder) AddEventsReceived(count int) {
@@ -87,6 +117,18 @@ func (tsb *TickStatBuilder) AddMessageSize(size int) {
 	tsb.maxMessageSize = max(tsb.maxMessageSize, size)
 }
 
+// AddDeferredEvents adds the events of a player that didn't fit in its budget this tick.
+func (tsb *TickStatBuilder) AddDeferredEvents(count int) {
+	tsb.deferredEvents += count
+}
+
+// AddCompression adds a frame that went through the application compression, its size before
+// and after it.
+func (tsb *TickStatBuilder) AddCompression(uncompressed, compressed int) {
+	tsb.uncompressedData += uncompressed
+	tsb.compressedData += compressed
+}
+
 func (tsb *TickStatBuilder) AddTime(seconds float64) {
 	tsb.processTime += seconds
 }
@@ -95,6 +137,20 @@ func (tsb *TickStatBuilder) AddActivePlayers(count int) {
 	tsb.activePlayers += count
 }
 
+// AddLatency adds the connection quality of one player, players that never answered a
+// ping are skipped.
+func (tsb *TickStatBuilder) AddLatency(latency Latency) {
+	if latency.LastPong.IsZero() {
+		return
+	}
+
+	tsb.latencySamples++
+	tsb.totalRTT += latency.RTT.Seconds()
+	tsb.totalJitter += latency.Jitter.Seconds()
+	tsb.totalLoss += latency.PacketLoss()
+	tsb.maxRTT = max(tsb.maxRTT, latency.RTT.Seconds())
+}
+
 func (tsb *TickStatBuilder) AvgTickStat() TickStats {
 	return TickStats{
 		ProcessingTime:    tsb.processTime,
@@ -105,6 +161,13 @@ func (tsb *TickStatBuilder) AvgTickStat() TickStats {
 		EventsSent:        float64(tsb.eventsSentCount),
 		MaxMessageSize:    float64(tsb.maxMessageSize),
 		ActivePlayers:     float64(tsb.activePlayers),
+		AvgRTT:            tsb.totalRTT / float64(max(tsb.latencySamples, 1)),
+		AvgJitter:         tsb.totalJitter / float64(max(tsb.latencySamples, 1)),
+		AvgPacketLoss:     tsb.totalLoss / float64(max(tsb.latencySamples, 1)),
+		MaxRTT:            tsb.maxRTT,
+		DeferredEvents:    float64(tsb.deferredEvents),
+		UncompressedData:  float64(tsb.uncompressedData),
+		CompressedData:    float64(tsb.compressedData),
 	}
 }
 
@@ -115,6 +178,14 @@ func (tsb *TickStatBuilder) Reset() {
 	tsb.processTime = 0
 	tsb.activePlayers = 0
 	tsb.maxMessageSize = 0
+	tsb.latencySamples = 0
+	tsb.totalRTT = 0
+	tsb.totalJitter = 0
+	tsb.totalLoss = 0
+	tsb.maxRTT = 0
+	tsb.deferredEvents = 0
+	tsb.uncompressedData = 0
+	tsb.compressedData = 0
 }
 
 type AvgStats struct {
@@ -125,6 +196,18 @@ type AvgStats struct {
 	AvgActivePlayers      float64
 	MaxMessageSize        float64
 	AvgMessageSize        float64
+	// Latency stats are in seconds
+	AvgRTT        float64
+	MaxRTT        float64
+	AvgJitter     float64
+	AvgPacketLoss float64
+	// AvgDeferredEvents is how many events didn't fit in the budgets of the players per tick
+	AvgDeferredEvents float64
+	// UncompressedData and CompressedData are the bytes of the frames that went through the
+	// application compression, CompressionRatio is compressed / uncompressed.
+	UncompressedData float64
+	CompressedData   float64
+	CompressionRatio float64
 }
 
 type TickStats struct {
@@ -136,4 +219,11 @@ type TickStats struct {
 	MaxMessageSize    float64
 	TotalDataSent     float64
 	ActivePlayers     float64
+	AvgRTT            float64
+	MaxRTT            float64
+	AvgJitter         float64
+	AvgPacketLoss     float64
+	DeferredEvents    float64
+	UncompressedData  float64
+	CompressedData    float64
 }
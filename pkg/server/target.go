@@ -0,0 +1,97 @@
+package server
+
+import (
+	"slices"
+
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+type targetKind int
+
+const (
+	targetOne targetKind = iota
+	targetAll
+	targetAllExcept
+	targetRadius
+	targetTeam
+	targetRoom
+)
+
+// Target picks the players an event is sent to, see GameServer.Send.
+type Target struct {
+	kind targetKind
+
+	ids          []int
+	x, y, radius float64
+	team         int
+	room         string
+}
+
+func ToPlayer(id int) Target {
+	return Target{kind: targetOne, ids: []int{id}}
+}
+
+func ToAll() Target {
+	return Target{kind: targetAll}
+}
+
+func ToAllExcept(ids ...int) Target {
+	return Target{kind: targetAllExcept, ids: ids}
+}
+
+// ToRadius targets the players at most radius away from (x, y).
+func ToRadius(x, y, radius float64) Target {
+	return Target{kind: targetRadius, x: x, y: y, radius: radius}
+}
+
+func ToTeam(team int) Target {
+	return Target{kind: targetTeam, team: team}
+}
+
+// ToRoom targets the players standing in the map region with the given name.
+func ToRoom(room string) Target {
+	return Target{kind: targetRoom, room: room}
+}
+
+// Includes tells if the player is targeted. Spectators are not in the world, so they are
+// never in a radius or a room.
+func (t Target) Includes(player PlayerWithSocket, worldMap *world.Map) bool {
+	switch t.kind {
+	case targetOne:
+		return player.Id == t.ids[0]
+	case targetAll:
+		return true
+	case targetAllExcept:
+		return !slices.Contains(t.ids, player.Id)
+	case targetRadius:
+		dx, dy := player.X-t.x, player.Y-t.y
+
+		return !player.Spectator && dx*dx+dy*dy <= t.radius*t.radius
+	case targetTeam:
+		return player.Team == t.team
+	case targetRoom:
+		region, ok := worldMap.RegionAt(player.X, player.Y)
+
+		return !player.Spectator && ok && region.Name == t.room
+	default:
+		return false
+	}
+}
+
+// Send queues the event for the targeted players, it's sent with the rest of their events at
+// the end of the tick. An event sent to everyone is collected once and shared by all of them.
+func (game *GameServer) Send(target Target, event EventHolder) {
+	switch target.kind {
+	case targetOne:
+		game.EventCollector.AddEvent(target.ids[0], event)
+	case targetAll:
+		game.EventCollector.AddGeneralEvent(event)
+	default:
+		for id, player := range game.Players.All() {
+			if target.Includes(player, game.World) {
+				game.EventCollector.AddEvent(id, event)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/server"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func TestSendTargets(t *testing.T) {
+	game := newTestGame()
+	game.World = &world.Map{
+		Width:   1000,
+		Height:  1000,
+		Regions: []world.Region{{Name: "arena", Area: world.Rect{X: 0, Y: 0, Width: 100, Height: 100}}},
+	}
+
+	players := []types.Player{
+		{Id: 1, X: 10, Y: 10, Team: 1},
+		{Id: 2, X: 50, Y: 50, Team: 2},
+		{Id: 3, X: 500, Y: 500, Team: 1},
+	}
+
+	for _, player := range players {
+		game.Players.Set(player.Id, types.PlayerWithSocket{Player: player})
+		game.EventCollector.AddPlayer(player.Id)
+	}
+
+	// A spectator standing in the arena, it's not in the world
+	game.Players.Set(4, types.PlayerWithSocket{Player: types.Player{Id: 4, X: 20, Y: 20, Team: 2}, Spectator: true})
+	game.EventCollector.AddPlayer(4)
+
+	testCases := []struct {
+		name     string
+		target   server.Target
+		expected []int
+	}{
+		{"one", server.ToPlayer(2), []int{2}},
+		{"all", server.ToAll(), []int{1, 2, 3, 4}},
+		{"all except", server.ToAllExcept(1, 3), []int{2, 4}},
+		{"radius", server.ToRadius(0, 0, 80), []int{1, 2}},
+		{"team", server.ToTeam(2), []int{2, 4}},
+		{"room", server.ToRoom("arena"), []int{1, 2}},
+		{"empty room", server.ToRoom("nowhere"), []int{}},
+	}
+
+	for _, tc := range testCases {
+		game.EventCollector.Reset()
+
+		game.Send(tc.target, utils.NewEventHolder(flatgen.EventKindPing, utils.NewFlatPing(flatbuffers.NewBuilder(32), 1)))
+
+		received := []int{}
+		for id := 1; id <= 4; id++ {
+			if _, count := game.EventCollector.GetPlayerEventList(id); count > 0 {
+				received = append(received, id)
+			}
+		}
+
+		if len(received) != len(tc.expected) {
+			t.Errorf("%s: expected %v to receive the event, got %v", tc.name, tc.expected, received)
+			continue
+		}
+
+		for i := range received {
+			if received[i] != tc.expected[i] {
+				t.Errorf("%s: expected %v to receive the event, got %v", tc.name, tc.expected, received)
+				break
+			}
+		}
+	}
+}
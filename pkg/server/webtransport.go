@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+)
+
+// WebTransportAddress is the UDP address WebTransport is served on, with a self signed
+// certificate. Players connected over it get the moves in datagrams, so a lost packet doesn't
+// hold back the rest. An empty address turns it off and the clients use the websocket.
+var WebTransportAddress = "127.0.0.1:6972"
+
+// WebTransportInfo is what clients need to connect over WebTransport.
+type WebTransportInfo struct {
+	URL string `json:"url"`
+	// CertificateHash is the SHA-256 hash of the self signed certificate, browsers trust it with
+	// the serverCertificateHashes option
+	CertificateHash []byte `json:"certificateHash"`
+}
+
+func (game *GameServer) listenWebTransport() (transport.Transport, error) {
+	host, _, err := net.SplitHostPort(WebTransportAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, hash, err := transport.SelfSignedTLS(host, "localhost")
+	if err != nil {
+		return nil, err
+	}
+
+	webTransport, err := transport.ListenWebTransport(WebTransportAddress, "/webtransport", tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	game.webTransport = &WebTransportInfo{
+		URL:             "https://" + webTransport.Addr().String() + "/webtransport",
+		CertificateHash: hash,
+	}
+
+	return webTransport, nil
+}
+
+// ServeWebTransportInfo tells clients where WebTransport is served, it's not found when it's
+// off and they fall back to the websocket.
+func (game *GameServer) ServeWebTransportInfo(w http.ResponseWriter, r *http.Request) {
+	if game.webTransport == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(game.webTransport)
+	if err != nil {
+		game.log.Errorf("err: %s\n", err.Error())
+	}
+}
+
+// addDatagramMoves gives the EventCollector the players sent in datagrams this tick, the ones
+// that moved in the last DatagramRepeats ticks as they are now, and the ones that stopped being
+// sent, which go in the EventLists.
+func (game *GameServer) addDatagramMoves(bufferPool *BuilderPool) {
+	if game.recentMoves == nil {
+		game.recentMoves = map[int]uint32{}
+	}
+
+	for _, player := range game.state.PlayerMovedList {
+		game.recentMoves[player.Id] = game.tick
+	}
+
+	game.datagramMoves = game.datagramMoves[:0]
+	game.settledMoves = game.settledMoves[:0]
+
+	for id, tick := range game.recentMoves {
+		settled := game.tick-tick >= uint32(DatagramRepeats)
+		if settled {
+			delete(game.recentMoves, id)
+		}
+
+		if !game.EventCollector.HasDatagramPlayers() {
+			continue
+		}
+
+		player, ok := game.mover(id)
+		if !ok {
+			continue
+		}
+
+		if settled {
+			game.settledMoves = append(game.settledMoves, player)
+		} else {
+			game.datagramMoves = append(game.datagramMoves, player)
+		}
+	}
+
+	game.EventCollector.AddDatagramMoves(game.datagramMoves)
+	game.EventCollector.AddSettledMoves(bufferPool, game.settledMoves)
+}
+
+// writeDatagrams sends the player its datagrams of the tick.
+func (game *GameServer) writeDatagrams(ctx context.Context, player PlayerWithSocket) {
+	conn, ok := player.Conn.(transport.DatagramConn)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := writeContext(ctx)
+	defer cancel()
+
+	for _, datagram := range game.EventCollector.GetPlayerDatagrams(player.Id) {
+		if err := closeOnTimeout(player, conn.WriteDatagram(ctx, datagram)); err != nil {
+			game.log.Error(err.Error())
+			return
+		}
+
+		game.StatCollector.Tick().AddEventsSent(1)
+		game.StatCollector.Tick().AddMessageSize(len(datagram))
+	}
+}
@@ -0,0 +1,63 @@
+package timesync
+
+import "time"
+
+// Clock estimates the server clock and the round trip time from TimeSync replies.
+type Clock struct {
+	// Smoothing is how much a new sample moves the estimates, in (0, 1].
+	Smoothing float64
+
+	start   time.Time
+	rtt     time.Duration
+	offset  time.Duration
+	samples int
+}
+
+func NewClock() *Clock {
+	return &Clock{
+		Smoothing: 0.1,
+		start:     time.Now(),
+	}
+}
+
+// LocalTime is the value clients put in TimeSync.ClientTime, in nanoseconds since the
+// clock was created.
+func (c *Clock) LocalTime(now time.Time) float64 {
+	return float64(now.Sub(c.start))
+}
+
+// AddSample uses a TimeSync reply to update the estimates. sentAt is the ClientTime that
+// was echoed back by the server, serverTime is the server time from the reply.
+func (c *Clock) AddSample(sentAt float64, receivedAt time.Time, serverTime time.Duration) {
+	received := receivedAt.Sub(c.start)
+	rtt := received - time.Duration(sentAt)
+
+	if rtt < 0 {
+		return
+	}
+
+	// The server answered somewhere in the middle of the round trip.
+	offset := serverTime + rtt/2 - received
+
+	if c.samples == 0 {
+		c.rtt, c.offset = rtt, offset
+	} else {
+		c.rtt += time.Duration(c.Smoothing * float64(rtt-c.rtt))
+		c.offset += time.Duration(c.Smoothing * float64(offset-c.offset))
+	}
+
+	c.samples++
+}
+
+func (c *Clock) Synced() bool {
+	return c.samples > 0
+}
+
+func (c *Clock) RTT() time.Duration {
+	return c.rtt
+}
+
+// ServerNow is the estimated server time at the given local time.
+func (c *Clock) ServerNow(now time.Time) time.Duration {
+	return now.Sub(c.start) + c.offset
+}
@@ -0,0 +1,77 @@
+package timesync
+
+import "time"
+
+type Snapshot struct {
+	ServerTime time.Duration
+	X, Y       float64
+}
+
+// InterpolationBuffer keeps the last positions of every entity and renders them Delay
+// behind the server, so there is almost always a snapshot on each side of the render time.
+type InterpolationBuffer struct {
+	Delay time.Duration
+
+	snapshots map[int][]Snapshot
+}
+
+// maxSnapshots bounds the buffer when positions are never read.
+const maxSnapshots = 32
+
+func NewInterpolationBuffer(delay time.Duration) *InterpolationBuffer {
+	return &InterpolationBuffer{
+		Delay:     delay,
+		snapshots: map[int][]Snapshot{},
+	}
+}
+
+func (ib *InterpolationBuffer) Add(id int, serverTime time.Duration, x, y float64) {
+	snapshots := ib.snapshots[id]
+
+	// Snapshots from an older tick can arrive late, they are useless now.
+	if len(snapshots) > 0 && snapshots[len(snapshots)-1].ServerTime >= serverTime {
+		return
+	}
+
+	if len(snapshots) == maxSnapshots {
+		snapshots = append(snapshots[:0], snapshots[1:]...)
+	}
+
+	ib.snapshots[id] = append(snapshots, Snapshot{ServerTime: serverTime, X: x, Y: y})
+}
+
+func (ib *InterpolationBuffer) Remove(id int) {
+	delete(ib.snapshots, id)
+}
+
+// Position returns where the entity should be drawn when the server clock is at serverNow.
+func (ib *InterpolationBuffer) Position(id int, serverNow time.Duration) (float64, float64, bool) {
+	snapshots := ib.snapshots[id]
+	if len(snapshots) == 0 {
+		return 0, 0, false
+	}
+
+	renderTime := serverNow - ib.Delay
+
+	if renderTime <= snapshots[0].ServerTime {
+		return snapshots[0].X, snapshots[0].Y, true
+	}
+
+	for i := 1; i < len(snapshots); i++ {
+		from, to := snapshots[i-1], snapshots[i]
+
+		if renderTime <= to.ServerTime {
+			// Everything before 'from' won't be needed again.
+			ib.snapshots[id] = snapshots[i-1:]
+
+			t := float64(renderTime-from.ServerTime) / float64(to.ServerTime-from.ServerTime)
+
+			return from.X + (to.X-from.X)*t, from.Y + (to.Y-from.Y)*t, true
+		}
+	}
+
+	last := snapshots[len(snapshots)-1]
+	ib.snapshots[id] = snapshots[len(snapshots)-1:]
+
+	return last.X, last.Y, true
+}
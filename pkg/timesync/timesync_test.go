@@ -0,0 +1,56 @@
+package timesync_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/timesync"
+)
+
+func TestClock(t *testing.T) {
+	clock := timesync.NewClock()
+
+	now := time.Now()
+	sentAt := clock.LocalTime(now)
+
+	// The server is 10s ahead of us and answers after 20ms, the reply takes 20ms more.
+	serverTime := time.Duration(sentAt) + 10*time.Second + 20*time.Millisecond
+	receivedAt := now.Add(40 * time.Millisecond)
+
+	clock.AddSample(sentAt, receivedAt, serverTime)
+
+	if !clock.Synced() {
+		t.Fatalf("expected the clock to be synced")
+	}
+
+	if clock.RTT() != 40*time.Millisecond {
+		t.Errorf("expected 40ms RTT, got %v", clock.RTT())
+	}
+
+	expected := time.Duration(clock.LocalTime(receivedAt)) + 10*time.Second
+	if diff := clock.ServerNow(receivedAt) - expected; diff < -time.Millisecond || diff > time.Millisecond {
+		t.Errorf("server time is off by %v", diff)
+	}
+}
+
+func TestInterpolationBuffer(t *testing.T) {
+	buffer := timesync.NewInterpolationBuffer(100 * time.Millisecond)
+
+	buffer.Add(1, 1000*time.Millisecond, 0, 0)
+	buffer.Add(1, 1100*time.Millisecond, 10, 20)
+	buffer.Add(1, 1050*time.Millisecond, 99, 99) // late, ignored
+
+	x, y, ok := buffer.Position(1, 1150*time.Millisecond)
+	if !ok || x != 5 || y != 10 {
+		t.Errorf("expected (5, 10), got (%v, %v, %v)", x, y, ok)
+	}
+
+	x, y, _ = buffer.Position(1, 2000*time.Millisecond)
+	if x != 10 || y != 20 {
+		t.Errorf("expected the last snapshot, got (%v, %v)", x, y)
+	}
+
+	if _, _, ok := buffer.Position(2, time.Second); ok {
+		t.Errorf("expected no position for an unknown entity")
+	}
+}
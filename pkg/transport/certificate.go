@@ -0,0 +1,84 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+)
+
+// CertificateValidity is how long a self signed certificate is valid, browsers only accept
+// the ones valid for at most 14 days by their hash.
+var CertificateValidity = 10 * 24 * time.Hour
+
+// SelfSignedTLS makes a TLS config with a new self signed certificate for the hosts, for
+// serving WebTransport on a machine without a real certificate. It returns the SHA-256 hash
+// of the certificate too, clients trust it with VerifyCertificateHash or, in a browser, with
+// the serverCertificateHashes option of WebTransport.
+func SelfSignedTLS(hosts ...string) (*tls.Config, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "multiplayer-game"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(CertificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hash := sha256.Sum256(certificate)
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{certificate}, PrivateKey: key}},
+	}, hash[:], nil
+}
+
+// VerifyCertificateHash makes a client TLS config that only trusts the certificate with the
+// SHA-256 hash, whatever signed it.
+func VerifyCertificateHash(hash []byte) *tls.Config {
+	return &tls.Config{
+		// the certificate is checked by its hash below instead
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(certificates [][]byte, _ [][]*x509.Certificate) error {
+			if len(certificates) > 0 {
+				leaf := sha256.Sum256(certificates[0])
+				if bytes.Equal(leaf[:], hash) {
+					return nil
+				}
+			}
+
+			return errors.New("the certificate doesn't have the expected hash")
+		},
+	}
+}
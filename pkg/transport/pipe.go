@@ -0,0 +1,134 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// PipeBuffer is how many messages a pipe connection holds before its writes block.
+var PipeBuffer = 256
+
+// pipe is what the two ends of a pipe connection share.
+type pipe struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func (p *pipe) close() {
+	p.once.Do(func() { close(p.done) })
+}
+
+type pipeConn struct {
+	pipe       *pipe
+	in         chan []byte
+	out        chan []byte
+	remoteAddr string
+}
+
+// NewPipeConns returns the two ends of an in memory connection, what's written in one is
+// read from the other. Closing one end closes both.
+func NewPipeConns(remoteAddr string) (Conn, Conn) {
+	p := &pipe{done: make(chan struct{})}
+	aToB, bToA := make(chan []byte, PipeBuffer), make(chan []byte, PipeBuffer)
+
+	a := &pipeConn{pipe: p, in: bToA, out: aToB, remoteAddr: remoteAddr}
+	b := &pipeConn{pipe: p, in: aToB, out: bToA, remoteAddr: remoteAddr}
+
+	return a, b
+}
+
+func (pc *pipeConn) Read(ctx context.Context) ([]byte, error) {
+	// the messages written before the pipe was closed are lost, like on a real connection
+	select {
+	case <-pc.pipe.done:
+		return nil, net.ErrClosed
+	default:
+	}
+
+	select {
+	case message := <-pc.in:
+		return message, nil
+	case <-pc.pipe.done:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (pc *pipeConn) Write(ctx context.Context, parts ...[]byte) error {
+	select {
+	case <-pc.pipe.done:
+		return net.ErrClosed
+	default:
+	}
+
+	size := 0
+	for _, part := range parts {
+		size += len(part)
+	}
+
+	message := make([]byte, 0, size)
+	for _, part := range parts {
+		message = append(message, part...)
+	}
+
+	select {
+	case pc.out <- message:
+		return nil
+	case <-pc.pipe.done:
+		return net.ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pc *pipeConn) Close() error {
+	pc.pipe.close()
+	return nil
+}
+
+func (pc *pipeConn) RemoteAddr() string {
+	return pc.remoteAddr
+}
+
+// Pipe is a Transport of in memory connections, clients connect with Dial. It's meant for
+// tests and simulations that run the server and its clients in one process.
+type Pipe struct {
+	conns chan Conn
+	dials atomic.Int64
+}
+
+func NewPipe() *Pipe {
+	return &Pipe{conns: make(chan Conn)}
+}
+
+// Dial connects to the Pipe and returns the client's end of the connection. It waits until
+// the Pipe is served.
+func (p *Pipe) Dial(ctx context.Context) (Conn, error) {
+	client, server := NewPipeConns(fmt.Sprintf("pipe-%d", p.dials.Add(1)))
+
+	select {
+	case p.conns <- server:
+		return client, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *Pipe) Serve(ctx context.Context, handle func(Conn)) error {
+	for {
+		select {
+		case conn := <-p.conns:
+			go func() {
+				defer conn.Close()
+
+				handle(conn)
+			}()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
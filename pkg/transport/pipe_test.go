@@ -0,0 +1,126 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+)
+
+func TestPipeConnsCarryMessages(t *testing.T) {
+	ctx := context.Background()
+	a, b := transport.NewPipeConns("test")
+
+	part := []byte("hello ")
+	if err := a.Write(ctx, part, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the parts can be reused once Write returns
+	copy(part, "xxxxxx")
+
+	if err := b.Write(ctx, []byte("back")); err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := b.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(message) != "hello world" {
+		t.Fatalf("expected 'hello world', got '%s'", message)
+	}
+
+	message, err = a.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(message) != "back" {
+		t.Fatalf("expected 'back', got '%s'", message)
+	}
+
+	if a.RemoteAddr() != "test" {
+		t.Fatalf("expected the remote address 'test', got '%s'", a.RemoteAddr())
+	}
+}
+
+func TestPipeConnsClose(t *testing.T) {
+	ctx := context.Background()
+	a, b := transport.NewPipeConns("test")
+
+	read := make(chan error)
+	go func() {
+		_, err := b.Read(ctx)
+		read <- err
+	}()
+
+	a.Close()
+
+	select {
+	case err := <-read:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Fatalf("expected net.ErrClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("closing one end didn't stop the read of the other")
+	}
+
+	if err := b.Write(ctx, []byte("late")); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected net.ErrClosed, got %v", err)
+	}
+}
+
+func TestPipeConnsReadCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, b := transport.NewPipeConns("test")
+
+	if _, err := b.Read(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline, got %v", err)
+	}
+}
+
+func TestPipeServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := transport.NewPipe()
+
+	go pipe.Serve(ctx, func(conn transport.Conn) {
+		message, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		conn.Write(ctx, []byte("echo: "), message)
+	})
+
+	client, err := pipe.Dial(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Write(ctx, []byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := client.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(message) != "echo: ping" {
+		t.Fatalf("expected 'echo: ping', got '%s'", message)
+	}
+
+	// the connection is closed once the handler returns
+	if _, err := client.Read(ctx); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected net.ErrClosed, got %v", err)
+	}
+}
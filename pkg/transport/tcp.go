@@ -0,0 +1,200 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// sizePrefix is the length of the size in front of every message of a stream, the same
+// prefix flatbuffers writes with FinishSizePrefixed.
+const sizePrefix = 4
+
+// stream is a byte stream the messages are sent over, like a TCP connection or a QUIC stream.
+type stream interface {
+	io.ReadWriteCloser
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+}
+
+// streamConn sends every message with its size in front as a little endian uint32.
+type streamConn struct {
+	stream     stream
+	remoteAddr string
+	reader     *bufio.Reader
+	buf        []byte
+	header     [sizePrefix]byte
+
+	// writeMu keeps the deadline of a write from cutting another one
+	writeMu sync.Mutex
+}
+
+func newStreamConn(stream stream, remoteAddr string) *streamConn {
+	return &streamConn{stream: stream, remoteAddr: remoteAddr, reader: bufio.NewReader(stream)}
+}
+
+// NewTCPConn wraps a stream connection, every message is sent with its size in front as a
+// little endian uint32.
+func NewTCPConn(conn net.Conn) Conn {
+	return newStreamConn(conn, conn.RemoteAddr().String())
+}
+
+// DialTCP connects to a TCP transport.
+func DialTCP(ctx context.Context, address string) (Conn, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetNoDelay(true)
+	}
+
+	return NewTCPConn(conn), nil
+}
+
+// withDeadline makes the reads or writes of the connection fail once ctx is done.
+func withDeadline(ctx context.Context, setDeadline func(time.Time) error) (stop func() bool) {
+	if deadline, ok := ctx.Deadline(); ok {
+		setDeadline(deadline)
+	} else {
+		setDeadline(time.Time{})
+	}
+
+	return context.AfterFunc(ctx, func() { setDeadline(time.Now()) })
+}
+
+func (sc *streamConn) Read(ctx context.Context) ([]byte, error) {
+	defer withDeadline(ctx, sc.stream.SetReadDeadline)()
+
+	if _, err := readFull(ctx, sc.reader, sc.header[:]); err != nil {
+		return nil, err
+	}
+
+	size := int(binary.LittleEndian.Uint32(sc.header[:]))
+	if size > MaxMessageSize {
+		sc.stream.Close()
+		return nil, fmt.Errorf("message of %d bytes is bigger than %d", size, MaxMessageSize)
+	}
+
+	if cap(sc.buf) < size {
+		sc.buf = make([]byte, size)
+	}
+
+	sc.buf = sc.buf[:size]
+
+	if _, err := readFull(ctx, sc.reader, sc.buf); err != nil {
+		return nil, err
+	}
+
+	return sc.buf, nil
+}
+
+// readFull is io.ReadFull that reports the context's error instead of the deadline's.
+func readFull(ctx context.Context, reader io.Reader, buf []byte) (int, error) {
+	n, err := io.ReadFull(reader, buf)
+	// the deadlines only come from ctx, it's done or about to be
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		<-ctx.Done()
+
+		return n, ctx.Err()
+	}
+
+	return n, err
+}
+
+func (sc *streamConn) Write(ctx context.Context, parts ...[]byte) error {
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+
+	defer withDeadline(ctx, sc.stream.SetWriteDeadline)()
+
+	size := 0
+	for _, part := range parts {
+		size += len(part)
+	}
+
+	if size > MaxMessageSize {
+		return fmt.Errorf("message of %d bytes is bigger than %d", size, MaxMessageSize)
+	}
+
+	header := make([]byte, sizePrefix)
+	binary.LittleEndian.PutUint32(header, uint32(size))
+
+	// net.Buffers writes the header and the parts without joining them, with one call on TCP
+	buffers := net.Buffers{header}
+	for _, part := range parts {
+		if len(part) > 0 {
+			buffers = append(buffers, part)
+		}
+	}
+
+	_, err := buffers.WriteTo(sc.stream)
+
+	return err
+}
+
+func (sc *streamConn) Close() error {
+	return sc.stream.Close()
+}
+
+func (sc *streamConn) RemoteAddr() string {
+	return sc.remoteAddr
+}
+
+// TCP accepts connections whose messages are prefixed by their size, for clients that don't
+// need the websocket framing.
+type TCP struct {
+	listener net.Listener
+}
+
+// ListenTCP starts listening on the address, connections are accepted once it's served.
+func ListenTCP(address string) (*TCP, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen on '%s': %w", address, err)
+	}
+
+	return &TCP{listener: listener}, nil
+}
+
+// Addr is the address the transport listens on.
+func (t *TCP) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+func (t *TCP) Serve(ctx context.Context, handle func(Conn)) error {
+	stop := context.AfterFunc(ctx, func() { t.listener.Close() })
+	defer stop()
+
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			tcp.SetNoDelay(true)
+		}
+
+		go func() {
+			tcpConn := NewTCPConn(conn)
+			defer tcpConn.Close()
+
+			handle(tcpConn)
+		}()
+	}
+}
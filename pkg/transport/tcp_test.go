@@ -0,0 +1,163 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func serveTCP(t *testing.T, ctx context.Context, handle func(transport.Conn)) string {
+	t.Helper()
+
+	tcp, err := transport.ListenTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go tcp.Serve(ctx, handle)
+
+	return tcp.Addr().String()
+}
+
+func echo(ctx context.Context) func(transport.Conn) {
+	return func(conn transport.Conn) {
+		for {
+			message, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+
+			if string(message) == "bye" {
+				return
+			}
+
+			if err := conn.Write(ctx, []byte("echo: "), message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestTCP(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := transport.DialTCP(ctx, serveTCP(t, ctx, echo(ctx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, text := range []string{"ping", "", "pong"} {
+		if err := client.Write(ctx, []byte(text)); err != nil {
+			t.Fatal(err)
+		}
+
+		message, err := client.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(message) != "echo: "+text {
+			t.Fatalf("expected 'echo: %s', got '%s'", text, message)
+		}
+	}
+
+	if err := client.Write(ctx, []byte("bye")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the connection is closed once the handler returns
+	if _, err := client.Read(ctx); err == nil {
+		t.Fatal("expected the connection to be closed")
+	}
+}
+
+func TestTCPReadsSizePrefixedFlatbuffers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan []byte, 1)
+	address := serveTCP(t, ctx, func(conn transport.Conn) {
+		message, err := conn.Read(ctx)
+		if err == nil {
+			received <- append([]byte(nil), message...)
+		}
+	})
+
+	builder := flatbuffers.NewBuilder(64)
+	text := builder.CreateString("hello")
+	builder.FinishSizePrefixed(text)
+
+	socket, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer socket.Close()
+
+	// a native client writes its size prefixed buffers as they are
+	if _, err := socket.Write(builder.FinishedBytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case message := <-received:
+		expected := builder.FinishedBytes()[4:]
+		if string(message) != string(expected) {
+			t.Fatalf("expected %v, got %v", expected, message)
+		}
+	case <-ctx.Done():
+		t.Fatal("the message wasn't read")
+	}
+}
+
+func TestTCPMessageTooBig(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	readErr := make(chan error, 1)
+	address := serveTCP(t, ctx, func(conn transport.Conn) {
+		_, err := conn.Read(ctx)
+		readErr <- err
+	})
+
+	socket, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer socket.Close()
+
+	header := binary.LittleEndian.AppendUint32(nil, uint32(transport.MaxMessageSize+1))
+	if _, err := socket.Write(header); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-readErr; err == nil {
+		t.Fatal("expected an error for a message over MaxMessageSize")
+	}
+}
+
+func TestTCPReadCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := transport.DialTCP(ctx, serveTCP(t, ctx, echo(ctx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	readCtx, readCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer readCancel()
+
+	if _, err := client.Read(readCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline, got %v", err)
+	}
+}
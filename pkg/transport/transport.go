@@ -0,0 +1,41 @@
+// Package transport is how the server talks to its players. A Transport accepts connections
+// and a Conn carries whole messages both ways, the game doesn't know if they go over a
+// websocket or stay in memory.
+package transport
+
+import "context"
+
+// MaxMessageSize is the biggest message a TCP, UDP or WebTransport connection reads, a peer
+// that sends a bigger one is disconnected.
+var MaxMessageSize = 1 << 20
+
+// Conn is a connection to one player, it sends and receives whole messages. Reads and writes
+// can happen at the same time and so can writes, but not two reads.
+type Conn interface {
+	// Read returns the next message, it's only valid until the next Read.
+	Read(ctx context.Context) ([]byte, error)
+	// Write sends the parts as one message. The parts can be reused once it returns.
+	Write(ctx context.Context, parts ...[]byte) error
+	// Close closes the connection right away, the pending reads and writes fail.
+	Close() error
+	// RemoteAddr is where the connection comes from.
+	RemoteAddr() string
+}
+
+// Transport accepts the connections of the players.
+type Transport interface {
+	// Serve calls handle for every new connection, each in its own goroutine, until ctx is
+	// done. The connection is closed once handle returns.
+	Serve(ctx context.Context, handle func(Conn)) error
+}
+
+// DatagramConn is a Conn that can also send messages that may be lost, arrive twice or out of
+// order, for state a newer message replaces anyway.
+type DatagramConn interface {
+	Conn
+	// WriteDatagram sends the parts as one unreliable message. A message bigger than
+	// MaxDatagramSize is sent reliably instead.
+	WriteDatagram(ctx context.Context, parts ...[]byte) error
+	// MaxDatagramSize is the biggest message WriteDatagram sends as a datagram.
+	MaxDatagramSize() int
+}
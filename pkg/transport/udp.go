@@ -0,0 +1,750 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// UDPPayloadSize is the biggest message sent in one datagram, bigger ones are split. It's
+	// below the usual MTU so the datagrams aren't fragmented on the way.
+	UDPPayloadSize = 1200
+	// UDPWindow is how many datagrams can wait for their ack, writes block when it's full.
+	UDPWindow = 1024
+	// UDPResendAfter is how long a datagram waits for its ack before it's sent again.
+	UDPResendAfter = 100 * time.Millisecond
+	// UDPTimeout is how long a connection lives without hearing from the other end.
+	UDPTimeout = 10 * time.Second
+	// UDPCookieLifetime is how long a client has to send back the cookie the transport answered
+	// its connect with.
+	UDPCookieLifetime = 5 * time.Second
+	// UDPSocketBuffer is the size asked for the buffers of the sockets, every player shares the
+	// socket of the transport so the default is too small.
+	UDPSocketBuffer = 4 << 20
+)
+
+// The first byte of every datagram says what it is.
+const (
+	udpConnect byte = iota + 1
+	udpAccept
+	// udpData is a whole message or the last part of a split one.
+	udpData
+	// udpPart is a part of a split message, more parts follow.
+	udpPart
+	udpAck
+	udpClose
+	// udpCookie answers a connect from an address that didn't send back a valid cookie yet.
+	udpCookie
+	// udpDatagram is a whole message that isn't numbered, acked or sent again.
+	udpDatagram
+)
+
+// udpCookieSize is when the cookie was made, in unix seconds, and the HMAC of it with the
+// address of the client.
+const (
+	udpCookieTime = 8
+	udpCookieSize = udpCookieTime + 16
+)
+
+// udpConnectSize is the kind and the cookie, a client that doesn't have one yet sends zeros.
+// A cookie is never bigger than the connect it answers, so a spoofed address can't be used to
+// send someone more than the spoofer sent.
+const udpConnectSize = 1 + udpCookieSize
+
+// udpDataHeader is the kind and the sequence number in front of the data.
+const udpDataHeader = 1 + 4
+
+// udpAckSize is the kind, the next sequence number the receiver waits for and a bit for
+// each of the 32 after it, set if the receiver already has it.
+const udpAckSize = 1 + 4 + 4
+
+type udpPending struct {
+	datagram []byte
+	sent     time.Time
+}
+
+// udpConn is a connection over UDP. Every datagram of a message is numbered and acked, the
+// lost ones are sent again and the messages are read in the order they were written. The moves
+// go in datagrams that aren't, a newer move replaces a lost one anyway.
+type udpConn struct {
+	send       func([]byte) error
+	onClose    func()
+	remoteAddr string
+
+	udpSettings
+
+	mu        sync.Mutex
+	nextSend  uint32
+	unacked   map[uint32]*udpPending
+	nextRecv  uint32
+	received  map[uint32][]byte
+	message   []byte
+	lastHeard time.Time
+
+	// writeMu keeps the parts of a split message together
+	writeMu sync.Mutex
+
+	room      chan struct{}
+	messages  chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// udpSettings are the UDP variables when the transport or the connection was made.
+type udpSettings struct {
+	payloadSize int
+	window      int
+	resendAfter time.Duration
+	timeout     time.Duration
+	cookieLife  time.Duration
+}
+
+func currentUDPSettings() udpSettings {
+	return udpSettings{
+		payloadSize: UDPPayloadSize,
+		window:      UDPWindow,
+		resendAfter: UDPResendAfter,
+		timeout:     UDPTimeout,
+		cookieLife:  UDPCookieLifetime,
+	}
+}
+
+func newUDPConn(settings udpSettings, remoteAddr string, send func([]byte) error, onClose func()) *udpConn {
+	return &udpConn{
+		send:        send,
+		onClose:     onClose,
+		remoteAddr:  remoteAddr,
+		udpSettings: settings,
+		unacked:     map[uint32]*udpPending{},
+		received:    map[uint32][]byte{},
+		lastHeard:   time.Now(),
+		room:        make(chan struct{}, 1),
+		messages:    make(chan []byte, settings.window),
+		done:        make(chan struct{}),
+	}
+}
+
+// DialUDP connects to a UDP transport.
+func DialUDP(ctx context.Context, address string) (Conn, error) {
+	remote, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	socket, err := net.DialUDP("udp", nil, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	uc := newUDPConn(currentUDPSettings(), remote.String(), func(datagram []byte) error {
+		_, err := socket.Write(datagram)
+
+		return err
+	}, func() { socket.Close() })
+
+	buf := make([]byte, 64<<10)
+	first, err := connectUDP(ctx, socket, buf, uc.udpSettings)
+	if err != nil {
+		socket.Close()
+
+		return nil, err
+	}
+
+	uc.receive(first)
+
+	go func() {
+		for {
+			n, err := socket.Read(buf)
+			if err != nil {
+				uc.close(false)
+
+				return
+			}
+
+			uc.receive(buf[:n])
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(uc.resendAfter / 4)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				if uc.resend(now) {
+					uc.Close()
+				}
+			case <-uc.done:
+				return
+			}
+		}
+	}()
+
+	return uc, nil
+}
+
+// connectUDP asks to connect until the transport accepts, sending back the cookie it
+// answered with. It returns the first datagram the transport sent.
+func connectUDP(ctx context.Context, socket *net.UDPConn, buf []byte, settings udpSettings) ([]byte, error) {
+	start := time.Now()
+	defer socket.SetReadDeadline(time.Time{})
+
+	connect := make([]byte, udpConnectSize)
+	connect[0] = udpConnect
+
+	for time.Since(start) < settings.timeout {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		socket.Write(connect)
+		socket.SetReadDeadline(time.Now().Add(settings.resendAfter))
+
+		n, err := socket.Read(buf)
+		if err != nil {
+			// the transport may not be up yet, the read doesn't wait for the deadline then
+			if !errors.Is(err, os.ErrDeadlineExceeded) {
+				time.Sleep(settings.resendAfter)
+			}
+
+			continue
+		}
+
+		if n == udpConnectSize && buf[0] == udpCookie {
+			copy(connect[1:], buf[1:n])
+
+			continue
+		}
+
+		// the transport starts sending right after it accepts, its data means the accept
+		// was lost
+		if n > 0 && (buf[0] == udpAccept || buf[0] == udpData || buf[0] == udpPart) {
+			return buf[:n], nil
+		}
+	}
+
+	return nil, fmt.Errorf("'%s' didn't accept the connection", socket.RemoteAddr())
+}
+
+func (uc *udpConn) Read(ctx context.Context) ([]byte, error) {
+	select {
+	case <-uc.done:
+		return nil, net.ErrClosed
+	default:
+	}
+
+	select {
+	case message := <-uc.messages:
+		return message, nil
+	case <-uc.done:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (uc *udpConn) Write(ctx context.Context, parts ...[]byte) error {
+	message, err := joinParts(parts)
+	if err != nil {
+		return err
+	}
+
+	uc.writeMu.Lock()
+	defer uc.writeMu.Unlock()
+
+	for {
+		kind, payload := udpData, message
+		if len(message) > uc.payloadSize {
+			kind, payload = udpPart, message[:uc.payloadSize]
+		}
+
+		if err := uc.writeDatagram(ctx, kind, payload); err != nil {
+			return err
+		}
+
+		message = message[len(payload):]
+		if kind == udpData {
+			return nil
+		}
+	}
+}
+
+func (uc *udpConn) WriteDatagram(ctx context.Context, parts ...[]byte) error {
+	message, err := joinParts(parts)
+	if err != nil {
+		return err
+	}
+
+	if len(message) > uc.payloadSize {
+		return uc.Write(ctx, message)
+	}
+
+	select {
+	case <-uc.done:
+		return net.ErrClosed
+	default:
+	}
+
+	// a lost datagram stays lost, only a closed socket is an error
+	if err := uc.send(append([]byte{udpDatagram}, message...)); errors.Is(err, net.ErrClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (uc *udpConn) MaxDatagramSize() int {
+	return uc.payloadSize
+}
+
+// writeDatagram numbers the payload and sends it, once there's room in the window.
+func (uc *udpConn) writeDatagram(ctx context.Context, kind byte, payload []byte) error {
+	for {
+		select {
+		case <-uc.done:
+			return net.ErrClosed
+		default:
+		}
+
+		uc.mu.Lock()
+		if len(uc.unacked) < uc.window {
+			break
+		}
+		uc.mu.Unlock()
+
+		select {
+		case <-uc.room:
+		case <-uc.done:
+			return net.ErrClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	datagram := make([]byte, udpDataHeader, udpDataHeader+len(payload))
+	datagram[0] = kind
+	binary.LittleEndian.PutUint32(datagram[1:], uc.nextSend)
+	datagram = append(datagram, payload...)
+
+	uc.unacked[uc.nextSend] = &udpPending{datagram: datagram, sent: time.Now()}
+	uc.nextSend++
+	uc.mu.Unlock()
+
+	// a lost datagram is sent again, only a closed socket is an error
+	if err := uc.send(datagram); errors.Is(err, net.ErrClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// receive handles a datagram from the other end, it's only called from one goroutine.
+func (uc *udpConn) receive(datagram []byte) {
+	if len(datagram) == 0 {
+		return
+	}
+
+	uc.mu.Lock()
+	uc.lastHeard = time.Now()
+
+	switch datagram[0] {
+	case udpData, udpPart:
+		if len(datagram) < udpDataHeader {
+			break
+		}
+
+		ack, ok := uc.receiveData(datagram)
+		uc.mu.Unlock()
+
+		if !ok {
+			uc.close(true)
+		} else if ack != nil {
+			uc.send(ack)
+		}
+
+		return
+	case udpDatagram:
+		// it's lost when the reader is behind, like it could be on the way
+		select {
+		case uc.messages <- bytes.Clone(datagram[1:]):
+		default:
+		}
+	case udpAck:
+		if len(datagram) < udpAckSize {
+			break
+		}
+
+		uc.receiveAck(datagram)
+	case udpClose:
+		uc.mu.Unlock()
+		uc.close(false)
+
+		return
+	}
+
+	uc.mu.Unlock()
+}
+
+// receiveData stores the datagram and reads the messages that are complete. It returns the
+// ack to send, if one is needed, and false if the other end sent a message that's too big.
+func (uc *udpConn) receiveData(datagram []byte) ([]byte, bool) {
+	seq := binary.LittleEndian.Uint32(datagram[1:])
+	ahead := int32(seq - uc.nextRecv)
+
+	if ahead >= int32(uc.window) {
+		return nil, true
+	}
+
+	if ahead >= 0 {
+		if _, ok := uc.received[seq]; !ok {
+			uc.received[seq] = append([]byte(nil), datagram...)
+		}
+	}
+
+	complete := false
+
+	for {
+		datagram, ok := uc.received[uc.nextRecv]
+		if !ok {
+			break
+		}
+
+		if datagram[0] == udpData {
+			message := append(uc.message, datagram[udpDataHeader:]...)
+
+			// nothing is acked while the reader is behind, the other end sends it again
+			select {
+			case uc.messages <- message:
+			default:
+				return uc.ack(), true
+			}
+
+			uc.message = nil
+			complete = true
+		} else {
+			uc.message = append(uc.message, datagram[udpDataHeader:]...)
+			if len(uc.message) > MaxMessageSize {
+				return nil, false
+			}
+		}
+
+		delete(uc.received, uc.nextRecv)
+		uc.nextRecv++
+	}
+
+	// the parts are acked with the end of their message, unless something's missing
+	if complete || ahead != 0 {
+		return uc.ack(), true
+	}
+
+	return nil, true
+}
+
+func (uc *udpConn) ack() []byte {
+	ack := make([]byte, udpAckSize)
+	ack[0] = udpAck
+	binary.LittleEndian.PutUint32(ack[1:], uc.nextRecv)
+
+	var bits uint32
+	for i := range uint32(32) {
+		if _, ok := uc.received[uc.nextRecv+1+i]; ok {
+			bits |= 1 << i
+		}
+	}
+
+	binary.LittleEndian.PutUint32(ack[5:], bits)
+
+	return ack
+}
+
+func (uc *udpConn) receiveAck(ack []byte) {
+	next := binary.LittleEndian.Uint32(ack[1:])
+	bits := binary.LittleEndian.Uint32(ack[5:])
+
+	freed := false
+
+	for seq := range uc.unacked {
+		ahead := int32(seq - next)
+		if ahead < 0 || (ahead >= 1 && ahead <= 32 && bits&(1<<(ahead-1)) != 0) {
+			delete(uc.unacked, seq)
+			freed = true
+		}
+	}
+
+	if freed {
+		select {
+		case uc.room <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// resend sends again the datagrams that weren't acked in time, it returns true if the other
+// end wasn't heard from for too long.
+func (uc *udpConn) resend(now time.Time) bool {
+	uc.mu.Lock()
+
+	if now.Sub(uc.lastHeard) > uc.timeout {
+		uc.mu.Unlock()
+
+		return true
+	}
+
+	var late [][]byte
+
+	for _, pending := range uc.unacked {
+		if now.Sub(pending.sent) >= uc.resendAfter {
+			pending.sent = now
+			late = append(late, pending.datagram)
+		}
+	}
+
+	uc.mu.Unlock()
+
+	for _, datagram := range late {
+		uc.send(datagram)
+	}
+
+	return false
+}
+
+func (uc *udpConn) Close() error {
+	uc.close(true)
+
+	return nil
+}
+
+// close closes the connection, telling the other end if notify is set.
+func (uc *udpConn) close(notify bool) {
+	uc.closeOnce.Do(func() {
+		close(uc.done)
+
+		if notify {
+			uc.send([]byte{udpClose})
+		}
+
+		uc.onClose()
+	})
+}
+
+func (uc *udpConn) RemoteAddr() string {
+	return uc.remoteAddr
+}
+
+// UDP accepts connections over a single UDP socket, their messages are acked and sent again
+// when they're lost. A connect is answered with a cookie and the connection is only made once
+// the client sends it back, so nothing is kept for the addresses that don't.
+type UDP struct {
+	socket   *net.UDPConn
+	settings udpSettings
+	// secret signs the cookies
+	secret []byte
+
+	mu    sync.Mutex
+	conns map[netip.AddrPort]*udpConn
+}
+
+// ListenUDP starts listening on the address, connections are accepted once it's served.
+func ListenUDP(address string) (*UDP, error) {
+	local, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	socket, err := net.ListenUDP("udp", local)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen on '%s': %w", address, err)
+	}
+
+	socket.SetReadBuffer(UDPSocketBuffer)
+	socket.SetWriteBuffer(UDPSocketBuffer)
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		socket.Close()
+
+		return nil, err
+	}
+
+	return &UDP{
+		socket:   socket,
+		settings: currentUDPSettings(),
+		secret:   secret,
+		conns:    map[netip.AddrPort]*udpConn{},
+	}, nil
+}
+
+// Addr is the address the transport listens on.
+func (u *UDP) Addr() net.Addr {
+	return u.socket.LocalAddr()
+}
+
+func (u *UDP) Serve(ctx context.Context, handle func(Conn)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() { u.socket.Close() })
+	defer stop()
+
+	go u.resendLoop(ctx)
+	defer u.closeAll()
+
+	buf := make([]byte, 64<<10)
+
+	for {
+		n, addr, err := u.socket.ReadFromUDPAddrPort(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if n == 0 {
+			continue
+		}
+
+		if buf[0] == udpConnect {
+			u.connect(addr, buf[:n], handle)
+
+			continue
+		}
+
+		u.mu.Lock()
+		uc := u.conns[addr]
+		u.mu.Unlock()
+
+		if uc != nil {
+			uc.receive(buf[:n])
+		}
+	}
+}
+
+// connect answers a connect with a cookie, or accepts it if the address sent back a valid one
+// or is already connected.
+func (u *UDP) connect(addr netip.AddrPort, datagram []byte, handle func(Conn)) {
+	if len(datagram) < udpConnectSize {
+		return
+	}
+
+	now := time.Now()
+
+	u.mu.Lock()
+	uc := u.conns[addr]
+
+	if uc == nil && u.validCookie(addr, datagram[1:udpConnectSize], now) {
+		uc = u.newConn(addr)
+		u.conns[addr] = uc
+
+		go func() {
+			defer uc.Close()
+
+			handle(uc)
+		}()
+	}
+	u.mu.Unlock()
+
+	if uc == nil {
+		u.socket.WriteToUDPAddrPort(append([]byte{udpCookie}, u.cookie(addr, now)...), addr)
+
+		return
+	}
+
+	u.socket.WriteToUDPAddrPort([]byte{udpAccept}, addr)
+}
+
+// cookie is the time it was made and the HMAC of the time and the address, only the one at the
+// address gets it and the transport doesn't have to remember it.
+func (u *UDP) cookie(addr netip.AddrPort, made time.Time) []byte {
+	cookie := make([]byte, udpCookieTime, udpCookieSize)
+	binary.LittleEndian.PutUint64(cookie, uint64(made.Unix()))
+
+	return append(cookie, u.cookieMAC(addr, cookie)...)
+}
+
+func (u *UDP) cookieMAC(addr netip.AddrPort, made []byte) []byte {
+	mac := hmac.New(sha256.New, u.secret)
+
+	ip := addr.Addr().As16()
+	mac.Write(ip[:])
+	mac.Write(binary.LittleEndian.AppendUint16(nil, addr.Port()))
+	mac.Write(made)
+
+	return mac.Sum(nil)[:udpCookieSize-udpCookieTime]
+}
+
+func (u *UDP) validCookie(addr netip.AddrPort, cookie []byte, now time.Time) bool {
+	made := time.Unix(int64(binary.LittleEndian.Uint64(cookie)), 0)
+	if made.After(now) || now.Sub(made) > u.settings.cookieLife {
+		return false
+	}
+
+	return hmac.Equal(cookie[udpCookieTime:], u.cookieMAC(addr, cookie[:udpCookieTime]))
+}
+
+func (u *UDP) newConn(addr netip.AddrPort) *udpConn {
+	var uc *udpConn
+
+	uc = newUDPConn(u.settings, addr.String(), func(datagram []byte) error {
+		_, err := u.socket.WriteToUDPAddrPort(datagram, addr)
+
+		return err
+	}, func() {
+		u.mu.Lock()
+		if u.conns[addr] == uc {
+			delete(u.conns, addr)
+		}
+		u.mu.Unlock()
+	})
+
+	return uc
+}
+
+func (u *UDP) connections() []*udpConn {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	conns := make([]*udpConn, 0, len(u.conns))
+	for _, uc := range u.conns {
+		conns = append(conns, uc)
+	}
+
+	return conns
+}
+
+func (u *UDP) resendLoop(ctx context.Context) {
+	ticker := time.NewTicker(u.settings.resendAfter / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, uc := range u.connections() {
+				if uc.resend(now) {
+					uc.Close()
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (u *UDP) closeAll() {
+	for _, uc := range u.connections() {
+		uc.close(false)
+	}
+}
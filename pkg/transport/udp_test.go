@@ -0,0 +1,426 @@
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+)
+
+func serveUDP(t *testing.T, ctx context.Context, handle func(transport.Conn)) string {
+	t.Helper()
+
+	udp, err := transport.ListenUDP("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go udp.Serve(ctx, handle)
+
+	return udp.Addr().String()
+}
+
+// lossyProxy forwards the datagrams of one client to the address and back, dropping some of
+// them in both directions.
+func lossyProxy(t *testing.T, ctx context.Context, address string, loss float64) string {
+	t.Helper()
+
+	front, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := net.DialUDP("udp", nil, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	context.AfterFunc(ctx, func() {
+		front.Close()
+		back.Close()
+	})
+
+	var (
+		mu     sync.Mutex
+		random = rand.New(rand.NewSource(1))
+		client *net.UDPAddr
+	)
+
+	drop := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return random.Float64() < loss
+	}
+
+	go func() {
+		buf := make([]byte, 64<<10)
+		for {
+			n, addr, err := front.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			client = addr
+			mu.Unlock()
+
+			if !drop() {
+				back.Write(buf[:n])
+			}
+		}
+	}()
+
+	go func() {
+		buf := make([]byte, 64<<10)
+		for {
+			n, err := back.Read(buf)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			addr := client
+			mu.Unlock()
+
+			if addr != nil && !drop() {
+				front.WriteToUDP(buf[:n], addr)
+			}
+		}
+	}()
+
+	return front.LocalAddr().String()
+}
+
+func TestUDP(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := transport.DialUDP(ctx, serveUDP(t, ctx, echo(ctx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// bigger than a datagram, it's split and put back together
+	big := bytes.Repeat([]byte("0123456789"), 3*transport.UDPPayloadSize/10+7)
+
+	for _, message := range [][]byte{[]byte("ping"), {}, big} {
+		if err := client.Write(ctx, message[:len(message)/2], message[len(message)/2:]); err != nil {
+			t.Fatal(err)
+		}
+
+		echoed, err := client.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(echoed, append([]byte("echo: "), message...)) {
+			t.Fatalf("expected the echo of %d bytes, got %d bytes", len(message), len(echoed))
+		}
+	}
+
+	if err := client.Write(ctx, []byte("bye")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the connection is closed once the handler returns
+	if _, err := client.Read(ctx); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected net.ErrClosed, got %v", err)
+	}
+}
+
+func TestUDPDeliversEverythingInOrderDespiteLoss(t *testing.T) {
+	defer func(resendAfter time.Duration) { transport.UDPResendAfter = resendAfter }(transport.UDPResendAfter)
+	transport.UDPResendAfter = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	address := lossyProxy(t, ctx, serveUDP(t, ctx, echo(ctx)), 0.3)
+
+	client, err := transport.DialUDP(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	const messages = 200
+
+	written := make(chan error, 1)
+	go func() {
+		for i := range messages {
+			message := []byte(fmt.Sprintf("move %d", i))
+			if i%10 == 0 {
+				message = append(message, bytes.Repeat([]byte{'.'}, 2*transport.UDPPayloadSize)...)
+			}
+
+			if err := client.Write(ctx, message); err != nil {
+				written <- err
+				return
+			}
+		}
+
+		written <- nil
+	}()
+
+	for i := range messages {
+		echoed, err := client.Read(ctx)
+		if err != nil {
+			t.Fatalf("message %d: %v", i, err)
+		}
+
+		expected := fmt.Sprintf("echo: move %d", i)
+		if !bytes.HasPrefix(echoed, []byte(expected)) {
+			t.Fatalf("expected '%s', got '%.20s'", expected, echoed)
+		}
+	}
+
+	if err := <-written; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUDPClose(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	closed := make(chan error, 1)
+	address := serveUDP(t, ctx, func(conn transport.Conn) {
+		_, err := conn.Read(ctx)
+		closed <- err
+	})
+
+	client, err := transport.DialUDP(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.Close()
+
+	select {
+	case err := <-closed:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Fatalf("expected net.ErrClosed, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("closing the client didn't close the connection on the server")
+	}
+
+	if err := client.Write(ctx, []byte("late")); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected net.ErrClosed, got %v", err)
+	}
+}
+
+func TestUDPOnlyConnectsAddressesThatSendBackTheirCookie(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	handled := make(chan transport.Conn, 1)
+	server, err := net.ResolveUDPAddr("udp", serveUDP(t, ctx, func(conn transport.Conn) {
+		handled <- conn
+		conn.Read(ctx)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first byte of a datagram is its kind, 1 is a connect and 2 an accept
+	const connect, accept = 1, 2
+
+	dial := func() *net.UDPConn {
+		socket, err := net.DialUDP("udp", nil, server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { socket.Close() })
+
+		return socket
+	}
+
+	exchange := func(socket *net.UDPConn, datagram []byte) []byte {
+		if _, err := socket.Write(datagram); err != nil {
+			t.Fatal(err)
+		}
+
+		socket.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+
+		buf := make([]byte, 64<<10)
+		n, err := socket.Read(buf)
+		if err != nil {
+			return nil
+		}
+
+		if n > len(datagram) {
+			t.Fatalf("a connect of %d bytes was answered with %d bytes", len(datagram), n)
+		}
+
+		return buf[:n]
+	}
+
+	noConnection := func() {
+		select {
+		case <-handled:
+			t.Fatal("expected no connection before the cookie is sent back")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	client, spoofer := dial(), dial()
+
+	// too small to be answered with a cookie
+	if reply := exchange(client, []byte{connect}); reply != nil {
+		t.Fatalf("expected no answer to a bare connect, got %v", reply)
+	}
+
+	cookie := exchange(client, append([]byte{connect}, make([]byte, 64)...))
+	if cookie == nil || cookie[0] == accept {
+		t.Fatalf("expected a cookie, got %v", cookie)
+	}
+	noConnection()
+
+	// the cookie is only good for the address it was sent to
+	echo := append([]byte{connect}, cookie[1:]...)
+	if reply := exchange(spoofer, echo); reply == nil || reply[0] == accept {
+		t.Fatalf("expected another cookie for the other address, got %v", reply)
+	}
+	noConnection()
+
+	forged := bytes.Clone(echo)
+	forged[len(forged)-1]++
+	if reply := exchange(client, forged); reply == nil || reply[0] == accept {
+		t.Fatalf("expected another cookie for a forged one, got %v", reply)
+	}
+	noConnection()
+
+	if reply := exchange(client, echo); reply == nil || reply[0] != accept {
+		t.Fatalf("expected the cookie sent back to be accepted, got %v", reply)
+	}
+
+	select {
+	case conn := <-handled:
+		if conn.RemoteAddr() != client.LocalAddr().String() {
+			t.Fatalf("expected the connection of %s, got %s", client.LocalAddr(), conn.RemoteAddr())
+		}
+	case <-ctx.Done():
+		t.Fatal("the cookie was accepted but there's no connection")
+	}
+}
+
+func TestUDPDatagrams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	big := bytes.Repeat([]byte{'x'}, 2*transport.UDPPayloadSize)
+
+	address := serveUDP(t, ctx, func(conn transport.Conn) {
+		datagramConn, ok := conn.(transport.DatagramConn)
+		if !ok {
+			t.Error("expected UDP connections to send datagrams")
+			return
+		}
+
+		if _, err := conn.Read(ctx); err != nil {
+			return
+		}
+
+		// nothing is lost on loopback, the datagram arrives
+		datagramConn.WriteDatagram(ctx, []byte("move"), []byte(" 1"))
+		datagramConn.WriteDatagram(ctx, big)
+
+		conn.Read(ctx)
+	})
+
+	client, err := transport.DialUDP(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.Write(ctx, []byte("ready")); err != nil {
+		t.Fatal(err)
+	}
+
+	received := map[string]bool{}
+	for range 2 {
+		message, err := client.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		received[string(message)] = true
+	}
+
+	if !received["move 1"] {
+		t.Fatal("expected the datagram 'move 1'")
+	}
+
+	// a message too big for a datagram is split and sent reliably
+	if !received[string(big)] {
+		t.Fatal("expected the message bigger than a datagram")
+	}
+}
+
+func TestUDPDatagramsAreNotSentAgain(t *testing.T) {
+	defer func(resendAfter time.Duration) { transport.UDPResendAfter = resendAfter }(transport.UDPResendAfter)
+	transport.UDPResendAfter = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	const datagrams = 200
+
+	address := lossyProxy(t, ctx, serveUDP(t, ctx, func(conn transport.Conn) {
+		if _, err := conn.Read(ctx); err != nil {
+			return
+		}
+
+		for i := range datagrams {
+			conn.(transport.DatagramConn).WriteDatagram(ctx, []byte(fmt.Sprintf("move %d", i)))
+		}
+
+		conn.Write(ctx, []byte("done"))
+		conn.Read(ctx)
+	}), 0.3)
+
+	client, err := transport.DialUDP(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.Write(ctx, []byte("ready")); err != nil {
+		t.Fatal(err)
+	}
+
+	moves := 0
+	for {
+		message, err := client.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(message) == "done" {
+			break
+		}
+
+		moves++
+	}
+
+	// the lost ones stay lost, the reliable message after them doesn't wait for them
+	if moves == 0 || moves == datagrams {
+		t.Fatalf("expected some of the %d datagrams to be lost, %d arrived", datagrams, moves)
+	}
+}
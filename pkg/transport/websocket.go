@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/coder/websocket"
+)
+
+type websocketConn struct {
+	conn       *websocket.Conn
+	remoteAddr string
+}
+
+// NewWebsocketConn wraps a websocket connection, messages are sent as binary.
+func NewWebsocketConn(conn *websocket.Conn, remoteAddr string) Conn {
+	return &websocketConn{conn: conn, remoteAddr: remoteAddr}
+}
+
+func (wc *websocketConn) Read(ctx context.Context) ([]byte, error) {
+	_, data, err := wc.conn.Read(ctx)
+
+	return data, err
+}
+
+func (wc *websocketConn) Write(ctx context.Context, parts ...[]byte) error {
+	if len(parts) == 1 {
+		return wc.conn.Write(ctx, websocket.MessageBinary, parts[0])
+	}
+
+	// The parts go as fragments of one message, so they don't have to be joined first
+	writer, err := wc.conn.Writer(ctx, websocket.MessageBinary)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+
+		if _, err := writer.Write(part); err != nil {
+			writer.Close()
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func (wc *websocketConn) Close() error {
+	return wc.conn.CloseNow()
+}
+
+func (wc *websocketConn) RemoteAddr() string {
+	return wc.remoteAddr
+}
+
+// Websocket accepts websocket connections on a path of a http.ServeMux, serving the mux is up
+// to the caller.
+type Websocket struct {
+	mux     *http.ServeMux
+	path    string
+	options *websocket.AcceptOptions
+}
+
+func NewWebsocket(mux *http.ServeMux, path string, options *websocket.AcceptOptions) *Websocket {
+	return &Websocket{mux: mux, path: path, options: options}
+}
+
+func (ws *Websocket) Serve(ctx context.Context, handle func(Conn)) error {
+	ws.mux.HandleFunc(ws.path, func(w http.ResponseWriter, r *http.Request) {
+		// Accept already answered the request when it fails
+		wsConn, err := websocket.Accept(w, r, ws.options)
+		if err != nil {
+			return
+		}
+
+		conn := NewWebsocketConn(wsConn, r.RemoteAddr)
+		defer conn.Close()
+
+		handle(conn)
+	})
+
+	<-ctx.Done()
+
+	return nil
+}
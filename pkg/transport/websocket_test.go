@@ -0,0 +1,71 @@
+package transport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+
+	"github.com/coder/websocket"
+)
+
+func TestWebsocket(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := http.NewServeMux()
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	remoteAddrs := make(chan string, 1)
+
+	go transport.NewWebsocket(mux, "/websocket", nil).Serve(ctx, func(conn transport.Conn) {
+		remoteAddrs <- conn.RemoteAddr()
+
+		message, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		conn.Write(ctx, []byte("echo: "), message)
+		conn.Read(ctx)
+	})
+
+	// Serve registers the handler in its own goroutine
+	var client *websocket.Conn
+	for client == nil {
+		conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/websocket", nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				t.Fatal(err)
+			}
+
+			time.Sleep(time.Millisecond)
+			continue
+		}
+
+		client = conn
+	}
+	defer client.CloseNow()
+
+	if err := client.Write(ctx, websocket.MessageBinary, []byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	kind, message, err := client.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kind != websocket.MessageBinary || string(message) != "echo: ping" {
+		t.Fatalf("expected a binary 'echo: ping', got %v '%s'", kind, message)
+	}
+
+	if remoteAddr := <-remoteAddrs; remoteAddr == "" {
+		t.Fatal("expected the remote address of the client")
+	}
+}
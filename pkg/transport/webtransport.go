@@ -0,0 +1,382 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/quic-go/webtransport-go"
+)
+
+// WebTransportDatagramSize is the biggest datagram a WebTransport connection sends, QUIC only
+// counts on packets of 1200 bytes and a datagram shares its packet with the headers.
+var WebTransportDatagramSize = 1100
+
+// WebTransportOpenTimeout is how long a new session has to open its stream.
+var WebTransportOpenTimeout = 5 * time.Second
+
+// WebTransportWriteQueue is how many messages, and separately datagrams, a WebTransport
+// connection holds while QUIC sends them. QUIC writes wait until their packets go out, so the
+// game doesn't wait on them. A write waits when the queue is full, a datagram is dropped.
+var WebTransportWriteQueue = 64
+
+// WebTransportFlushTimeout is how long a closing connection keeps sending the messages it holds.
+var WebTransportFlushTimeout = time.Second
+
+// webTransportConn sends the messages on one reliable stream of a WebTransport session, and
+// the datagrams as QUIC datagrams. Reads return both, in the order they arrive.
+type webTransportConn struct {
+	session      *webtransport.Session
+	stream       *streamConn
+	onClose      func()
+	datagramSize int
+
+	messages  chan []byte
+	writes    chan []byte
+	datagrams chan []byte
+	flushed   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWebTransportConn(session *webtransport.Session, stream *streamConn, onClose func()) *webTransportConn {
+	wc := &webTransportConn{
+		session:      session,
+		stream:       stream,
+		onClose:      onClose,
+		datagramSize: WebTransportDatagramSize,
+		messages:     make(chan []byte),
+		writes:       make(chan []byte, WebTransportWriteQueue),
+		datagrams:    make(chan []byte, WebTransportWriteQueue),
+		flushed:      make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+
+	go wc.readStream()
+	go wc.readDatagrams()
+	go wc.writeStream()
+	go wc.writeDatagrams()
+
+	return wc
+}
+
+// DialWebTransport connects to a WebTransport transport, the url is like
+// https://127.0.0.1:6972/webtransport.
+func DialWebTransport(ctx context.Context, url string, tlsConfig *tls.Config) (Conn, error) {
+	dialer := &webtransport.Dialer{
+		TLSClientConfig: tlsConfig,
+		QUICConfig:      &quic.Config{EnableDatagrams: true},
+	}
+
+	_, session, err := dialer.Dial(ctx, url, nil)
+	if err != nil {
+		dialer.Close()
+
+		return nil, err
+	}
+
+	stream, err := session.OpenStreamSync(ctx)
+	if err != nil {
+		session.CloseWithError(0, "")
+		dialer.Close()
+
+		return nil, err
+	}
+
+	conn := newStreamConn(stream, session.RemoteAddr().String())
+
+	// the transport only sees the stream once something is sent on it
+	if err := conn.Write(ctx); err != nil {
+		session.CloseWithError(0, "")
+		dialer.Close()
+
+		return nil, err
+	}
+
+	return newWebTransportConn(session, conn, func() { dialer.Close() }), nil
+}
+
+func (wc *webTransportConn) readStream() {
+	defer wc.Close()
+
+	for {
+		message, err := wc.stream.Read(wc.session.Context())
+		if err != nil {
+			return
+		}
+
+		if !wc.deliver(bytes.Clone(message)) {
+			return
+		}
+	}
+}
+
+func (wc *webTransportConn) readDatagrams() {
+	for {
+		datagram, err := wc.session.ReceiveDatagram(wc.session.Context())
+		if err != nil {
+			return
+		}
+
+		if !wc.deliver(datagram) {
+			return
+		}
+	}
+}
+
+func (wc *webTransportConn) deliver(message []byte) bool {
+	select {
+	case wc.messages <- message:
+		return true
+	case <-wc.done:
+		return false
+	}
+}
+
+func (wc *webTransportConn) Read(ctx context.Context) ([]byte, error) {
+	select {
+	case <-wc.done:
+		return nil, net.ErrClosed
+	default:
+	}
+
+	select {
+	case message := <-wc.messages:
+		return message, nil
+	case <-wc.done:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (wc *webTransportConn) writeStream() {
+	defer close(wc.flushed)
+
+	for {
+		select {
+		case message := <-wc.writes:
+			if err := wc.stream.Write(wc.session.Context(), message); err != nil {
+				// Close waits for this goroutine
+				go wc.Close()
+				return
+			}
+		case <-wc.done:
+			wc.flush()
+			return
+		}
+	}
+}
+
+// flush writes the messages queued before the connection closed, while the peer takes them.
+func (wc *webTransportConn) flush() {
+	ctx, cancel := context.WithTimeout(wc.session.Context(), WebTransportFlushTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case message := <-wc.writes:
+			if err := wc.stream.Write(ctx, message); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (wc *webTransportConn) writeDatagrams() {
+	for {
+		select {
+		case datagram := <-wc.datagrams:
+			err := wc.session.SendDatagram(datagram)
+
+			// the path can carry less than we counted on
+			var tooLarge *quic.DatagramTooLargeError
+			if errors.As(err, &tooLarge) {
+				err = wc.Write(wc.session.Context(), datagram)
+			}
+
+			if err != nil {
+				return
+			}
+		case <-wc.done:
+			return
+		}
+	}
+}
+
+func (wc *webTransportConn) Write(ctx context.Context, parts ...[]byte) error {
+	message, err := joinParts(parts)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-wc.done:
+		return net.ErrClosed
+	default:
+	}
+
+	select {
+	case wc.writes <- message:
+		return nil
+	case <-wc.done:
+		return net.ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (wc *webTransportConn) WriteDatagram(ctx context.Context, parts ...[]byte) error {
+	datagram, err := joinParts(parts)
+	if err != nil {
+		return err
+	}
+
+	if len(datagram) > wc.datagramSize {
+		return wc.Write(ctx, datagram)
+	}
+
+	select {
+	case <-wc.done:
+		return net.ErrClosed
+	default:
+	}
+
+	select {
+	case wc.datagrams <- datagram:
+	default:
+		// it's lost, like it could be on the way
+	}
+
+	return nil
+}
+
+// joinParts copies the parts in one message, the caller can reuse them once the write returns.
+func joinParts(parts [][]byte) ([]byte, error) {
+	size := 0
+	for _, part := range parts {
+		size += len(part)
+	}
+
+	if size > MaxMessageSize {
+		return nil, fmt.Errorf("message of %d bytes is bigger than %d", size, MaxMessageSize)
+	}
+
+	message := make([]byte, 0, size)
+	for _, part := range parts {
+		message = append(message, part...)
+	}
+
+	return message, nil
+}
+
+func (wc *webTransportConn) MaxDatagramSize() int {
+	return wc.datagramSize
+}
+
+func (wc *webTransportConn) Close() error {
+	wc.closeOnce.Do(func() {
+		close(wc.done)
+		<-wc.flushed
+
+		wc.session.CloseWithError(0, "")
+		wc.onClose()
+	})
+
+	return nil
+}
+
+func (wc *webTransportConn) RemoteAddr() string {
+	return wc.session.RemoteAddr().String()
+}
+
+// WebTransport accepts WebTransport sessions over HTTP/3. Every session opens one stream for
+// the messages that have to arrive, movement can go in datagrams instead, so a lost packet
+// doesn't hold back the rest.
+type WebTransport struct {
+	server *webtransport.Server
+	socket net.PacketConn
+	path   string
+}
+
+// ListenWebTransport starts listening on the UDP address, sessions are accepted on the path
+// once it's served.
+func ListenWebTransport(address string, path string, tlsConfig *tls.Config) (*WebTransport, error) {
+	socket, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen on '%s': %w", address, err)
+	}
+
+	server := &webtransport.Server{
+		H3: http3.Server{TLSConfig: http3.ConfigureTLSConfig(tlsConfig)},
+		// the game page is served over plain HTTP from another port
+		CheckOrigin: func(*http.Request) bool { return true },
+	}
+
+	return &WebTransport{server: server, socket: socket, path: path}, nil
+}
+
+// Addr is the address the transport listens on.
+func (wt *WebTransport) Addr() net.Addr {
+	return wt.socket.LocalAddr()
+}
+
+func (wt *WebTransport) Serve(ctx context.Context, handle func(Conn)) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(wt.path, func(w http.ResponseWriter, r *http.Request) {
+		wt.serveSession(ctx, w, r, handle)
+	})
+
+	wt.server.H3.Handler = mux
+
+	stop := context.AfterFunc(ctx, func() { wt.server.Close() })
+	defer stop()
+	defer wt.socket.Close()
+
+	err := wt.server.Serve(wt.socket)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
+
+func (wt *WebTransport) serveSession(ctx context.Context, w http.ResponseWriter, r *http.Request, handle func(Conn)) {
+	session, err := wt.server.Upgrade(w, r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	openCtx, cancel := context.WithTimeout(ctx, WebTransportOpenTimeout)
+	defer cancel()
+
+	stream, err := session.AcceptStream(openCtx)
+	if err != nil {
+		session.CloseWithError(0, "")
+		return
+	}
+
+	conn := newStreamConn(stream, session.RemoteAddr().String())
+
+	// the empty message the client opens the stream with
+	if _, err := conn.Read(openCtx); err != nil {
+		session.CloseWithError(0, "")
+		return
+	}
+
+	wc := newWebTransportConn(session, conn, func() {})
+	defer wc.Close()
+
+	handle(wc)
+}
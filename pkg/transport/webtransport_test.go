@@ -0,0 +1,148 @@
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
+)
+
+func serveWebTransport(t *testing.T, ctx context.Context, handle func(transport.Conn)) func(context.Context) (transport.Conn, error) {
+	t.Helper()
+
+	tlsConfig, hash, err := transport.SelfSignedTLS("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webTransport, err := transport.ListenWebTransport("127.0.0.1:0", "/webtransport", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go webTransport.Serve(ctx, handle)
+
+	url := "https://" + webTransport.Addr().String() + "/webtransport"
+
+	return func(ctx context.Context) (transport.Conn, error) {
+		return transport.DialWebTransport(ctx, url, transport.VerifyCertificateHash(hash))
+	}
+}
+
+func TestWebTransport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := serveWebTransport(t, ctx, echo(ctx))(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, text := range []string{"ping", "", "pong"} {
+		if err := client.Write(ctx, []byte(text)); err != nil {
+			t.Fatal(err)
+		}
+
+		message, err := client.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(message) != "echo: "+text {
+			t.Fatalf("expected 'echo: %s', got '%s'", text, message)
+		}
+	}
+
+	if err := client.Write(ctx, []byte("bye")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the connection is closed once the handler returns
+	if _, err := client.Read(ctx); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected net.ErrClosed, got %v", err)
+	}
+}
+
+func TestWebTransportDatagrams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	big := bytes.Repeat([]byte{'x'}, 2*transport.WebTransportDatagramSize)
+
+	dial := serveWebTransport(t, ctx, func(conn transport.Conn) {
+		datagramConn, ok := conn.(transport.DatagramConn)
+		if !ok {
+			t.Error("expected WebTransport connections to send datagrams")
+			return
+		}
+
+		if _, err := conn.Read(ctx); err != nil {
+			return
+		}
+
+		// nothing is lost on loopback, the datagrams arrive
+		datagramConn.WriteDatagram(ctx, []byte("move"), []byte(" 1"))
+		datagramConn.WriteDatagram(ctx, big)
+
+		conn.Read(ctx)
+	})
+
+	client, err := dial(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.Write(ctx, []byte("ready")); err != nil {
+		t.Fatal(err)
+	}
+
+	received := map[string]bool{}
+	for range 2 {
+		message, err := client.Read(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		received[string(message)] = true
+	}
+
+	if !received["move 1"] {
+		t.Fatal("expected the datagram 'move 1'")
+	}
+
+	// a message too big for a datagram goes on the stream
+	if !received[string(big)] {
+		t.Fatal("expected the message bigger than a datagram")
+	}
+}
+
+func TestVerifyCertificateHash(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tlsConfig, hash, err := transport.SelfSignedTLS("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webTransport, err := transport.ListenWebTransport("127.0.0.1:0", "/webtransport", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go webTransport.Serve(ctx, echo(ctx))
+
+	wrongHash := bytes.Clone(hash)
+	wrongHash[0]++
+
+	url := "https://" + webTransport.Addr().String() + "/webtransport"
+	if _, err := transport.DialWebTransport(ctx, url, transport.VerifyCertificateHash(wrongHash)); err == nil {
+		t.Fatal("expected the certificate to be refused")
+	}
+}
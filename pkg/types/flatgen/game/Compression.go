@@ -0,0 +1,29 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import "strconv"
+
+type Compression byte
+
+const (
+	CompressionNone    Compression = 0
+	CompressionDeflate Compression = 1
+)
+
+var EnumNamesCompression = map[Compression]string{
+	CompressionNone:    "None",
+	CompressionDeflate: "Deflate",
+}
+
+var EnumValuesCompression = map[string]Compression{
+	"None":    CompressionNone,
+	"Deflate": CompressionDeflate,
+}
+
+func (v Compression) String() string {
+	if s, ok := EnumNamesCompression[v]; ok {
+		return s
+	}
+	return "Compression(" + strconv.FormatInt(int64(v), 10) + ")"
+}
@@ -0,0 +1,90 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type Entity struct {
+	_tab flatbuffers.Struct
+}
+
+func (rcv *Entity) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *Entity) Table() flatbuffers.Table {
+	return rcv._tab.Table
+}
+
+func (rcv *Entity) Id() int32 {
+	return rcv._tab.GetInt32(rcv._tab.Pos + flatbuffers.UOffsetT(0))
+}
+func (rcv *Entity) MutateId(n int32) bool {
+	return rcv._tab.MutateInt32(rcv._tab.Pos+flatbuffers.UOffsetT(0), n)
+}
+
+func (rcv *Entity) Kind() EntityKind {
+	return EntityKind(rcv._tab.GetByte(rcv._tab.Pos + flatbuffers.UOffsetT(4)))
+}
+func (rcv *Entity) MutateKind(n EntityKind) bool {
+	return rcv._tab.MutateByte(rcv._tab.Pos+flatbuffers.UOffsetT(4), byte(n))
+}
+
+func (rcv *Entity) Owner() int32 {
+	return rcv._tab.GetInt32(rcv._tab.Pos + flatbuffers.UOffsetT(8))
+}
+func (rcv *Entity) MutateOwner(n int32) bool {
+	return rcv._tab.MutateInt32(rcv._tab.Pos+flatbuffers.UOffsetT(8), n)
+}
+
+func (rcv *Entity) X() int32 {
+	return rcv._tab.GetInt32(rcv._tab.Pos + flatbuffers.UOffsetT(12))
+}
+func (rcv *Entity) MutateX(n int32) bool {
+	return rcv._tab.MutateInt32(rcv._tab.Pos+flatbuffers.UOffsetT(12), n)
+}
+
+func (rcv *Entity) Y() int32 {
+	return rcv._tab.GetInt32(rcv._tab.Pos + flatbuffers.UOffsetT(16))
+}
+func (rcv *Entity) MutateY(n int32) bool {
+	return rcv._tab.MutateInt32(rcv._tab.Pos+flatbuffers.UOffsetT(16), n)
+}
+
+func (rcv *Entity) Vx() float32 {
+	return rcv._tab.GetFloat32(rcv._tab.Pos + flatbuffers.UOffsetT(20))
+}
+func (rcv *Entity) MutateVx(n float32) bool {
+	return rcv._tab.MutateFloat32(rcv._tab.Pos+flatbuffers.UOffsetT(20), n)
+}
+
+func (rcv *Entity) Vy() float32 {
+	return rcv._tab.GetFloat32(rcv._tab.Pos + flatbuffers.UOffsetT(24))
+}
+func (rcv *Entity) MutateVy(n float32) bool {
+	return rcv._tab.MutateFloat32(rcv._tab.Pos+flatbuffers.UOffsetT(24), n)
+}
+
+func (rcv *Entity) Size() float32 {
+	return rcv._tab.GetFloat32(rcv._tab.Pos + flatbuffers.UOffsetT(28))
+}
+func (rcv *Entity) MutateSize(n float32) bool {
+	return rcv._tab.MutateFloat32(rcv._tab.Pos+flatbuffers.UOffsetT(28), n)
+}
+
+func CreateEntity(builder *flatbuffers.Builder, id int32, kind EntityKind, owner int32, x int32, y int32, vx float32, vy float32, size float32) flatbuffers.UOffsetT {
+	builder.Prep(4, 32)
+	builder.PrependFloat32(size)
+	builder.PrependFloat32(vy)
+	builder.PrependFloat32(vx)
+	builder.PrependInt32(y)
+	builder.PrependInt32(x)
+	builder.PrependInt32(owner)
+	builder.Pad(3)
+	builder.PrependByte(byte(kind))
+	builder.PrependInt32(id)
+	return builder.Offset()
+}
@@ -0,0 +1,96 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type EntityDespawned struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsEntityDespawned(buf []byte, offset flatbuffers.UOffsetT) *EntityDespawned {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &EntityDespawned{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishEntityDespawnedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsEntityDespawned(buf []byte, offset flatbuffers.UOffsetT) *EntityDespawned {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &EntityDespawned{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedEntityDespawnedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *EntityDespawned) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *EntityDespawned) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *EntityDespawned) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *EntityDespawned) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *EntityDespawned) Ids(j int) int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetInt32(a + flatbuffers.UOffsetT(j*4))
+	}
+	return 0
+}
+
+func (rcv *EntityDespawned) IdsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *EntityDespawned) MutateIds(j int, n int32) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateInt32(a+flatbuffers.UOffsetT(j*4), n)
+	}
+	return false
+}
+
+func EntityDespawnedStart(builder *flatbuffers.Builder) {
+	builder.StartObject(2)
+}
+func EntityDespawnedAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func EntityDespawnedAddIds(builder *flatbuffers.Builder, ids flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(ids), 0)
+}
+func EntityDespawnedStartIdsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func EntityDespawnedEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
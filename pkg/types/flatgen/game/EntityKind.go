@@ -0,0 +1,35 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import "strconv"
+
+type EntityKind byte
+
+const (
+	EntityKindProp       EntityKind = 0
+	EntityKindProjectile EntityKind = 1
+	EntityKindPickup     EntityKind = 2
+	EntityKindNpc        EntityKind = 3
+)
+
+var EnumNamesEntityKind = map[EntityKind]string{
+	EntityKindProp:       "Prop",
+	EntityKindProjectile: "Projectile",
+	EntityKindPickup:     "Pickup",
+	EntityKindNpc:        "Npc",
+}
+
+var EnumValuesEntityKind = map[string]EntityKind{
+	"Prop":       EntityKindProp,
+	"Projectile": EntityKindProjectile,
+	"Pickup":     EntityKindPickup,
+	"Npc":        EntityKindNpc,
+}
+
+func (v EntityKind) String() string {
+	if s, ok := EnumNamesEntityKind[v]; ok {
+		return s
+	}
+	return "EntityKind(" + strconv.FormatInt(int64(v), 10) + ")"
+}
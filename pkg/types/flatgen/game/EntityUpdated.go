@@ -0,0 +1,89 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type EntityUpdated struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsEntityUpdated(buf []byte, offset flatbuffers.UOffsetT) *EntityUpdated {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &EntityUpdated{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishEntityUpdatedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsEntityUpdated(buf []byte, offset flatbuffers.UOffsetT) *EntityUpdated {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &EntityUpdated{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedEntityUpdatedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *EntityUpdated) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *EntityUpdated) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *EntityUpdated) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *EntityUpdated) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *EntityUpdated) Entities(obj *Entity, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 32
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *EntityUpdated) EntitiesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func EntityUpdatedStart(builder *flatbuffers.Builder) {
+	builder.StartObject(2)
+}
+func EntityUpdatedAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func EntityUpdatedAddEntities(builder *flatbuffers.Builder, entities flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(entities), 0)
+}
+func EntityUpdatedStartEntitiesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(32, numElems, 4)
+}
+func EntityUpdatedEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
@@ -15,6 +15,19 @@ const (
 	EventKindPlayerHelloConfirm EventKind = 5
 	EventKindPlayerMovedList    EventKind = 6
 	EventKindPlayerMoved        EventKind = 7
+	EventKindWorldMap           EventKind = 8
+	EventKindTimeSync           EventKind = 9
+	EventKindTimeSyncReply      EventKind = 10
+	EventKindPing               EventKind = 11
+	EventKindPong               EventKind = 12
+	EventKindIdleWarning        EventKind = 13
+	EventKindEntitySpawned      EventKind = 14
+	EventKindEntityUpdated      EventKind = 15
+	EventKindEntityDespawned    EventKind = 16
+	EventKindPlayerFire         EventKind = 17
+	EventKindPlayerDamaged      EventKind = 18
+	EventKindPlayerDied         EventKind = 19
+	EventKindPlayerRespawned    EventKind = 20
 )
 
 var EnumNamesEventKind = map[EventKind]string{
@@ -26,6 +39,19 @@ var EnumNamesEventKind = map[EventKind]string{
 	EventKindPlayerHelloConfirm: "PlayerHelloConfirm",
 	EventKindPlayerMovedList:    "PlayerMovedList",
 	EventKindPlayerMoved:        "PlayerMoved",
+	EventKindWorldMap:           "WorldMap",
+	EventKindTimeSync:           "TimeSync",
+	EventKindTimeSyncReply:      "TimeSyncReply",
+	EventKindPing:               "Ping",
+	EventKindPong:               "Pong",
+	EventKindIdleWarning:        "IdleWarning",
+	EventKindEntitySpawned:      "EntitySpawned",
+	EventKindEntityUpdated:      "EntityUpdated",
+	EventKindEntityDespawned:    "EntityDespawned",
+	EventKindPlayerFire:         "PlayerFire",
+	EventKindPlayerDamaged:      "PlayerDamaged",
+	EventKindPlayerDied:         "PlayerDied",
+	EventKindPlayerRespawned:    "PlayerRespawned",
 }
 
 var EnumValuesEventKind = map[string]EventKind{
@@ -37,6 +63,19 @@ var EnumValuesEventKind = map[string]EventKind{
 	"PlayerHelloConfirm": EventKindPlayerHelloConfirm,
 	"PlayerMovedList":    EventKindPlayerMovedList,
 	"PlayerMoved":        EventKindPlayerMoved,
+	"WorldMap":           EventKindWorldMap,
+	"TimeSync":           EventKindTimeSync,
+	"TimeSyncReply":      EventKindTimeSyncReply,
+	"Ping":               EventKindPing,
+	"Pong":               EventKindPong,
+	"IdleWarning":        EventKindIdleWarning,
+	"EntitySpawned":      EventKindEntitySpawned,
+	"EntityUpdated":      EventKindEntityUpdated,
+	"EntityDespawned":    EventKindEntityDespawned,
+	"PlayerFire":         EventKindPlayerFire,
+	"PlayerDamaged":      EventKindPlayerDamaged,
+	"PlayerDied":         EventKindPlayerDied,
+	"PlayerRespawned":    EventKindPlayerRespawned,
 }
 
 func (v EventKind) String() string {
@@ -0,0 +1,29 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import "strconv"
+
+type IdleAction byte
+
+const (
+	IdleActionSpectate IdleAction = 0
+	IdleActionKick     IdleAction = 1
+)
+
+var EnumNamesIdleAction = map[IdleAction]string{
+	IdleActionSpectate: "Spectate",
+	IdleActionKick:     "Kick",
+}
+
+var EnumValuesIdleAction = map[string]IdleAction{
+	"Spectate": IdleActionSpectate,
+	"Kick":     IdleActionKick,
+}
+
+func (v IdleAction) String() string {
+	if s, ok := EnumNamesIdleAction[v]; ok {
+		return s
+	}
+	return "IdleAction(" + strconv.FormatInt(int64(v), 10) + ")"
+}
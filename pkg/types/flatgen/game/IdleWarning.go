@@ -0,0 +1,94 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type IdleWarning struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsIdleWarning(buf []byte, offset flatbuffers.UOffsetT) *IdleWarning {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &IdleWarning{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishIdleWarningBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsIdleWarning(buf []byte, offset flatbuffers.UOffsetT) *IdleWarning {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &IdleWarning{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedIdleWarningBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *IdleWarning) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *IdleWarning) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *IdleWarning) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *IdleWarning) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *IdleWarning) Action() IdleAction {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return IdleAction(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *IdleWarning) MutateAction(n IdleAction) bool {
+	return rcv._tab.MutateByteSlot(6, byte(n))
+}
+
+func (rcv *IdleWarning) SecondsLeft() float32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetFloat32(o + rcv._tab.Pos)
+	}
+	return 0.0
+}
+
+func (rcv *IdleWarning) MutateSecondsLeft(n float32) bool {
+	return rcv._tab.MutateFloat32Slot(8, n)
+}
+
+func IdleWarningStart(builder *flatbuffers.Builder) {
+	builder.StartObject(3)
+}
+func IdleWarningAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func IdleWarningAddAction(builder *flatbuffers.Builder, action IdleAction) {
+	builder.PrependByteSlot(1, byte(action), 0)
+}
+func IdleWarningAddSecondsLeft(builder *flatbuffers.Builder, secondsLeft float32) {
+	builder.PrependFloat32Slot(2, secondsLeft, 0.0)
+}
+func IdleWarningEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
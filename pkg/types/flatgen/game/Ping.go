@@ -0,0 +1,79 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type Ping struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsPing(buf []byte, offset flatbuffers.UOffsetT) *Ping {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &Ping{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishPingBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsPing(buf []byte, offset flatbuffers.UOffsetT) *Ping {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &Ping{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedPingBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *Ping) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *Ping) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *Ping) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *Ping) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *Ping) Seq() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *Ping) MutateSeq(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(6, n)
+}
+
+func PingStart(builder *flatbuffers.Builder) {
+	builder.StartObject(2)
+}
+func PingAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func PingAddSeq(builder *flatbuffers.Builder, seq uint32) {
+	builder.PrependUint32Slot(1, seq, 0)
+}
+func PingEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
@@ -47,40 +47,40 @@ func (rcv *Player) MutateSpeed(n int32) bool {
 	return rcv._tab.MutateInt32(rcv._tab.Pos+flatbuffers.UOffsetT(12), n)
 }
 
-func (rcv *Player) MovingLeft() bool {
-	return rcv._tab.GetBool(rcv._tab.Pos + flatbuffers.UOffsetT(16))
+func (rcv *Player) InputX() float32 {
+	return rcv._tab.GetFloat32(rcv._tab.Pos + flatbuffers.UOffsetT(16))
 }
-func (rcv *Player) MutateMovingLeft(n bool) bool {
-	return rcv._tab.MutateBool(rcv._tab.Pos+flatbuffers.UOffsetT(16), n)
+func (rcv *Player) MutateInputX(n float32) bool {
+	return rcv._tab.MutateFloat32(rcv._tab.Pos+flatbuffers.UOffsetT(16), n)
 }
 
-func (rcv *Player) MovingRight() bool {
-	return rcv._tab.GetBool(rcv._tab.Pos + flatbuffers.UOffsetT(17))
+func (rcv *Player) InputY() float32 {
+	return rcv._tab.GetFloat32(rcv._tab.Pos + flatbuffers.UOffsetT(20))
 }
-func (rcv *Player) MutateMovingRight(n bool) bool {
-	return rcv._tab.MutateBool(rcv._tab.Pos+flatbuffers.UOffsetT(17), n)
+func (rcv *Player) MutateInputY(n float32) bool {
+	return rcv._tab.MutateFloat32(rcv._tab.Pos+flatbuffers.UOffsetT(20), n)
 }
 
-func (rcv *Player) MovingUp() bool {
-	return rcv._tab.GetBool(rcv._tab.Pos + flatbuffers.UOffsetT(18))
+func (rcv *Player) Vx() float32 {
+	return rcv._tab.GetFloat32(rcv._tab.Pos + flatbuffers.UOffsetT(24))
 }
-func (rcv *Player) MutateMovingUp(n bool) bool {
-	return rcv._tab.MutateBool(rcv._tab.Pos+flatbuffers.UOffsetT(18), n)
+func (rcv *Player) MutateVx(n float32) bool {
+	return rcv._tab.MutateFloat32(rcv._tab.Pos+flatbuffers.UOffsetT(24), n)
 }
 
-func (rcv *Player) MovingDown() bool {
-	return rcv._tab.GetBool(rcv._tab.Pos + flatbuffers.UOffsetT(19))
+func (rcv *Player) Vy() float32 {
+	return rcv._tab.GetFloat32(rcv._tab.Pos + flatbuffers.UOffsetT(28))
 }
-func (rcv *Player) MutateMovingDown(n bool) bool {
-	return rcv._tab.MutateBool(rcv._tab.Pos+flatbuffers.UOffsetT(19), n)
+func (rcv *Player) MutateVy(n float32) bool {
+	return rcv._tab.MutateFloat32(rcv._tab.Pos+flatbuffers.UOffsetT(28), n)
 }
 
-func CreatePlayer(builder *flatbuffers.Builder, id int32, x int32, y int32, speed int32, movingLeft bool, movingRight bool, movingUp bool, movingDown bool) flatbuffers.UOffsetT {
-	builder.Prep(4, 20)
-	builder.PrependBool(movingDown)
-	builder.PrependBool(movingUp)
-	builder.PrependBool(movingRight)
-	builder.PrependBool(movingLeft)
+func CreatePlayer(builder *flatbuffers.Builder, id int32, x int32, y int32, speed int32, inputX float32, inputY float32, vx float32, vy float32) flatbuffers.UOffsetT {
+	builder.Prep(4, 32)
+	builder.PrependFloat32(vy)
+	builder.PrependFloat32(vx)
+	builder.PrependFloat32(inputY)
+	builder.PrependFloat32(inputX)
 	builder.PrependInt32(speed)
 	builder.PrependInt32(y)
 	builder.PrependInt32(x)
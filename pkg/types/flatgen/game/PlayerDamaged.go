@@ -0,0 +1,124 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type PlayerDamaged struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsPlayerDamaged(buf []byte, offset flatbuffers.UOffsetT) *PlayerDamaged {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &PlayerDamaged{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishPlayerDamagedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsPlayerDamaged(buf []byte, offset flatbuffers.UOffsetT) *PlayerDamaged {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &PlayerDamaged{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedPlayerDamagedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *PlayerDamaged) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *PlayerDamaged) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *PlayerDamaged) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *PlayerDamaged) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *PlayerDamaged) Id() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerDamaged) MutateId(n int32) bool {
+	return rcv._tab.MutateInt32Slot(6, n)
+}
+
+func (rcv *PlayerDamaged) Attacker() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerDamaged) MutateAttacker(n int32) bool {
+	return rcv._tab.MutateInt32Slot(8, n)
+}
+
+func (rcv *PlayerDamaged) Damage() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerDamaged) MutateDamage(n int32) bool {
+	return rcv._tab.MutateInt32Slot(10, n)
+}
+
+func (rcv *PlayerDamaged) Health() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerDamaged) MutateHealth(n int32) bool {
+	return rcv._tab.MutateInt32Slot(12, n)
+}
+
+func PlayerDamagedStart(builder *flatbuffers.Builder) {
+	builder.StartObject(5)
+}
+func PlayerDamagedAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func PlayerDamagedAddId(builder *flatbuffers.Builder, id int32) {
+	builder.PrependInt32Slot(1, id, 0)
+}
+func PlayerDamagedAddAttacker(builder *flatbuffers.Builder, attacker int32) {
+	builder.PrependInt32Slot(2, attacker, 0)
+}
+func PlayerDamagedAddDamage(builder *flatbuffers.Builder, damage int32) {
+	builder.PrependInt32Slot(3, damage, 0)
+}
+func PlayerDamagedAddHealth(builder *flatbuffers.Builder, health int32) {
+	builder.PrependInt32Slot(4, health, 0)
+}
+func PlayerDamagedEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
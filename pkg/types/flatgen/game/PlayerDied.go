@@ -0,0 +1,109 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type PlayerDied struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsPlayerDied(buf []byte, offset flatbuffers.UOffsetT) *PlayerDied {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &PlayerDied{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishPlayerDiedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsPlayerDied(buf []byte, offset flatbuffers.UOffsetT) *PlayerDied {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &PlayerDied{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedPlayerDiedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *PlayerDied) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *PlayerDied) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *PlayerDied) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *PlayerDied) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *PlayerDied) Id() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerDied) MutateId(n int32) bool {
+	return rcv._tab.MutateInt32Slot(6, n)
+}
+
+func (rcv *PlayerDied) Killer() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerDied) MutateKiller(n int32) bool {
+	return rcv._tab.MutateInt32Slot(8, n)
+}
+
+func (rcv *PlayerDied) RespawnIn() float32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.GetFloat32(o + rcv._tab.Pos)
+	}
+	return 0.0
+}
+
+func (rcv *PlayerDied) MutateRespawnIn(n float32) bool {
+	return rcv._tab.MutateFloat32Slot(10, n)
+}
+
+func PlayerDiedStart(builder *flatbuffers.Builder) {
+	builder.StartObject(4)
+}
+func PlayerDiedAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func PlayerDiedAddId(builder *flatbuffers.Builder, id int32) {
+	builder.PrependInt32Slot(1, id, 0)
+}
+func PlayerDiedAddKiller(builder *flatbuffers.Builder, killer int32) {
+	builder.PrependInt32Slot(2, killer, 0)
+}
+func PlayerDiedAddRespawnIn(builder *flatbuffers.Builder, respawnIn float32) {
+	builder.PrependFloat32Slot(3, respawnIn, 0.0)
+}
+func PlayerDiedEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
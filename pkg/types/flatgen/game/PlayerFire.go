@@ -0,0 +1,94 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type PlayerFire struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsPlayerFire(buf []byte, offset flatbuffers.UOffsetT) *PlayerFire {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &PlayerFire{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishPlayerFireBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsPlayerFire(buf []byte, offset flatbuffers.UOffsetT) *PlayerFire {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &PlayerFire{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedPlayerFireBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *PlayerFire) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *PlayerFire) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *PlayerFire) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *PlayerFire) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *PlayerFire) DirX() float32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.GetFloat32(o + rcv._tab.Pos)
+	}
+	return 0.0
+}
+
+func (rcv *PlayerFire) MutateDirX(n float32) bool {
+	return rcv._tab.MutateFloat32Slot(6, n)
+}
+
+func (rcv *PlayerFire) DirY() float32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetFloat32(o + rcv._tab.Pos)
+	}
+	return 0.0
+}
+
+func (rcv *PlayerFire) MutateDirY(n float32) bool {
+	return rcv._tab.MutateFloat32Slot(8, n)
+}
+
+func PlayerFireStart(builder *flatbuffers.Builder) {
+	builder.StartObject(3)
+}
+func PlayerFireAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func PlayerFireAddDirX(builder *flatbuffers.Builder, dirX float32) {
+	builder.PrependFloat32Slot(1, dirX, 0.0)
+}
+func PlayerFireAddDirY(builder *flatbuffers.Builder, dirY float32) {
+	builder.PrependFloat32Slot(2, dirY, 0.0)
+}
+func PlayerFireEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
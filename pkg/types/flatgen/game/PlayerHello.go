@@ -65,8 +65,108 @@ func (rcv *PlayerHello) MutateId(n int32) bool {
 	return rcv._tab.MutateInt32Slot(6, n)
 }
 
+func (rcv *PlayerHello) PositionEncodings(j int) PositionEncoding {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return PositionEncoding(rcv._tab.GetByte(a + flatbuffers.UOffsetT(j*1)))
+	}
+	return 0
+}
+
+func (rcv *PlayerHello) PositionEncodingsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *PlayerHello) PositionEncodingsBytes() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *PlayerHello) MutatePositionEncodings(j int, n PositionEncoding) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateByte(a+flatbuffers.UOffsetT(j*1), byte(n))
+	}
+	return false
+}
+
+func (rcv *PlayerHello) FixedPointScale() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerHello) MutateFixedPointScale(n int32) bool {
+	return rcv._tab.MutateInt32Slot(10, n)
+}
+
+func (rcv *PlayerHello) Compressions(j int) Compression {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return Compression(rcv._tab.GetByte(a + flatbuffers.UOffsetT(j*1)))
+	}
+	return 0
+}
+
+func (rcv *PlayerHello) CompressionsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *PlayerHello) CompressionsBytes() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *PlayerHello) MutateCompressions(j int, n Compression) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateByte(a+flatbuffers.UOffsetT(j*1), byte(n))
+	}
+	return false
+}
+
+func (rcv *PlayerHello) DictionaryVersion() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerHello) MutateDictionaryVersion(n int32) bool {
+	return rcv._tab.MutateInt32Slot(14, n)
+}
+
+func (rcv *PlayerHello) Profile() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
 func PlayerHelloStart(builder *flatbuffers.Builder) {
-	builder.StartObject(2)
+	builder.StartObject(7)
 }
 func PlayerHelloAddKind(builder *flatbuffers.Builder, kind EventKind) {
 	builder.PrependByteSlot(0, byte(kind), 0)
@@ -74,6 +174,27 @@ func PlayerHelloAddKind(builder *flatbuffers.Builder, kind EventKind) {
 func PlayerHelloAddId(builder *flatbuffers.Builder, id int32) {
 	builder.PrependInt32Slot(1, id, 0)
 }
+func PlayerHelloAddPositionEncodings(builder *flatbuffers.Builder, positionEncodings flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(positionEncodings), 0)
+}
+func PlayerHelloStartPositionEncodingsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(1, numElems, 1)
+}
+func PlayerHelloAddFixedPointScale(builder *flatbuffers.Builder, fixedPointScale int32) {
+	builder.PrependInt32Slot(3, fixedPointScale, 0)
+}
+func PlayerHelloAddCompressions(builder *flatbuffers.Builder, compressions flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(4, flatbuffers.UOffsetT(compressions), 0)
+}
+func PlayerHelloStartCompressionsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(1, numElems, 1)
+}
+func PlayerHelloAddDictionaryVersion(builder *flatbuffers.Builder, dictionaryVersion int32) {
+	builder.PrependInt32Slot(5, dictionaryVersion, 0)
+}
+func PlayerHelloAddProfile(builder *flatbuffers.Builder, profile flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(6, flatbuffers.UOffsetT(profile), 0)
+}
 func PlayerHelloEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -65,8 +65,52 @@ func (rcv *PlayerHelloConfirm) MutateId(n int32) bool {
 	return rcv._tab.MutateInt32Slot(6, n)
 }
 
+func (rcv *PlayerHelloConfirm) PositionEncoding() PositionEncoding {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return PositionEncoding(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *PlayerHelloConfirm) MutatePositionEncoding(n PositionEncoding) bool {
+	return rcv._tab.MutateByteSlot(8, byte(n))
+}
+
+func (rcv *PlayerHelloConfirm) Compression() Compression {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return Compression(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *PlayerHelloConfirm) MutateCompression(n Compression) bool {
+	return rcv._tab.MutateByteSlot(10, byte(n))
+}
+
+func (rcv *PlayerHelloConfirm) Profile() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *PlayerHelloConfirm) DictionaryVersion() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerHelloConfirm) MutateDictionaryVersion(n int32) bool {
+	return rcv._tab.MutateInt32Slot(14, n)
+}
+
 func PlayerHelloConfirmStart(builder *flatbuffers.Builder) {
-	builder.StartObject(2)
+	builder.StartObject(6)
 }
 func PlayerHelloConfirmAddKind(builder *flatbuffers.Builder, kind EventKind) {
 	builder.PrependByteSlot(0, byte(kind), 0)
@@ -74,6 +118,18 @@ func PlayerHelloConfirmAddKind(builder *flatbuffers.Builder, kind EventKind) {
 func PlayerHelloConfirmAddId(builder *flatbuffers.Builder, id int32) {
 	builder.PrependInt32Slot(1, id, 0)
 }
+func PlayerHelloConfirmAddPositionEncoding(builder *flatbuffers.Builder, positionEncoding PositionEncoding) {
+	builder.PrependByteSlot(2, byte(positionEncoding), 0)
+}
+func PlayerHelloConfirmAddCompression(builder *flatbuffers.Builder, compression Compression) {
+	builder.PrependByteSlot(3, byte(compression), 0)
+}
+func PlayerHelloConfirmAddProfile(builder *flatbuffers.Builder, profile flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(4, flatbuffers.UOffsetT(profile), 0)
+}
+func PlayerHelloConfirmAddDictionaryVersion(builder *flatbuffers.Builder, dictionaryVersion int32) {
+	builder.PrependInt32Slot(5, dictionaryVersion, 0)
+}
 func PlayerHelloConfirmEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
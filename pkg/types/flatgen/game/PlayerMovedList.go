@@ -57,7 +57,7 @@ func (rcv *PlayerMovedList) Players(obj *Player, j int) bool {
 	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
 	if o != 0 {
 		x := rcv._tab.Vector(o)
-		x += flatbuffers.UOffsetT(j) * 20
+		x += flatbuffers.UOffsetT(j) * 32
 		obj.Init(rcv._tab.Bytes, x)
 		return true
 	}
@@ -82,7 +82,7 @@ func PlayerMovedListAddPlayers(builder *flatbuffers.Builder, players flatbuffers
 	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(players), 0)
 }
 func PlayerMovedListStartPlayersVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
-	return builder.StartVector(20, numElems, 4)
+	return builder.StartVector(32, numElems, 4)
 }
 func PlayerMovedListEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
@@ -0,0 +1,95 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type PlayerRespawned struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsPlayerRespawned(buf []byte, offset flatbuffers.UOffsetT) *PlayerRespawned {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &PlayerRespawned{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishPlayerRespawnedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsPlayerRespawned(buf []byte, offset flatbuffers.UOffsetT) *PlayerRespawned {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &PlayerRespawned{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedPlayerRespawnedBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *PlayerRespawned) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *PlayerRespawned) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *PlayerRespawned) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *PlayerRespawned) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *PlayerRespawned) Player(obj *Player) *Player {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		x := o + rcv._tab.Pos
+		if obj == nil {
+			obj = new(Player)
+		}
+		obj.Init(rcv._tab.Bytes, x)
+		return obj
+	}
+	return nil
+}
+
+func (rcv *PlayerRespawned) Health() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *PlayerRespawned) MutateHealth(n int32) bool {
+	return rcv._tab.MutateInt32Slot(8, n)
+}
+
+func PlayerRespawnedStart(builder *flatbuffers.Builder) {
+	builder.StartObject(3)
+}
+func PlayerRespawnedAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func PlayerRespawnedAddPlayer(builder *flatbuffers.Builder, player flatbuffers.UOffsetT) {
+	builder.PrependStructSlot(1, flatbuffers.UOffsetT(player), 0)
+}
+func PlayerRespawnedAddHealth(builder *flatbuffers.Builder, health int32) {
+	builder.PrependInt32Slot(2, health, 0)
+}
+func PlayerRespawnedEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
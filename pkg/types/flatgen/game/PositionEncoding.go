@@ -0,0 +1,29 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import "strconv"
+
+type PositionEncoding byte
+
+const (
+	PositionEncodingInteger    PositionEncoding = 0
+	PositionEncodingFixedPoint PositionEncoding = 2
+)
+
+var EnumNamesPositionEncoding = map[PositionEncoding]string{
+	PositionEncodingInteger:    "Integer",
+	PositionEncodingFixedPoint: "FixedPoint",
+}
+
+var EnumValuesPositionEncoding = map[string]PositionEncoding{
+	"Integer":    PositionEncodingInteger,
+	"FixedPoint": PositionEncodingFixedPoint,
+}
+
+func (v PositionEncoding) String() string {
+	if s, ok := EnumNamesPositionEncoding[v]; ok {
+		return s
+	}
+	return "PositionEncoding(" + strconv.FormatInt(int64(v), 10) + ")"
+}
@@ -0,0 +1,79 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type TimeSync struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsTimeSync(buf []byte, offset flatbuffers.UOffsetT) *TimeSync {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &TimeSync{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishTimeSyncBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsTimeSync(buf []byte, offset flatbuffers.UOffsetT) *TimeSync {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &TimeSync{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedTimeSyncBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *TimeSync) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *TimeSync) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *TimeSync) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *TimeSync) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *TimeSync) ClientTime() float64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.GetFloat64(o + rcv._tab.Pos)
+	}
+	return 0.0
+}
+
+func (rcv *TimeSync) MutateClientTime(n float64) bool {
+	return rcv._tab.MutateFloat64Slot(6, n)
+}
+
+func TimeSyncStart(builder *flatbuffers.Builder) {
+	builder.StartObject(2)
+}
+func TimeSyncAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func TimeSyncAddClientTime(builder *flatbuffers.Builder, clientTime float64) {
+	builder.PrependFloat64Slot(1, clientTime, 0.0)
+}
+func TimeSyncEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
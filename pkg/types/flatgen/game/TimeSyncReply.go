@@ -0,0 +1,109 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package game
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type TimeSyncReply struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsTimeSyncReply(buf []byte, offset flatbuffers.UOffsetT) *TimeSyncReply {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &TimeSyncReply{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishTimeSyncReplyBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsTimeSyncReply(buf []byte, offset flatbuffers.UOffsetT) *TimeSyncReply {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &TimeSyncReply{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedTimeSyncReplyBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *TimeSyncReply) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *TimeSyncReply) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *TimeSyncReply) Kind() EventKind {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return EventKind(rcv._tab.GetByte(o + rcv._tab.Pos))
+	}
+	return 0
+}
+
+func (rcv *TimeSyncReply) MutateKind(n EventKind) bool {
+	return rcv._tab.MutateByteSlot(4, byte(n))
+}
+
+func (rcv *TimeSyncReply) ClientTime() float64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.GetFloat64(o + rcv._tab.Pos)
+	}
+	return 0.0
+}
+
+func (rcv *TimeSyncReply) MutateClientTime(n float64) bool {
+	return rcv._tab.MutateFloat64Slot(6, n)
+}
+
+func (rcv *TimeSyncReply) ServerTime() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *TimeSyncReply) MutateServerTime(n int64) bool {
+	return rcv._tab.MutateInt64Slot(8, n)
+}
+
+func (rcv *TimeSyncReply) Tick() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *TimeSyncReply) MutateTick(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(10, n)
+}
+
+func TimeSyncReplyStart(builder *flatbuffers.Builder) {
+	builder.StartObject(4)
+}
+func TimeSyncReplyAddKind(builder *flatbuffers.Builder, kind EventKind) {
+	builder.PrependByteSlot(0, byte(kind), 0)
+}
+func TimeSyncReplyAddClientTime(builder *flatbuffers.Builder, clientTime float64) {
+	builder.PrependFloat64Slot(1, clientTime, 0.0)
+}
+func TimeSyncReplyAddServerTime(builder *flatbuffers.Builder, serverTime int64) {
+	builder.PrependInt64Slot(2, serverTime, 0)
+}
+func TimeSyncReplyAddTick(builder *flatbuffers.Builder, tick uint32) {
+	builder.PrependUint32Slot(3, tick, 0)
+}
+func TimeSyncReplyEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
@@ -0,0 +1,90 @@
+package types
+
+import "time"
+
+// Latency keeps the connection quality of a player, measured with Ping/Pong. Only one ping
+// is in flight at a time, a ping that is still unanswered when the next one is sent counts
+// as a missed pong.
+type Latency struct {
+	RTT    time.Duration
+	Jitter time.Duration
+
+	PingsSent   int
+	MissedPongs int
+	// MissedInARow is reset by every pong, it's what tells a slow connection from a dead one.
+	MissedInARow int
+	LastPong     time.Time
+
+	pendingSeq    uint32
+	pendingSentAt time.Time
+	pending       bool
+}
+
+// PingSent records a new ping that was written now, see PingQueued.
+func (l *Latency) PingSent(seq uint32, now time.Time) {
+	l.PingQueued(seq)
+	l.PingWritten(now)
+}
+
+// PingQueued records a new ping that isn't written yet, the previous one is counted as lost if
+// it wasn't answered.
+func (l *Latency) PingQueued(seq uint32) {
+	if l.pending {
+		l.MissedPongs++
+		l.MissedInARow++
+	}
+
+	l.PingsSent++
+	l.pendingSeq = seq
+	l.pendingSentAt = time.Time{}
+	l.pending = true
+}
+
+// PingWritten sets when the ping in flight was written, the RTT is measured from there. Only
+// the first write after the ping was queued counts.
+func (l *Latency) PingWritten(now time.Time) {
+	if l.pending && l.pendingSentAt.IsZero() {
+		l.pendingSentAt = now
+	}
+}
+
+// PongReceived updates RTT and jitter if seq answers the ping in flight. Late or unknown
+// pongs, and pongs to a ping that was never written, are ignored and false is returned.
+func (l *Latency) PongReceived(seq uint32, now time.Time) bool {
+	if !l.pending || seq != l.pendingSeq || l.pendingSentAt.IsZero() || now.Before(l.pendingSentAt) {
+		return false
+	}
+
+	rtt := now.Sub(l.pendingSentAt)
+
+	if l.LastPong.IsZero() {
+		l.RTT = rtt
+	} else {
+		// Same smoothing as TCP for the RTT and RTP (RFC 3550) for the jitter
+		l.Jitter += (abs(rtt-l.RTT) - l.Jitter) / 16
+		l.RTT += (rtt - l.RTT) / 8
+	}
+
+	l.pending = false
+	l.MissedInARow = 0
+	l.LastPong = now
+
+	return true
+}
+
+// PacketLoss is the fraction of pings that never got an answer.
+func (l *Latency) PacketLoss() float64 {
+	if l.PingsSent == 0 {
+		return 0
+	}
+
+	return float64(l.MissedPongs) / float64(l.PingsSent)
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
@@ -0,0 +1,86 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+)
+
+func TestLatencyRTTAndJitter(t *testing.T) {
+	latency := types.Latency{}
+	now := time.Now()
+
+	for seq, rtt := range []time.Duration{50, 50, 50, 150} {
+		latency.PingSent(uint32(seq), now)
+		now = now.Add(rtt * time.Millisecond)
+
+		if !latency.PongReceived(uint32(seq), now) {
+			t.Fatalf("pong %v was not matched", seq)
+		}
+	}
+
+	if latency.RTT <= 50*time.Millisecond || latency.RTT >= 150*time.Millisecond {
+		t.Errorf("RTT should be smoothed, got %v", latency.RTT)
+	}
+
+	if latency.Jitter == 0 {
+		t.Errorf("expected some jitter after a late pong")
+	}
+
+	if latency.MissedPongs != 0 || latency.PacketLoss() != 0 {
+		t.Errorf("no pong was missed, got %v (%v)", latency.MissedPongs, latency.PacketLoss())
+	}
+}
+
+func TestLatencyMissedPongs(t *testing.T) {
+	latency := types.Latency{}
+	now := time.Now()
+
+	latency.PingSent(1, now)
+	latency.PingSent(2, now)
+	latency.PingSent(3, now)
+
+	if latency.MissedInARow != 2 || latency.MissedPongs != 2 {
+		t.Fatalf("expected 2 missed pongs, got %v in a row, %v total", latency.MissedInARow, latency.MissedPongs)
+	}
+
+	if latency.PongReceived(1, now) {
+		t.Errorf("a late pong shouldn't be matched")
+	}
+
+	if !latency.PongReceived(3, now.Add(time.Millisecond)) {
+		t.Errorf("pong 3 should be matched")
+	}
+
+	if latency.MissedInARow != 0 || latency.MissedPongs != 2 {
+		t.Errorf("a pong resets only the missed in a row count, got %v in a row, %v total", latency.MissedInARow, latency.MissedPongs)
+	}
+
+	if loss := latency.PacketLoss(); loss < 0.66 || loss > 0.67 {
+		t.Errorf("expected 2/3 loss, got %v", loss)
+	}
+}
+
+func TestLatencyCountsFromTheWrite(t *testing.T) {
+	latency := types.Latency{}
+	now := time.Now()
+
+	latency.PingQueued(1)
+
+	if latency.PongReceived(1, now) {
+		t.Fatalf("a pong to a ping that wasn't written shouldn't be matched")
+	}
+
+	latency.PingWritten(now.Add(20 * time.Millisecond))
+	// only the frame that carried the ping counts
+	latency.PingWritten(now.Add(40 * time.Millisecond))
+
+	if !latency.PongReceived(1, now.Add(50*time.Millisecond)) {
+		t.Fatalf("pong 1 should be matched")
+	}
+
+	if latency.RTT != 30*time.Millisecond {
+		t.Errorf("expected the RTT from the write, 30ms, got %v", latency.RTT)
+	}
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"math"
+
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+)
+
+// PositionCodec converts the server positions, which are kept as float64, to the int32
+// fields of the Player struct on the wire.
+//
+//   - Integer truncates to whole units, it's what clients get if they don't ask for anything.
+//   - FixedPoint multiplies by Scale before rounding, so players move smoothly between units.
+type PositionCodec struct {
+	Encoding flatgen.PositionEncoding
+	Scale    float64
+}
+
+var IntegerCodec = PositionCodec{Encoding: flatgen.PositionEncodingInteger, Scale: 1}
+
+func (pc PositionCodec) Encode(x, y float64) (int32, int32) {
+	return pc.encodeAxis(x), pc.encodeAxis(y)
+}
+
+func (pc PositionCodec) Decode(x, y int32) (float64, float64) {
+	return pc.decodeAxis(x), pc.decodeAxis(y)
+}
+
+func (pc PositionCodec) encodeAxis(value float64) int32 {
+	if pc.Encoding == flatgen.PositionEncodingFixedPoint {
+		return int32(math.Round(value * pc.Scale))
+	}
+
+	return int32(value)
+}
+
+func (pc PositionCodec) decodeAxis(value int32) float64 {
+	if pc.Encoding == flatgen.PositionEncodingFixedPoint {
+		return float64(value) / pc.Scale
+	}
+
+	return float64(value)
+}
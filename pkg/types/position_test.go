@@ -0,0 +1,32 @@
+package types_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+)
+
+func TestPositionCodecRoundTrip(t *testing.T) {
+	testCases := []struct {
+		codec     types.PositionCodec
+		tolerance float64
+	}{
+		{types.IntegerCodec, 1},
+		{types.PositionCodec{Encoding: flatgen.PositionEncodingFixedPoint, Scale: 100}, 0.005},
+	}
+
+	positions := [][2]float64{{0, 0}, {12.345, 678.9}, {1599.99, 1199.99}, {800.5, 0.25}}
+
+	for _, tc := range testCases {
+		for _, position := range positions {
+			encodedX, encodedY := tc.codec.Encode(position[0], position[1])
+			x, y := tc.codec.Decode(encodedX, encodedY)
+
+			if math.Abs(x-position[0]) > tc.tolerance || math.Abs(y-position[1]) > tc.tolerance {
+				t.Errorf("%v: %v decoded as (%v, %v)", tc.codec.Encoding, position, x, y)
+			}
+		}
+	}
+}
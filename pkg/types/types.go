@@ -1,38 +1,80 @@
 package types
 
 import (
+	"time"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/transport"
 	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
 
-	"github.com/coder/websocket"
 	flatbuffers "github.com/google/flatbuffers/go"
 )
 
 type Player struct {
-	Id          int
-	X, Y        float64
-	Speed       float64
-	MovingLeft  bool
-	MovingRight bool
-	MovingUp    bool
-	MovingDown  bool
+	Id    int
+	X, Y  float64
+	Speed float64
+	Team  int
+	// InputX and InputY are the direction the player wants to move in, each in [-1, 1].
+	InputX, InputY float64
+	VX, VY         float64
+	Health         int
+	State          PlayerState
 }
 
+// PlayerState is whether the player is in the game or dead and waiting to respawn, players
+// start alive.
+type PlayerState uint8
+
+const (
+	Alive PlayerState = iota
+	Dead
+)
+
 type PlayerWithSocket struct {
 	Player
-	Conn *websocket.Conn
+	Conn    transport.Conn
+	Codec   PositionCodec
+	Latency Latency
+	// Compression is how the EventLists sent to the player are compressed, on top of the
+	// permessage-deflate the websocket may have negotiated.
+	Compression flatgen.Compression
+
+	// LastInput is the last time the player sent a PlayerMoved, LastWrite the last time a
+	// write to their connection succeeded.
+	LastInput  time.Time
+	LastWrite  time.Time
+	IdleWarned bool
+	// LastFire is the last time the player fired a projectile.
+	LastFire time.Time
+	// RespawnAt is when a dead player comes back.
+	RespawnAt time.Time
+	// Spectators stay connected and keep receiving events but are not part of the world.
+	Spectator bool
+	// Profile is the key the client asked the player to be remembered by between connections,
+	// empty when it didn't.
+	Profile string
 }
 
 type Event struct {
 	Kind     flatgen.EventKind
 	PlayerId int
-	Conn     *websocket.Conn
+	Conn     transport.Conn
 	Data     any
+	// Lifecycle events are queued by the server when a connection opens or closes, their Data
+	// is a Go struct. Every other event was read from the wire and its Data is a flatbuffer.
+	Lifecycle bool
+	// ReceivedAt is when the read loop got the event, it can wait in the queue for a while
+	// before a tick handles it.
+	ReceivedAt time.Time
 }
 
-type PlayerHello struct {
-	Kind flatgen.EventKind
-	Id   int
-}
+// PlayerConnected is the lifecycle event of a new connection, it's queued with the
+// PlayerHello kind.
+type PlayerConnected struct{}
+
+// PlayerDisconnected is the lifecycle event of a closed connection, it's queued with the
+// PlayerQuit kind.
+type PlayerDisconnected struct{}
 
 // EventHolder is used for when we send events to users, we care only for the bytes and kind
 type EventHolder interface {
@@ -5,8 +5,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/dispatch"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
 	. "github.com/laurentiuNiculae/multiplayer-game/pkg/types"
 	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/verify"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
 
 	flatbuffers "github.com/google/flatbuffers/go"
 )
@@ -25,19 +29,58 @@ func NewEventHolder(kind flatgen.EventKind, event any) EventHolder {
 	return &EmptyEvent{}
 }
 
-func NewFlatPlayerHello(builder *flatbuffers.Builder, newPlayer Player) *flatgen.PlayerHello {
+func NewFlatPlayerHello(builder *flatbuffers.Builder, newPlayer Player, encodings []flatgen.PositionEncoding, fixedPointScale int,
+	compressions []flatgen.Compression, dictionaryVersion int, profile string,
+) *flatgen.PlayerHello {
+	var flatProfile flatbuffers.UOffsetT
+	if profile != "" {
+		flatProfile = builder.CreateString(profile)
+	}
+
+	flatgen.PlayerHelloStartPositionEncodingsVector(builder, len(encodings))
+	for i := len(encodings) - 1; i >= 0; i-- {
+		builder.PrependByte(byte(encodings[i]))
+	}
+	encodingsVecOffset := builder.EndVector(len(encodings))
+
+	flatgen.PlayerHelloStartCompressionsVector(builder, len(compressions))
+	for i := len(compressions) - 1; i >= 0; i-- {
+		builder.PrependByte(byte(compressions[i]))
+	}
+	compressionsVecOffset := builder.EndVector(len(compressions))
+
 	flatgen.PlayerHelloStart(builder)
 	flatgen.PlayerHelloAddId(builder, int32(newPlayer.Id))
 	flatgen.PlayerHelloAddKind(builder, flatgen.EventKindPlayerHello)
+	flatgen.PlayerHelloAddPositionEncodings(builder, encodingsVecOffset)
+	flatgen.PlayerHelloAddFixedPointScale(builder, int32(fixedPointScale))
+	flatgen.PlayerHelloAddCompressions(builder, compressionsVecOffset)
+	flatgen.PlayerHelloAddDictionaryVersion(builder, int32(dictionaryVersion))
+	if profile != "" {
+		flatgen.PlayerHelloAddProfile(builder, flatProfile)
+	}
 	flatgen.FinishPlayerHelloBuffer(builder, flatgen.PlayerHelloEnd(builder))
 
 	return flatgen.GetRootAsPlayerHello(builder.FinishedBytes(), 0)
 }
 
-func NewFlatPlayerHelloConfirm(builder *flatbuffers.Builder, id int) *flatgen.PlayerHelloConfirm {
+func NewFlatPlayerHelloConfirm(builder *flatbuffers.Builder, id int, encoding flatgen.PositionEncoding, compression flatgen.Compression,
+	dictionaryVersion int, profile string,
+) *flatgen.PlayerHelloConfirm {
+	var flatProfile flatbuffers.UOffsetT
+	if profile != "" {
+		flatProfile = builder.CreateString(profile)
+	}
+
 	flatgen.PlayerHelloConfirmStart(builder)
 	flatgen.PlayerHelloConfirmAddId(builder, int32(id))
 	flatgen.PlayerHelloConfirmAddKind(builder, flatgen.EventKindPlayerHelloConfirm)
+	flatgen.PlayerHelloConfirmAddPositionEncoding(builder, encoding)
+	flatgen.PlayerHelloConfirmAddCompression(builder, compression)
+	flatgen.PlayerHelloConfirmAddDictionaryVersion(builder, int32(dictionaryVersion))
+	if profile != "" {
+		flatgen.PlayerHelloConfirmAddProfile(builder, flatProfile)
+	}
 	flatgen.FinishPlayerHelloConfirmBuffer(builder, flatgen.PlayerHelloConfirmEnd(builder))
 
 	return flatgen.GetRootAsPlayerHelloConfirm(builder.FinishedBytes(), 0)
@@ -52,8 +95,56 @@ func NewFlatPlayerQuit(builder *flatbuffers.Builder, playerId int) *flatgen.Play
 	return flatgen.GetRootAsPlayerQuit(builder.FinishedBytes(), 0)
 }
 
-func NewFlatPlayerJoined(builder *flatbuffers.Builder, newPlayer Player) *flatgen.PlayerJoined {
-	flatPlayer := NewFlatPlayer(builder, newPlayer)
+func NewFlatTimeSync(builder *flatbuffers.Builder, clientTime float64) *flatgen.TimeSync {
+	flatgen.TimeSyncStart(builder)
+	flatgen.TimeSyncAddKind(builder, flatgen.EventKindTimeSync)
+	flatgen.TimeSyncAddClientTime(builder, clientTime)
+	flatgen.FinishTimeSyncBuffer(builder, flatgen.TimeSyncEnd(builder))
+
+	return flatgen.GetRootAsTimeSync(builder.FinishedBytes(), 0)
+}
+
+func NewFlatTimeSyncReply(builder *flatbuffers.Builder, clientTime float64, serverTime int64, tick uint32) *flatgen.TimeSyncReply {
+	flatgen.TimeSyncReplyStart(builder)
+	flatgen.TimeSyncReplyAddKind(builder, flatgen.EventKindTimeSyncReply)
+	flatgen.TimeSyncReplyAddClientTime(builder, clientTime)
+	flatgen.TimeSyncReplyAddServerTime(builder, serverTime)
+	flatgen.TimeSyncReplyAddTick(builder, tick)
+	flatgen.FinishTimeSyncReplyBuffer(builder, flatgen.TimeSyncReplyEnd(builder))
+
+	return flatgen.GetRootAsTimeSyncReply(builder.FinishedBytes(), 0)
+}
+
+func NewFlatPing(builder *flatbuffers.Builder, seq uint32) *flatgen.Ping {
+	flatgen.PingStart(builder)
+	flatgen.PingAddKind(builder, flatgen.EventKindPing)
+	flatgen.PingAddSeq(builder, seq)
+	flatgen.FinishPingBuffer(builder, flatgen.PingEnd(builder))
+
+	return flatgen.GetRootAsPing(builder.FinishedBytes(), 0)
+}
+
+func NewFlatPong(builder *flatbuffers.Builder, seq uint32) *flatgen.Pong {
+	flatgen.PongStart(builder)
+	flatgen.PongAddKind(builder, flatgen.EventKindPong)
+	flatgen.PongAddSeq(builder, seq)
+	flatgen.FinishPongBuffer(builder, flatgen.PongEnd(builder))
+
+	return flatgen.GetRootAsPong(builder.FinishedBytes(), 0)
+}
+
+func NewFlatIdleWarning(builder *flatbuffers.Builder, action flatgen.IdleAction, secondsLeft float32) *flatgen.IdleWarning {
+	flatgen.IdleWarningStart(builder)
+	flatgen.IdleWarningAddKind(builder, flatgen.EventKindIdleWarning)
+	flatgen.IdleWarningAddAction(builder, action)
+	flatgen.IdleWarningAddSecondsLeft(builder, secondsLeft)
+	flatgen.FinishIdleWarningBuffer(builder, flatgen.IdleWarningEnd(builder))
+
+	return flatgen.GetRootAsIdleWarning(builder.FinishedBytes(), 0)
+}
+
+func NewFlatPlayerJoined(builder *flatbuffers.Builder, newPlayer Player, codec PositionCodec) *flatgen.PlayerJoined {
+	flatPlayer := NewFlatPlayer(builder, newPlayer, codec)
 
 	flatgen.PlayerJoinedStart(builder)
 	flatgen.PlayerJoinedAddPlayer(builder, flatPlayer)
@@ -63,8 +154,8 @@ func NewFlatPlayerJoined(builder *flatbuffers.Builder, newPlayer Player) *flatge
 	return flatgen.GetRootAsPlayerJoined(builder.FinishedBytes(), 0)
 }
 
-func NewFlatPlayerMoved(builder *flatbuffers.Builder, newPlayer Player) *flatgen.PlayerMoved {
-	flatPlayer := NewFlatPlayer(builder, newPlayer)
+func NewFlatPlayerMoved(builder *flatbuffers.Builder, newPlayer Player, codec PositionCodec) *flatgen.PlayerMoved {
+	flatPlayer := NewFlatPlayer(builder, newPlayer, codec)
 
 	flatgen.PlayerMovedStart(builder)
 	flatgen.PlayerMovedAddPlayer(builder, flatPlayer)
@@ -74,10 +165,10 @@ func NewFlatPlayerMoved(builder *flatbuffers.Builder, newPlayer Player) *flatgen
 	return flatgen.GetRootAsPlayerMoved(builder.FinishedBytes(), 0)
 }
 
-func NewFlatPlayerMovedList(builder *flatbuffers.Builder, movingPlayers []*flatgen.PlayerMoved) *flatgen.PlayerMovedList {
+func NewFlatPlayerMovedList(builder *flatbuffers.Builder, movingPlayers []Player, codec PositionCodec) *flatgen.PlayerMovedList {
 	flatgen.PlayerMovedListStartPlayersVector(builder, len(movingPlayers))
 	for i := range movingPlayers {
-		NewFlatPlayerFromFlat(builder, movingPlayers[i].Player(nil))
+		NewFlatPlayer(builder, movingPlayers[i], codec)
 	}
 	movingPlayersVecOffset := builder.EndVector(len(movingPlayers))
 
@@ -89,10 +180,10 @@ func NewFlatPlayerMovedList(builder *flatbuffers.Builder, movingPlayers []*flatg
 	return flatgen.GetRootAsPlayerMovedList(builder.FinishedBytes(), 0)
 }
 
-func NewFlatPlayerJoinedList(builder *flatbuffers.Builder, joinedPlayers []Player) *flatgen.PlayerJoinedList {
+func NewFlatPlayerJoinedList(builder *flatbuffers.Builder, joinedPlayers []Player, codec PositionCodec) *flatgen.PlayerJoinedList {
 	flatgen.PlayerJoinedListStartPlayersVector(builder, len(joinedPlayers))
 	for i := range joinedPlayers {
-		NewFlatPlayer(builder, joinedPlayers[i])
+		NewFlatPlayer(builder, joinedPlayers[i], codec)
 	}
 	movingPlayersVecOffset := builder.EndVector(len(joinedPlayers))
 
@@ -104,16 +195,79 @@ func NewFlatPlayerJoinedList(builder *flatbuffers.Builder, joinedPlayers []Playe
 	return flatgen.GetRootAsPlayerJoinedList(builder.FinishedBytes(), 0)
 }
 
-func NewFlatPlayer(builder *flatbuffers.Builder, newPlayer Player) flatbuffers.UOffsetT {
+func NewFlatWorldMap(builder *flatbuffers.Builder, worldMap *world.Map) *flatgen.WorldMap {
+	spawnZoneOffsets := newFlatMapRegions(builder, worldMap.SpawnZones)
+	regionOffsets := newFlatMapRegions(builder, worldMap.Regions)
+
+	flatgen.WorldMapStartSpawnZonesVector(builder, len(spawnZoneOffsets))
+	for i := len(spawnZoneOffsets) - 1; i >= 0; i-- {
+		builder.PrependUOffsetT(spawnZoneOffsets[i])
+	}
+	spawnZonesVecOffset := builder.EndVector(len(spawnZoneOffsets))
+
+	flatgen.WorldMapStartRegionsVector(builder, len(regionOffsets))
+	for i := len(regionOffsets) - 1; i >= 0; i-- {
+		builder.PrependUOffsetT(regionOffsets[i])
+	}
+	regionsVecOffset := builder.EndVector(len(regionOffsets))
+
+	flatgen.WorldMapStartWallsVector(builder, len(worldMap.Walls))
+	for i := len(worldMap.Walls) - 1; i >= 0; i-- {
+		NewFlatRect(builder, worldMap.Walls[i])
+	}
+	wallsVecOffset := builder.EndVector(len(worldMap.Walls))
+
+	nameOffset := builder.CreateString(worldMap.Name)
+
+	flatgen.WorldMapStart(builder)
+	flatgen.WorldMapAddKind(builder, flatgen.EventKindWorldMap)
+	flatgen.WorldMapAddName(builder, nameOffset)
+	flatgen.WorldMapAddWidth(builder, float32(worldMap.Width))
+	flatgen.WorldMapAddHeight(builder, float32(worldMap.Height))
+	flatgen.WorldMapAddWalls(builder, wallsVecOffset)
+	flatgen.WorldMapAddSpawnZones(builder, spawnZonesVecOffset)
+	flatgen.WorldMapAddRegions(builder, regionsVecOffset)
+	flatgen.FinishWorldMapBuffer(builder, flatgen.WorldMapEnd(builder))
+
+	return flatgen.GetRootAsWorldMap(builder.FinishedBytes(), 0)
+}
+
+func newFlatMapRegions(builder *flatbuffers.Builder, regions []world.Region) []flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(regions))
+
+	for i := range regions {
+		nameOffset := builder.CreateString(regions[i].Name)
+
+		flatgen.MapRegionStart(builder)
+		flatgen.MapRegionAddName(builder, nameOffset)
+		flatgen.MapRegionAddArea(builder, NewFlatRect(builder, regions[i].Area))
+		offsets[i] = flatgen.MapRegionEnd(builder)
+	}
+
+	return offsets
+}
+
+func NewFlatRect(builder *flatbuffers.Builder, rect world.Rect) flatbuffers.UOffsetT {
+	return flatgen.CreateRect(builder,
+		float32(rect.X),
+		float32(rect.Y),
+		float32(rect.Width),
+		float32(rect.Height),
+	)
+}
+
+func NewFlatPlayer(builder *flatbuffers.Builder, newPlayer Player, codec PositionCodec) flatbuffers.UOffsetT {
+	x, y := codec.Encode(newPlayer.X, newPlayer.Y)
+
 	return flatgen.CreatePlayer(builder,
 		int32(newPlayer.Id),
-		int32(newPlayer.X),
-		int32(newPlayer.Y),
+		x,
+		y,
 		int32(newPlayer.Speed),
-		newPlayer.MovingLeft,
-		newPlayer.MovingRight,
-		newPlayer.MovingUp,
-		newPlayer.MovingDown,
+		float32(newPlayer.InputX),
+		float32(newPlayer.InputY),
+		float32(newPlayer.VX),
+		float32(newPlayer.VY),
 	)
 }
 
@@ -123,58 +277,198 @@ func NewFlatPlayerFromFlat(builder *flatbuffers.Builder, newPlayer *flatgen.Play
 		int32(newPlayer.X()),
 		int32(newPlayer.Y()),
 		int32(newPlayer.Speed()),
-		newPlayer.MovingLeft(),
-		newPlayer.MovingRight(),
-		newPlayer.MovingUp(),
-		newPlayer.MovingDown(),
+		newPlayer.InputX(),
+		newPlayer.InputY(),
+		newPlayer.Vx(),
+		newPlayer.Vy(),
 	)
 }
 
-func ParseEventBytes(data []byte) (eventKind flatgen.EventKind, eventData any, err error) {
-	kindHolder := flatgen.GetRootAsKindHolder(data, 0)
+func NewFlatEntity(builder *flatbuffers.Builder, entity ecs.State, codec PositionCodec) flatbuffers.UOffsetT {
+	x, y := codec.Encode(entity.X, entity.Y)
+
+	return flatgen.CreateEntity(builder,
+		int32(entity.Id),
+		entity.Kind,
+		int32(entity.Owner),
+		x,
+		y,
+		float32(entity.VX),
+		float32(entity.VY),
+		float32(entity.Size),
+	)
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("was panic, returned panic value '%v'", r)
-		}
-	}()
+func newFlatEntities(builder *flatbuffers.Builder, startVector func(*flatbuffers.Builder, int) flatbuffers.UOffsetT,
+	entities []ecs.State, codec PositionCodec,
+) flatbuffers.UOffsetT {
+	startVector(builder, len(entities))
+	for i := len(entities) - 1; i >= 0; i-- {
+		NewFlatEntity(builder, entities[i], codec)
+	}
+
+	return builder.EndVector(len(entities))
+}
+
+func NewFlatEntitySpawned(builder *flatbuffers.Builder, entities []ecs.State, codec PositionCodec) *flatgen.EntitySpawned {
+	entitiesVecOffset := newFlatEntities(builder, flatgen.EntitySpawnedStartEntitiesVector, entities, codec)
 
-	eventKind = kindHolder.Kind()
+	flatgen.EntitySpawnedStart(builder)
+	flatgen.EntitySpawnedAddKind(builder, flatgen.EventKindEntitySpawned)
+	flatgen.EntitySpawnedAddEntities(builder, entitiesVecOffset)
+	flatgen.FinishEntitySpawnedBuffer(builder, flatgen.EntitySpawnedEnd(builder))
 
-	switch kindHolder.Kind() {
-	case flatgen.EventKindPlayerHello:
-		flatPlayerHello := flatgen.GetRootAsPlayerHello(data, 0)
+	return flatgen.GetRootAsEntitySpawned(builder.FinishedBytes(), 0)
+}
 
-		return eventKind, flatPlayerHello, nil
-	case flatgen.EventKindPlayerHelloConfirm:
-		flatPlayerHelloConfirm := flatgen.GetRootAsPlayerHelloConfirm(data, 0)
+func NewFlatEntityUpdated(builder *flatbuffers.Builder, entities []ecs.State, codec PositionCodec) *flatgen.EntityUpdated {
+	entitiesVecOffset := newFlatEntities(builder, flatgen.EntityUpdatedStartEntitiesVector, entities, codec)
 
-		return eventKind, flatPlayerHelloConfirm, nil
-	case flatgen.EventKindPlayerQuit:
-		flatPlayerQuit := flatgen.GetRootAsPlayerQuit(data, 0)
+	flatgen.EntityUpdatedStart(builder)
+	flatgen.EntityUpdatedAddKind(builder, flatgen.EventKindEntityUpdated)
+	flatgen.EntityUpdatedAddEntities(builder, entitiesVecOffset)
+	flatgen.FinishEntityUpdatedBuffer(builder, flatgen.EntityUpdatedEnd(builder))
 
-		return eventKind, flatPlayerQuit, nil
-	case flatgen.EventKindPlayerJoined:
-		flatPlayerJoined := flatgen.GetRootAsPlayerJoined(data, 0)
+	return flatgen.GetRootAsEntityUpdated(builder.FinishedBytes(), 0)
+}
 
-		return eventKind, flatPlayerJoined, nil
-	case flatgen.EventKindPlayerJoinedList:
-		flatPlayerJoinedList := flatgen.GetRootAsPlayerJoinedList(data, 0)
+func NewFlatEntityDespawned(builder *flatbuffers.Builder, ids []int) *flatgen.EntityDespawned {
+	flatgen.EntityDespawnedStartIdsVector(builder, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		builder.PrependInt32(int32(ids[i]))
+	}
+	idsVecOffset := builder.EndVector(len(ids))
 
-		return eventKind, flatPlayerJoinedList, nil
-	case flatgen.EventKindPlayerMoved:
-		flatPlayerMoved := flatgen.GetRootAsPlayerMoved(data, 0)
+	flatgen.EntityDespawnedStart(builder)
+	flatgen.EntityDespawnedAddKind(builder, flatgen.EventKindEntityDespawned)
+	flatgen.EntityDespawnedAddIds(builder, idsVecOffset)
+	flatgen.FinishEntityDespawnedBuffer(builder, flatgen.EntityDespawnedEnd(builder))
 
-		return eventKind, flatPlayerMoved, nil
-	case flatgen.EventKindPlayerMovedList:
-		flatPlayerMovedList := flatgen.GetRootAsPlayerMovedList(data, 0)
+	return flatgen.GetRootAsEntityDespawned(builder.FinishedBytes(), 0)
+}
 
-		return eventKind, flatPlayerMovedList, nil
-	default:
-		return 0, nil, fmt.Errorf("ERROR: bogus-amogus kind '%s'", flatgen.EnumNamesEventKind[kindHolder.Kind()])
+func NewFlatPlayerFire(builder *flatbuffers.Builder, dirX, dirY float64) *flatgen.PlayerFire {
+	flatgen.PlayerFireStart(builder)
+	flatgen.PlayerFireAddKind(builder, flatgen.EventKindPlayerFire)
+	flatgen.PlayerFireAddDirX(builder, float32(dirX))
+	flatgen.PlayerFireAddDirY(builder, float32(dirY))
+	flatgen.FinishPlayerFireBuffer(builder, flatgen.PlayerFireEnd(builder))
+
+	return flatgen.GetRootAsPlayerFire(builder.FinishedBytes(), 0)
+}
+
+func NewFlatPlayerDamaged(builder *flatbuffers.Builder, playerId, attacker, damage, health int) *flatgen.PlayerDamaged {
+	flatgen.PlayerDamagedStart(builder)
+	flatgen.PlayerDamagedAddKind(builder, flatgen.EventKindPlayerDamaged)
+	flatgen.PlayerDamagedAddId(builder, int32(playerId))
+	flatgen.PlayerDamagedAddAttacker(builder, int32(attacker))
+	flatgen.PlayerDamagedAddDamage(builder, int32(damage))
+	flatgen.PlayerDamagedAddHealth(builder, int32(health))
+	flatgen.FinishPlayerDamagedBuffer(builder, flatgen.PlayerDamagedEnd(builder))
+
+	return flatgen.GetRootAsPlayerDamaged(builder.FinishedBytes(), 0)
+}
+
+func NewFlatPlayerDied(builder *flatbuffers.Builder, playerId, killer int, respawnIn float32) *flatgen.PlayerDied {
+	flatgen.PlayerDiedStart(builder)
+	flatgen.PlayerDiedAddKind(builder, flatgen.EventKindPlayerDied)
+	flatgen.PlayerDiedAddId(builder, int32(playerId))
+	flatgen.PlayerDiedAddKiller(builder, int32(killer))
+	flatgen.PlayerDiedAddRespawnIn(builder, respawnIn)
+	flatgen.FinishPlayerDiedBuffer(builder, flatgen.PlayerDiedEnd(builder))
+
+	return flatgen.GetRootAsPlayerDied(builder.FinishedBytes(), 0)
+}
+
+func NewFlatPlayerRespawned(builder *flatbuffers.Builder, player Player, health int, codec PositionCodec) *flatgen.PlayerRespawned {
+	flatgen.PlayerRespawnedStart(builder)
+	flatgen.PlayerRespawnedAddKind(builder, flatgen.EventKindPlayerRespawned)
+	flatgen.PlayerRespawnedAddPlayer(builder, NewFlatPlayer(builder, player, codec))
+	flatgen.PlayerRespawnedAddHealth(builder, int32(health))
+	flatgen.FinishPlayerRespawnedBuffer(builder, flatgen.PlayerRespawnedEnd(builder))
+
+	return flatgen.GetRootAsPlayerRespawned(builder.FinishedBytes(), 0)
+}
+
+// RegisterFlatDecoders adds the decoder of every flatbuffer event to the registry. Buffers
+// are verified before being decoded, so the accessors of a decoded event never go out of bounds.
+func RegisterFlatDecoders(registry *dispatch.Registry) {
+	RegisterClientDecoders(registry)
+	RegisterServerDecoders(registry)
+}
+
+// RegisterClientDecoders adds the decoders of the events clients send to the server, the only
+// ones the server parses. The rest reach its registry as raw bytes, see dispatch.Registry.Parse.
+func RegisterClientDecoders(registry *dispatch.Registry) {
+	verifyKind(registry)
+
+	registry.Decode(flatgen.EventKindPlayerHelloConfirm, verified(flatgen.EventKindPlayerHelloConfirm, flatgen.GetRootAsPlayerHelloConfirm))
+	registry.Decode(flatgen.EventKindPlayerMoved, verified(flatgen.EventKindPlayerMoved, flatgen.GetRootAsPlayerMoved))
+	registry.Decode(flatgen.EventKindTimeSync, verified(flatgen.EventKindTimeSync, flatgen.GetRootAsTimeSync))
+	registry.Decode(flatgen.EventKindPong, verified(flatgen.EventKindPong, flatgen.GetRootAsPong))
+	registry.Decode(flatgen.EventKindPlayerFire, verified(flatgen.EventKindPlayerFire, flatgen.GetRootAsPlayerFire))
+}
+
+// RegisterServerDecoders adds the decoders of the events the server sends to its clients.
+func RegisterServerDecoders(registry *dispatch.Registry) {
+	verifyKind(registry)
+
+	registry.Decode(flatgen.EventKindPlayerHello, verified(flatgen.EventKindPlayerHello, flatgen.GetRootAsPlayerHello))
+	registry.Decode(flatgen.EventKindPlayerQuit, verified(flatgen.EventKindPlayerQuit, flatgen.GetRootAsPlayerQuit))
+	registry.Decode(flatgen.EventKindPlayerJoined, verified(flatgen.EventKindPlayerJoined, flatgen.GetRootAsPlayerJoined))
+	registry.Decode(flatgen.EventKindPlayerJoinedList, verified(flatgen.EventKindPlayerJoinedList, flatgen.GetRootAsPlayerJoinedList))
+	registry.Decode(flatgen.EventKindPlayerMovedList, verified(flatgen.EventKindPlayerMovedList, flatgen.GetRootAsPlayerMovedList))
+	registry.Decode(flatgen.EventKindWorldMap, verified(flatgen.EventKindWorldMap, flatgen.GetRootAsWorldMap))
+	registry.Decode(flatgen.EventKindTimeSyncReply, verified(flatgen.EventKindTimeSyncReply, flatgen.GetRootAsTimeSyncReply))
+	registry.Decode(flatgen.EventKindPing, verified(flatgen.EventKindPing, flatgen.GetRootAsPing))
+	registry.Decode(flatgen.EventKindIdleWarning, verified(flatgen.EventKindIdleWarning, flatgen.GetRootAsIdleWarning))
+	registry.Decode(flatgen.EventKindEntitySpawned, verified(flatgen.EventKindEntitySpawned, flatgen.GetRootAsEntitySpawned))
+	registry.Decode(flatgen.EventKindEntityUpdated, verified(flatgen.EventKindEntityUpdated, flatgen.GetRootAsEntityUpdated))
+	registry.Decode(flatgen.EventKindEntityDespawned, verified(flatgen.EventKindEntityDespawned, flatgen.GetRootAsEntityDespawned))
+	registry.Decode(flatgen.EventKindPlayerDamaged, verified(flatgen.EventKindPlayerDamaged, flatgen.GetRootAsPlayerDamaged))
+	registry.Decode(flatgen.EventKindPlayerDied, verified(flatgen.EventKindPlayerDied, flatgen.GetRootAsPlayerDied))
+	registry.Decode(flatgen.EventKindPlayerRespawned, verified(flatgen.EventKindPlayerRespawned, flatgen.GetRootAsPlayerRespawned))
+}
+
+func verifyKind(registry *dispatch.Registry) {
+	registry.VerifyKind(func(data []byte) error {
+		return verify.Root(data, verify.KindHolder)
+	})
+}
+
+func verified[T any](kind flatgen.EventKind, getRoot func(buf []byte, offset flatbuffers.UOffsetT) T) dispatch.Decoder {
+	decode := dispatch.Flat(getRoot)
+
+	return func(data []byte) (any, error) {
+		if err := verify.Event(kind, data); err != nil {
+			return nil, err
+		}
+
+		return decode(data)
 	}
 }
 
+var flatDecoders = func() *dispatch.Registry {
+	registry := dispatch.NewRegistry()
+	RegisterFlatDecoders(registry)
+
+	return registry
+}()
+
+// ParseEventBytes decodes any flatbuffer event, unknown kinds are an error.
+func ParseEventBytes(data []byte) (eventKind flatgen.EventKind, eventData any, err error) {
+	eventKind, eventData, err = flatDecoders.Parse(data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !flatDecoders.HasDecoder(eventKind) {
+		return 0, nil, fmt.Errorf("ERROR: bogus-amogus kind '%s'", flatgen.EnumNamesEventKind[eventKind])
+	}
+
+	return eventKind, eventData, nil
+}
+
 func WaitServerIsReady(url string) {
 	for {
 		_, err := http.Get(url)
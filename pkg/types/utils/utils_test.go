@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func BenchmarkNewFlatPlayerMovedList(b *testing.B) {
+	codecs := []struct {
+		name  string
+		codec types.PositionCodec
+	}{
+		{"integer", types.IntegerCodec},
+		{"fixedpoint", types.PositionCodec{Encoding: flatgen.PositionEncodingFixedPoint, Scale: 100}},
+	}
+
+	for _, count := range []int{10, 100, 1000} {
+		players := make([]types.Player, count)
+		for i := range players {
+			players[i] = types.Player{Id: i + 1, X: float64(i % 1600), Y: float64(i % 1200), Speed: 250, InputX: 1}
+		}
+
+		for _, c := range codecs {
+			b.Run(fmt.Sprintf("players=%v/%v", count, c.name), func(b *testing.B) {
+				builder := flatbuffers.NewBuilder(1024)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for range b.N {
+					builder.Reset()
+					utils.NewFlatPlayerMovedList(builder, players, c.codec)
+				}
+			})
+		}
+	}
+}
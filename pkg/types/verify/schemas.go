@@ -0,0 +1,194 @@
+package verify
+
+import (
+	"fmt"
+
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+)
+
+// The schemas below mirror pkg/types/flatbuffers/flat_types.fbs and have to be updated with it.
+
+// PlayerSize is the size of the Player struct: 4 ints and 4 floats.
+const PlayerSize = 32
+const RectSize = 16
+
+// EntitySize is the size of the Entity struct: 4 ints, 3 floats and the kind padded to 4.
+const EntitySize = 32
+
+var kind = Field{Name: "kind", Type: Scalar, Size: 1}
+
+var KindHolder = &Schema{Name: "KindHolder", Fields: []Field{kind}}
+
+var PlayerQuit = &Schema{Name: "PlayerQuit", Fields: []Field{
+	kind,
+	{Name: "id", Type: Scalar, Size: 4},
+}}
+
+var PlayerJoined = &Schema{Name: "PlayerJoined", Fields: []Field{
+	kind,
+	{Name: "player", Type: Struct, Size: PlayerSize, Required: true},
+}}
+
+var PlayerJoinedList = &Schema{Name: "PlayerJoinedList", Fields: []Field{
+	kind,
+	{Name: "players", Type: Vector, Size: PlayerSize},
+}}
+
+var PlayerHello = &Schema{Name: "PlayerHello", Fields: []Field{
+	kind,
+	{Name: "id", Type: Scalar, Size: 4},
+	{Name: "position_encodings", Type: Vector, Size: 1},
+	{Name: "fixed_point_scale", Type: Scalar, Size: 4},
+	{Name: "compressions", Type: Vector, Size: 1},
+	{Name: "dictionary_version", Type: Scalar, Size: 4},
+	{Name: "profile", Type: String},
+}}
+
+var PlayerHelloConfirm = &Schema{Name: "PlayerHelloConfirm", Fields: []Field{
+	kind,
+	{Name: "id", Type: Scalar, Size: 4},
+	{Name: "position_encoding", Type: Scalar, Size: 1},
+	{Name: "compression", Type: Scalar, Size: 1},
+	{Name: "profile", Type: String},
+	{Name: "dictionary_version", Type: Scalar, Size: 4},
+}}
+
+var PlayerMovedList = &Schema{Name: "PlayerMovedList", Fields: []Field{
+	kind,
+	{Name: "players", Type: Vector, Size: PlayerSize},
+}}
+
+var PlayerMoved = &Schema{Name: "PlayerMoved", Fields: []Field{
+	kind,
+	{Name: "player", Type: Struct, Size: PlayerSize, Required: true},
+}}
+
+var MapRegion = &Schema{Name: "MapRegion", Fields: []Field{
+	{Name: "name", Type: String},
+	{Name: "area", Type: Struct, Size: RectSize},
+}}
+
+var WorldMap = &Schema{Name: "WorldMap", Fields: []Field{
+	kind,
+	{Name: "name", Type: String},
+	{Name: "width", Type: Scalar, Size: 4},
+	{Name: "height", Type: Scalar, Size: 4},
+	{Name: "walls", Type: Vector, Size: RectSize},
+	{Name: "spawn_zones", Type: TableVector, Table: MapRegion},
+	{Name: "regions", Type: TableVector, Table: MapRegion},
+}}
+
+var TimeSync = &Schema{Name: "TimeSync", Fields: []Field{
+	kind,
+	{Name: "client_time", Type: Scalar, Size: 8},
+}}
+
+var TimeSyncReply = &Schema{Name: "TimeSyncReply", Fields: []Field{
+	kind,
+	{Name: "client_time", Type: Scalar, Size: 8},
+	{Name: "server_time", Type: Scalar, Size: 8},
+	{Name: "tick", Type: Scalar, Size: 4},
+}}
+
+var Ping = &Schema{Name: "Ping", Fields: []Field{
+	kind,
+	{Name: "seq", Type: Scalar, Size: 4},
+}}
+
+var Pong = &Schema{Name: "Pong", Fields: []Field{
+	kind,
+	{Name: "seq", Type: Scalar, Size: 4},
+}}
+
+var IdleWarning = &Schema{Name: "IdleWarning", Fields: []Field{
+	kind,
+	{Name: "action", Type: Scalar, Size: 1},
+	{Name: "seconds_left", Type: Scalar, Size: 4},
+}}
+
+var EntitySpawned = &Schema{Name: "EntitySpawned", Fields: []Field{
+	kind,
+	{Name: "entities", Type: Vector, Size: EntitySize},
+}}
+
+var EntityUpdated = &Schema{Name: "EntityUpdated", Fields: []Field{
+	kind,
+	{Name: "entities", Type: Vector, Size: EntitySize},
+}}
+
+var EntityDespawned = &Schema{Name: "EntityDespawned", Fields: []Field{
+	kind,
+	{Name: "ids", Type: Vector, Size: 4},
+}}
+
+var PlayerFire = &Schema{Name: "PlayerFire", Fields: []Field{
+	kind,
+	{Name: "dir_x", Type: Scalar, Size: 4},
+	{Name: "dir_y", Type: Scalar, Size: 4},
+}}
+
+var PlayerDamaged = &Schema{Name: "PlayerDamaged", Fields: []Field{
+	kind,
+	{Name: "id", Type: Scalar, Size: 4},
+	{Name: "attacker", Type: Scalar, Size: 4},
+	{Name: "damage", Type: Scalar, Size: 4},
+	{Name: "health", Type: Scalar, Size: 4},
+}}
+
+var PlayerDied = &Schema{Name: "PlayerDied", Fields: []Field{
+	kind,
+	{Name: "id", Type: Scalar, Size: 4},
+	{Name: "killer", Type: Scalar, Size: 4},
+	{Name: "respawn_in", Type: Scalar, Size: 4},
+}}
+
+var PlayerRespawned = &Schema{Name: "PlayerRespawned", Fields: []Field{
+	kind,
+	{Name: "player", Type: Struct, Size: PlayerSize, Required: true},
+	{Name: "health", Type: Scalar, Size: 4},
+}}
+
+var RawEvent = &Schema{Name: "RawEvent", Fields: []Field{
+	{Name: "raw_data", Type: Vector, Size: 1},
+}}
+
+var EventList = &Schema{Name: "EventList", Fields: []Field{
+	{Name: "events", Type: TableVector, Table: RawEvent},
+	{Name: "tick", Type: Scalar, Size: 4},
+	{Name: "server_time", Type: Scalar, Size: 8},
+	{Name: "compressed", Type: Vector, Size: 1},
+}}
+
+// Events has the schema of every event kind.
+var Events = map[flatgen.EventKind]*Schema{
+	flatgen.EventKindPlayerHello:        PlayerHello,
+	flatgen.EventKindPlayerQuit:         PlayerQuit,
+	flatgen.EventKindPlayerJoined:       PlayerJoined,
+	flatgen.EventKindPlayerJoinedList:   PlayerJoinedList,
+	flatgen.EventKindPlayerHelloConfirm: PlayerHelloConfirm,
+	flatgen.EventKindPlayerMovedList:    PlayerMovedList,
+	flatgen.EventKindPlayerMoved:        PlayerMoved,
+	flatgen.EventKindWorldMap:           WorldMap,
+	flatgen.EventKindTimeSync:           TimeSync,
+	flatgen.EventKindTimeSyncReply:      TimeSyncReply,
+	flatgen.EventKindPing:               Ping,
+	flatgen.EventKindPong:               Pong,
+	flatgen.EventKindIdleWarning:        IdleWarning,
+	flatgen.EventKindEntitySpawned:      EntitySpawned,
+	flatgen.EventKindEntityUpdated:      EntityUpdated,
+	flatgen.EventKindEntityDespawned:    EntityDespawned,
+	flatgen.EventKindPlayerFire:         PlayerFire,
+	flatgen.EventKindPlayerDamaged:      PlayerDamaged,
+	flatgen.EventKindPlayerDied:         PlayerDied,
+	flatgen.EventKindPlayerRespawned:    PlayerRespawned,
+}
+
+// Event verifies data as an event of the given kind.
+func Event(eventKind flatgen.EventKind, data []byte) error {
+	schema, ok := Events[eventKind]
+	if !ok {
+		return fmt.Errorf("no schema for event kind '%v'", eventKind)
+	}
+
+	return Root(data, schema)
+}
@@ -0,0 +1,182 @@
+// Package verify checks that a flatbuffer can be read without going out of bounds, before
+// any of the generated accessors touch it. The Go flatbuffers runtime has no verifier, so the
+// layout of every table is described by hand in schemas.go.
+package verify
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MaxDepth bounds how deep tables can be nested and MaxTables how many tables a buffer can
+// have, so a small malicious buffer can't make the verifier do a lot of work.
+var MaxDepth = 16
+var MaxTables = 1 << 16
+
+type FieldType int
+
+const (
+	// Scalar and Struct are stored inline in the table, Size bytes long.
+	Scalar FieldType = iota
+	Struct
+	String
+	// Vector is a vector of scalars or structs, Size bytes each.
+	Vector
+	Table
+	TableVector
+)
+
+// Field describes the field in the vtable slot matching its index in Schema.Fields, so the
+// fields have to be listed in the order they are declared in the .fbs file.
+type Field struct {
+	Name     string
+	Type     FieldType
+	Size     int
+	Table    *Schema
+	Required bool
+}
+
+type Schema struct {
+	Name   string
+	Fields []Field
+}
+
+// Root verifies a buffer whose root table is described by schema.
+func Root(buf []byte, schema *Schema) error {
+	v := &verifier{buf: buf}
+
+	if !v.inBounds(0, 4) {
+		return fmt.Errorf("%s: buffer of %d bytes is too small", schema.Name, len(buf))
+	}
+
+	return v.table(int64(v.uint32(0)), schema)
+}
+
+type verifier struct {
+	buf    []byte
+	depth  int
+	tables int
+}
+
+func (v *verifier) inBounds(pos, size int64) bool {
+	return pos >= 0 && size >= 0 && pos+size <= int64(len(v.buf))
+}
+
+func (v *verifier) uint32(pos int64) uint32 {
+	return binary.LittleEndian.Uint32(v.buf[pos:])
+}
+
+func (v *verifier) uint16(pos int64) uint16 {
+	return binary.LittleEndian.Uint16(v.buf[pos:])
+}
+
+func (v *verifier) table(pos int64, schema *Schema) error {
+	v.depth++
+	defer func() { v.depth-- }()
+
+	v.tables++
+
+	if v.depth > MaxDepth {
+		return fmt.Errorf("%s: tables are nested deeper than %d", schema.Name, MaxDepth)
+	}
+
+	if v.tables > MaxTables {
+		return fmt.Errorf("%s: more than %d tables", schema.Name, MaxTables)
+	}
+
+	if !v.inBounds(pos, 4) {
+		return fmt.Errorf("%s: table at %d is out of bounds", schema.Name, pos)
+	}
+
+	vtable := pos - int64(int32(v.uint32(pos)))
+	if !v.inBounds(vtable, 4) {
+		return fmt.Errorf("%s: vtable at %d is out of bounds", schema.Name, vtable)
+	}
+
+	vtableSize, tableSize := int64(v.uint16(vtable)), int64(v.uint16(vtable+2))
+
+	if vtableSize < 4 || vtableSize%2 != 0 || !v.inBounds(vtable, vtableSize) {
+		return fmt.Errorf("%s: bad vtable size %d", schema.Name, vtableSize)
+	}
+
+	if tableSize < 4 || !v.inBounds(pos, tableSize) {
+		return fmt.Errorf("%s: bad table size %d", schema.Name, tableSize)
+	}
+
+	for i, field := range schema.Fields {
+		slot := int64(4 + 2*i)
+
+		offset := int64(0)
+		if slot < vtableSize {
+			offset = int64(v.uint16(vtable + slot))
+		}
+
+		if offset == 0 {
+			if field.Required {
+				return fmt.Errorf("%s: required field '%s' is missing", schema.Name, field.Name)
+			}
+
+			continue
+		}
+
+		size := int64(4)
+		if field.Type == Scalar || field.Type == Struct {
+			size = int64(field.Size)
+		}
+
+		// Like the C++ verifier, fields only have to be inside the buffer. Our builders write the
+		// Player struct before starting the table, so it's outside of the size the vtable records.
+		fieldPos := pos + offset
+		if offset < 4 || !v.inBounds(fieldPos, size) {
+			return fmt.Errorf("%s: field '%s' is out of bounds", schema.Name, field.Name)
+		}
+
+		var err error
+
+		switch field.Type {
+		case String:
+			_, _, err = v.vector(fieldPos, 1, true)
+		case Vector:
+			_, _, err = v.vector(fieldPos, int64(field.Size), false)
+		case Table:
+			err = v.table(fieldPos+int64(v.uint32(fieldPos)), field.Table)
+		case TableVector:
+			var start, length int64
+
+			start, length, err = v.vector(fieldPos, 4, false)
+
+			for j := int64(0); err == nil && j < length; j++ {
+				elem := start + 4*j
+				err = v.table(elem+int64(v.uint32(elem)), field.Table)
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", schema.Name, field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// vector checks the vector the offset at pos points to and returns where its elements start.
+func (v *verifier) vector(pos, elemSize int64, isString bool) (start, length int64, err error) {
+	target := pos + int64(v.uint32(pos))
+	if !v.inBounds(target, 4) {
+		return 0, 0, fmt.Errorf("vector at %d is out of bounds", target)
+	}
+
+	length = int64(v.uint32(target))
+
+	byteLength := length * elemSize
+	if isString {
+		// strings are followed by a 0 byte
+		byteLength++
+	}
+
+	if !v.inBounds(target+4, byteLength) {
+		return 0, 0, fmt.Errorf("vector of %d elements at %d is out of bounds", length, target)
+	}
+
+	return target + 4, length, nil
+}
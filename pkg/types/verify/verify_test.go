@@ -0,0 +1,341 @@
+package verify_test
+
+import (
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/ecs"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types"
+	flatgen "github.com/laurentiuNiculae/multiplayer-game/pkg/types/flatgen/game"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/utils"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/types/verify"
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// validEvents has one event of every kind, built the way the server and the clients build them.
+func validEvents() map[flatgen.EventKind][]byte {
+	player := types.Player{Id: 7, X: 100, Y: 200, Speed: 250, InputX: 1, VX: 30}
+	players := []types.Player{player, {Id: 8, X: 1, Y: 2}}
+	entities := []ecs.State{{Id: 9, Kind: flatgen.EntityKindProjectile, Owner: 7, X: 5, Y: 6, VX: 300, Size: 4}, {Id: 10}}
+
+	newBuilder := func() *flatbuffers.Builder { return flatbuffers.NewBuilder(64) }
+
+	return map[flatgen.EventKind][]byte{
+		flatgen.EventKindPlayerHello:        utils.NewFlatPlayerHello(newBuilder(), player, []flatgen.PositionEncoding{flatgen.PositionEncodingFixedPoint}, 100, []flatgen.Compression{flatgen.CompressionDeflate}, 1, "profile").Table().Bytes,
+		flatgen.EventKindPlayerQuit:         utils.NewFlatPlayerQuit(newBuilder(), 7).Table().Bytes,
+		flatgen.EventKindPlayerJoined:       utils.NewFlatPlayerJoined(newBuilder(), player, types.IntegerCodec).Table().Bytes,
+		flatgen.EventKindPlayerJoinedList:   utils.NewFlatPlayerJoinedList(newBuilder(), players, types.IntegerCodec).Table().Bytes,
+		flatgen.EventKindPlayerHelloConfirm: utils.NewFlatPlayerHelloConfirm(newBuilder(), 7, flatgen.PositionEncodingFixedPoint, flatgen.CompressionDeflate, 1, "profile").Table().Bytes,
+		flatgen.EventKindPlayerMovedList:    utils.NewFlatPlayerMovedList(newBuilder(), players, types.IntegerCodec).Table().Bytes,
+		flatgen.EventKindPlayerMoved:        utils.NewFlatPlayerMoved(newBuilder(), player, types.IntegerCodec).Table().Bytes,
+		flatgen.EventKindWorldMap:           utils.NewFlatWorldMap(newBuilder(), world.NewEmptyMap(1600, 1200)).Table().Bytes,
+		flatgen.EventKindTimeSync:           utils.NewFlatTimeSync(newBuilder(), 1234.5).Table().Bytes,
+		flatgen.EventKindTimeSyncReply:      utils.NewFlatTimeSyncReply(newBuilder(), 1234.5, 99, 3).Table().Bytes,
+		flatgen.EventKindPing:               utils.NewFlatPing(newBuilder(), 3).Table().Bytes,
+		flatgen.EventKindPong:               utils.NewFlatPong(newBuilder(), 3).Table().Bytes,
+		flatgen.EventKindIdleWarning:        utils.NewFlatIdleWarning(newBuilder(), flatgen.IdleActionKick, 30).Table().Bytes,
+		flatgen.EventKindEntitySpawned:      utils.NewFlatEntitySpawned(newBuilder(), entities, types.IntegerCodec).Table().Bytes,
+		flatgen.EventKindEntityUpdated:      utils.NewFlatEntityUpdated(newBuilder(), entities, types.IntegerCodec).Table().Bytes,
+		flatgen.EventKindEntityDespawned:    utils.NewFlatEntityDespawned(newBuilder(), []int{9, 10}).Table().Bytes,
+		flatgen.EventKindPlayerFire:         utils.NewFlatPlayerFire(newBuilder(), 0.6, -0.8).Table().Bytes,
+		flatgen.EventKindPlayerDamaged:      utils.NewFlatPlayerDamaged(newBuilder(), 7, 8, 20, 80).Table().Bytes,
+		flatgen.EventKindPlayerDied:         utils.NewFlatPlayerDied(newBuilder(), 7, 8, 3).Table().Bytes,
+		flatgen.EventKindPlayerRespawned:    utils.NewFlatPlayerRespawned(newBuilder(), player, 100, types.IntegerCodec).Table().Bytes,
+	}
+}
+
+func validEventList(events map[flatgen.EventKind][]byte) []byte {
+	builder := flatbuffers.NewBuilder(1024)
+
+	rawEvents := []flatbuffers.UOffsetT{}
+	for _, event := range events {
+		rawData := builder.CreateByteVector(event)
+
+		flatgen.RawEventStart(builder)
+		flatgen.RawEventAddRawData(builder, rawData)
+		rawEvents = append(rawEvents, flatgen.RawEventEnd(builder))
+	}
+
+	flatgen.EventListStartEventsVector(builder, len(rawEvents))
+	for i := len(rawEvents) - 1; i >= 0; i-- {
+		builder.PrependUOffsetT(rawEvents[i])
+	}
+	eventsVector := builder.EndVector(len(rawEvents))
+
+	flatgen.EventListStart(builder)
+	flatgen.EventListAddEvents(builder, eventsVector)
+	flatgen.EventListAddTick(builder, 12)
+	flatgen.EventListAddServerTime(builder, 3400)
+	builder.Finish(flatgen.EventListEnd(builder))
+
+	return builder.FinishedBytes()
+}
+
+// rootOf reads data as the root table of the event kind with the generated accessors, without
+// the recover of dispatch.Registry.Parse in the way.
+func rootOf(kind flatgen.EventKind, data []byte) any {
+	switch kind {
+	case flatgen.EventKindPlayerHello:
+		return flatgen.GetRootAsPlayerHello(data, 0)
+	case flatgen.EventKindPlayerQuit:
+		return flatgen.GetRootAsPlayerQuit(data, 0)
+	case flatgen.EventKindPlayerJoined:
+		return flatgen.GetRootAsPlayerJoined(data, 0)
+	case flatgen.EventKindPlayerJoinedList:
+		return flatgen.GetRootAsPlayerJoinedList(data, 0)
+	case flatgen.EventKindPlayerHelloConfirm:
+		return flatgen.GetRootAsPlayerHelloConfirm(data, 0)
+	case flatgen.EventKindPlayerMovedList:
+		return flatgen.GetRootAsPlayerMovedList(data, 0)
+	case flatgen.EventKindPlayerMoved:
+		return flatgen.GetRootAsPlayerMoved(data, 0)
+	case flatgen.EventKindWorldMap:
+		return flatgen.GetRootAsWorldMap(data, 0)
+	case flatgen.EventKindTimeSync:
+		return flatgen.GetRootAsTimeSync(data, 0)
+	case flatgen.EventKindTimeSyncReply:
+		return flatgen.GetRootAsTimeSyncReply(data, 0)
+	case flatgen.EventKindPing:
+		return flatgen.GetRootAsPing(data, 0)
+	case flatgen.EventKindPong:
+		return flatgen.GetRootAsPong(data, 0)
+	case flatgen.EventKindIdleWarning:
+		return flatgen.GetRootAsIdleWarning(data, 0)
+	case flatgen.EventKindEntitySpawned:
+		return flatgen.GetRootAsEntitySpawned(data, 0)
+	case flatgen.EventKindEntityUpdated:
+		return flatgen.GetRootAsEntityUpdated(data, 0)
+	case flatgen.EventKindEntityDespawned:
+		return flatgen.GetRootAsEntityDespawned(data, 0)
+	case flatgen.EventKindPlayerFire:
+		return flatgen.GetRootAsPlayerFire(data, 0)
+	case flatgen.EventKindPlayerDamaged:
+		return flatgen.GetRootAsPlayerDamaged(data, 0)
+	case flatgen.EventKindPlayerDied:
+		return flatgen.GetRootAsPlayerDied(data, 0)
+	case flatgen.EventKindPlayerRespawned:
+		return flatgen.GetRootAsPlayerRespawned(data, 0)
+	default:
+		return nil
+	}
+}
+
+// readEvent calls every accessor of a decoded event, it panics if one goes out of bounds.
+func readEvent(data any) {
+	player, entity := &flatgen.Player{}, &flatgen.Entity{}
+
+	readEntity := func(entity *flatgen.Entity) {
+		_, _, _, _ = entity.Id(), entity.Kind(), entity.Owner(), entity.X()
+		_, _, _, _ = entity.Y(), entity.Vx(), entity.Vy(), entity.Size()
+	}
+
+	readPlayer := func(player *flatgen.Player) {
+		if player != nil {
+			_, _, _, _ = player.Id(), player.X(), player.Y(), player.Speed()
+			_, _, _, _ = player.InputX(), player.InputY(), player.Vx(), player.Vy()
+		}
+	}
+
+	switch event := data.(type) {
+	case *flatgen.PlayerHello:
+		_, _, _, _ = event.Kind(), event.Id(), event.FixedPointScale(), event.DictionaryVersion()
+		event.Profile()
+		for i := range event.PositionEncodingsLength() {
+			event.PositionEncodings(i)
+		}
+	case *flatgen.PlayerQuit:
+		_, _ = event.Kind(), event.Id()
+	case *flatgen.PlayerJoined:
+		event.Kind()
+		readPlayer(event.Player(player))
+	case *flatgen.PlayerJoinedList:
+		event.Kind()
+		for i := range event.PlayersLength() {
+			event.Players(player, i)
+			readPlayer(player)
+		}
+	case *flatgen.PlayerHelloConfirm:
+		_, _, _, _ = event.Kind(), event.Id(), event.PositionEncoding(), event.DictionaryVersion()
+	case *flatgen.PlayerMovedList:
+		event.Kind()
+		for i := range event.PlayersLength() {
+			event.Players(player, i)
+			readPlayer(player)
+		}
+	case *flatgen.PlayerMoved:
+		event.Kind()
+		readPlayer(event.Player(player))
+	case *flatgen.WorldMap:
+		_, _, _, _ = event.Kind(), event.Name(), event.Width(), event.Height()
+
+		rect, region := &flatgen.Rect{}, &flatgen.MapRegion{}
+		for i := range event.WallsLength() {
+			event.Walls(rect, i)
+			_, _, _, _ = rect.X(), rect.Y(), rect.Width(), rect.Height()
+		}
+		for i := range event.SpawnZonesLength() {
+			event.SpawnZones(region, i)
+			region.Name()
+			region.Area(rect)
+		}
+		for i := range event.RegionsLength() {
+			event.Regions(region, i)
+			region.Name()
+			region.Area(rect)
+		}
+	case *flatgen.TimeSync:
+		_, _ = event.Kind(), event.ClientTime()
+	case *flatgen.TimeSyncReply:
+		_, _, _, _ = event.Kind(), event.ClientTime(), event.ServerTime(), event.Tick()
+	case *flatgen.Ping:
+		_, _ = event.Kind(), event.Seq()
+	case *flatgen.Pong:
+		_, _ = event.Kind(), event.Seq()
+	case *flatgen.IdleWarning:
+		_, _, _ = event.Kind(), event.Action(), event.SecondsLeft()
+	case *flatgen.EntitySpawned:
+		event.Kind()
+		for i := range event.EntitiesLength() {
+			event.Entities(entity, i)
+			readEntity(entity)
+		}
+	case *flatgen.EntityUpdated:
+		event.Kind()
+		for i := range event.EntitiesLength() {
+			event.Entities(entity, i)
+			readEntity(entity)
+		}
+	case *flatgen.EntityDespawned:
+		event.Kind()
+		for i := range event.IdsLength() {
+			event.Ids(i)
+		}
+	case *flatgen.PlayerFire:
+		_, _, _ = event.Kind(), event.DirX(), event.DirY()
+	case *flatgen.PlayerDamaged:
+		_, _, _, _ = event.Kind(), event.Id(), event.Attacker(), event.Damage()
+		event.Health()
+	case *flatgen.PlayerDied:
+		_, _, _, _ = event.Kind(), event.Id(), event.Killer(), event.RespawnIn()
+	case *flatgen.PlayerRespawned:
+		_, _ = event.Kind(), event.Health()
+		readPlayer(event.Player(player))
+	}
+}
+
+// readEventList reads every event of a verified EventList, and the events that verify too.
+func readEventList(data []byte) {
+	eventList := flatgen.GetRootAsEventList(data, 0)
+	_, _ = eventList.Tick(), eventList.ServerTime()
+
+	rawEvent := &flatgen.RawEvent{}
+	for i := range eventList.EventsLength() {
+		eventList.Events(rawEvent, i)
+		raw := rawEvent.RawDataBytes()
+
+		if verify.Root(raw, verify.KindHolder) != nil {
+			continue
+		}
+
+		kind := flatgen.GetRootAsKindHolder(raw, 0).Kind()
+		if verify.Event(kind, raw) == nil {
+			readEvent(rootOf(kind, raw))
+		}
+	}
+}
+
+func TestValidEvents(t *testing.T) {
+	events := validEvents()
+
+	for kind := range flatgen.EnumNamesEventKind {
+		if kind == flatgen.EventKindNilEvent {
+			continue
+		}
+
+		data, ok := events[kind]
+		if !ok {
+			t.Errorf("no valid event of kind '%v' to test with", kind)
+			continue
+		}
+
+		if err := verify.Event(kind, data); err != nil {
+			t.Errorf("valid '%v' didn't verify: %v", kind, err)
+		}
+
+		if rootOf(kind, data) == nil {
+			t.Errorf("rootOf doesn't read '%v', the fuzzers would skip it", kind)
+		}
+	}
+
+	if err := verify.Root(validEventList(events), verify.EventList); err != nil {
+		t.Errorf("valid EventList didn't verify: %v", err)
+	}
+}
+
+func TestTruncatedEvents(t *testing.T) {
+	for kind, data := range validEvents() {
+		for end := range len(data) {
+			truncated := data[:end]
+
+			if err := verify.Event(kind, truncated); err == nil {
+				readEvent(rootOf(kind, truncated))
+			}
+		}
+	}
+}
+
+func TestMissingRequiredField(t *testing.T) {
+	builder := flatbuffers.NewBuilder(64)
+	flatgen.PlayerMovedStart(builder)
+	flatgen.PlayerMovedAddKind(builder, flatgen.EventKindPlayerMoved)
+	builder.Finish(flatgen.PlayerMovedEnd(builder))
+
+	if err := verify.Event(flatgen.EventKindPlayerMoved, builder.FinishedBytes()); err == nil {
+		t.Errorf("expected an error for a PlayerMoved without a player")
+	}
+}
+
+func TestTooManyTables(t *testing.T) {
+	defer func(maxTables int) { verify.MaxTables = maxTables }(verify.MaxTables)
+
+	verify.MaxTables = 4
+
+	if err := verify.Root(validEventList(validEvents()), verify.EventList); err == nil {
+		t.Errorf("expected an error for an EventList with more than %d tables", verify.MaxTables)
+	}
+}
+
+// FuzzEvent verifies the data as an event of the kind and reads what passes with every
+// accessor. Nothing recovers in between, so a panic in the verifier or in an accessor of a
+// verified event fails the fuzzer.
+func FuzzEvent(f *testing.F) {
+	for kind, data := range validEvents() {
+		f.Add(uint8(kind), data)
+	}
+
+	f.Fuzz(func(t *testing.T, kind uint8, data []byte) {
+		if err := verify.Event(flatgen.EventKind(kind), data); err != nil {
+			return
+		}
+
+		readEvent(rootOf(flatgen.EventKind(kind), data))
+	})
+}
+
+func FuzzEventList(f *testing.F) {
+	events := validEvents()
+
+	f.Add(validEventList(events))
+	for kind, data := range events {
+		f.Add(validEventList(map[flatgen.EventKind][]byte{kind: data}))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := verify.Root(data, verify.EventList); err != nil {
+			return
+		}
+
+		readEventList(data)
+	})
+}
@@ -0,0 +1,174 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type Rect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+func (r Rect) Intersects(other Rect) bool {
+	return r.X < other.X+other.Width && other.X < r.X+r.Width &&
+		r.Y < other.Y+other.Height && other.Y < r.Y+r.Height
+}
+
+func (r Rect) Center() (float64, float64) {
+	return r.X + r.Width/2, r.Y + r.Height/2
+}
+
+// IntersectsSegment tells if the segment from (x0, y0) to (x1, y1) goes through the rect and
+// how far along it, from 0 to 1, it enters.
+func (r Rect) IntersectsSegment(x0, y0, x1, y1 float64) (float64, bool) {
+	enter, exit := 0.0, 1.0
+
+	for _, axis := range [2][4]float64{{x0, x1 - x0, r.X, r.X + r.Width}, {y0, y1 - y0, r.Y, r.Y + r.Height}} {
+		start, delta, low, high := axis[0], axis[1], axis[2], axis[3]
+
+		if delta == 0 {
+			if start < low || start >= high {
+				return 0, false
+			}
+
+			continue
+		}
+
+		t0, t1 := (low-start)/delta, (high-start)/delta
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+
+		enter, exit = max(enter, t0), min(exit, t1)
+		if enter > exit {
+			return 0, false
+		}
+	}
+
+	return enter, true
+}
+
+// Region is a named area of the map, used both for spawn zones and for
+// gameplay regions that level designers want to refer to by name.
+type Region struct {
+	Name string `json:"name"`
+	Area Rect   `json:"area"`
+}
+
+type Map struct {
+	Name       string   `json:"name"`
+	Width      float64  `json:"width"`
+	Height     float64  `json:"height"`
+	Walls      []Rect   `json:"walls"`
+	SpawnZones []Region `json:"spawnZones"`
+	Regions    []Region `json:"regions"`
+}
+
+// NewEmptyMap returns a map with no walls and a single spawn zone in the
+// center quarter, which is how the world looked before maps existed.
+func NewEmptyMap(width, height float64) *Map {
+	return &Map{
+		Name:   "empty",
+		Width:  width,
+		Height: height,
+		SpawnZones: []Region{
+			{Name: "center", Area: Rect{X: width / 2, Y: height / 2, Width: width / 4, Height: height / 4}},
+		},
+	}
+}
+
+func Load(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Map, error) {
+	worldMap := &Map{}
+
+	err := json.Unmarshal(data, worldMap)
+	if err != nil {
+		return nil, err
+	}
+
+	err = worldMap.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return worldMap, nil
+}
+
+func (m *Map) Validate() error {
+	if m.Width <= 0 || m.Height <= 0 {
+		return fmt.Errorf("map '%s' has invalid bounds %vx%v", m.Name, m.Width, m.Height)
+	}
+
+	bounds := m.Bounds()
+
+	for _, zone := range m.SpawnZones {
+		if zone.Area.Width <= 0 || zone.Area.Height <= 0 {
+			return fmt.Errorf("spawn zone '%s' has an empty area", zone.Name)
+		}
+
+		if !bounds.Contains(zone.Area.X, zone.Area.Y) ||
+			zone.Area.X+zone.Area.Width > m.Width || zone.Area.Y+zone.Area.Height > m.Height {
+			return fmt.Errorf("spawn zone '%s' is outside the map bounds", zone.Name)
+		}
+	}
+
+	return nil
+}
+
+func (m *Map) Bounds() Rect {
+	return Rect{Width: m.Width, Height: m.Height}
+}
+
+// Blocked reports if a box of the given size placed at (x, y) leaves the map
+// or overlaps any wall.
+func (m *Map) Blocked(x, y, size float64) bool {
+	if x < 0 || y < 0 || x+size > m.Width || y+size > m.Height {
+		return true
+	}
+
+	box := Rect{X: x, Y: y, Width: size, Height: size}
+
+	for _, wall := range m.Walls {
+		if wall.Intersects(box) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Map) RegionAt(x, y float64) (Region, bool) {
+	for _, region := range m.Regions {
+		if region.Area.Contains(x, y) {
+			return region, true
+		}
+	}
+
+	return Region{}, false
+}
+
+func (m *Map) SpawnZone(name string) (Region, bool) {
+	for _, zone := range m.SpawnZones {
+		if zone.Name == name {
+			return zone, true
+		}
+	}
+
+	return Region{}, false
+}
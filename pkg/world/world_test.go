@@ -0,0 +1,98 @@
+package world_test
+
+import (
+	"testing"
+
+	"github.com/laurentiuNiculae/multiplayer-game/pkg/world"
+)
+
+func TestParse(t *testing.T) {
+	worldMap, err := world.Parse([]byte(`{
+		"name": "test",
+		"width": 100,
+		"height": 50,
+		"walls": [{"x": 40, "y": 0, "width": 10, "height": 50}],
+		"spawnZones": [{"name": "left", "area": {"x": 0, "y": 0, "width": 20, "height": 20}}],
+		"regions": [{"name": "right", "area": {"x": 50, "y": 0, "width": 50, "height": 50}}]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if worldMap.Name != "test" || worldMap.Width != 100 || worldMap.Height != 50 {
+		t.Fatalf("unexpected map header: %+v", worldMap)
+	}
+
+	if !worldMap.Blocked(35, 10, 8) {
+		t.Errorf("expected the wall to block a player touching it")
+	}
+
+	if worldMap.Blocked(10, 10, 8) {
+		t.Errorf("expected free space to not be blocked")
+	}
+
+	if !worldMap.Blocked(95, 10, 8) {
+		t.Errorf("expected the map edge to block")
+	}
+
+	region, ok := worldMap.RegionAt(60, 10)
+	if !ok || region.Name != "right" {
+		t.Errorf("expected region 'right', got %v %v", region, ok)
+	}
+
+	if _, ok := worldMap.SpawnZone("left"); !ok {
+		t.Errorf("expected spawn zone 'left'")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	invalidMaps := []string{
+		`{"name": "no-bounds"}`,
+		`{"name": "zone-outside", "width": 10, "height": 10, "spawnZones": [{"name": "out", "area": {"x": 5, "y": 5, "width": 10, "height": 10}}]}`,
+		`{"name": "empty-zone", "width": 10, "height": 10, "spawnZones": [{"name": "empty", "area": {"x": 5, "y": 5}}]}`,
+		`not json`,
+	}
+
+	for _, data := range invalidMaps {
+		if _, err := world.Parse([]byte(data)); err == nil {
+			t.Errorf("expected error for map %s", data)
+		}
+	}
+}
+
+func TestLoadDefaultMap(t *testing.T) {
+	_, err := world.Load("../../maps/default.json")
+	if err != nil {
+		t.Fatalf("default map should be valid: %v", err)
+	}
+}
+
+func TestRectIntersectsSegment(t *testing.T) {
+	rect := world.Rect{X: 10, Y: 10, Width: 10, Height: 10}
+
+	testCases := []struct {
+		name           string
+		x0, y0, x1, y1 float64
+		hit            bool
+		enter          float64
+	}{
+		{"through it", 0, 15, 40, 15, true, 0.25},
+		{"backwards", 40, 15, 0, 15, true, 0.5},
+		{"diagonal", 0, 0, 20, 20, true, 0.5},
+		{"starting inside", 15, 15, 100, 15, true, 0},
+		{"stopping short", 0, 15, 9, 15, false, 0},
+		{"passing above", 0, 5, 40, 5, false, 0},
+		{"standing still outside", 5, 5, 5, 5, false, 0},
+		{"missing a corner", 0, 15, 15, 0, false, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			enter, hit := rect.IntersectsSegment(tc.x0, tc.y0, tc.x1, tc.y1)
+
+			if hit != tc.hit || enter != tc.enter {
+				t.Fatalf("expected %v at %v, got %v at %v", tc.hit, tc.enter, hit, enter)
+			}
+		})
+	}
+}